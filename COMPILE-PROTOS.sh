@@ -19,6 +19,7 @@ go install github.com/golang/protobuf/protoc-gen-go@latest
 
 protoc -I . -I ./third_party --go_out=. --go_opt=paths=source_relative openapiv2/*.proto
 protoc -I . -I ./third_party --go_out=. --go_opt=paths=source_relative openapiv3/*.proto
+protoc -I . -I ./third_party --go_out=. --go_opt=paths=source_relative openapiv31/*.proto
 protoc -I . -I ./third_party --go_out=. --go_opt=paths=source_relative discovery/*.proto
 protoc -I . -I ./third_party --go_out=. --go_opt=paths=source_relative plugins/*.proto
 protoc -I . -I ./third_party --go_out=. --go_opt=paths=source_relative extensions/*.proto
@@ -0,0 +1,379 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundler combines API descriptions that are split across multiple
+// files into single self-contained documents. Each externally-referenced
+// fragment is copied into the reusable components of the bundled document
+// and the references to it are rewritten as local JSON pointers.
+// Bundled documents can also be dereferenced by replacing each reference
+// with a copy of the content that it refers to.
+package bundler
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// openAPI3Sections and openAPI2Sections map the names of fields that contain
+// references to the component sections that hold the referenced fragments.
+// Fragments referenced from anywhere else are bundled as schemas.
+var openAPI3Sections = map[string]string{
+	"parameters":      "parameters",
+	"responses":       "responses",
+	"requestBody":     "requestBodies",
+	"requestBodies":   "requestBodies",
+	"headers":         "headers",
+	"examples":        "examples",
+	"links":           "links",
+	"callbacks":       "callbacks",
+	"securitySchemes": "securitySchemes",
+}
+
+var openAPI2Sections = map[string]string{
+	"parameters": "parameters",
+	"responses":  "responses",
+}
+
+// bundler holds the state of a bundling operation.
+type bundler struct {
+	// root is the top-level node of the bundled document.
+	root *yaml.Node
+	// rootFile is the name of the file containing the bundled document.
+	rootFile string
+	// openAPI3 is true when bundling an OpenAPI v3 document.
+	openAPI3 bool
+	// refs maps the absolute locations of bundled fragments to their local references.
+	refs map[string]string
+	// names records the names used in each component section.
+	names map[string]map[string]bool
+	// session reads the referenced files.
+	session *compiler.Session
+}
+
+// BundleFile reads an OpenAPI v2 or v3 document and any files that it
+// references and returns a single document containing all of their contents.
+func BundleFile(filename string) (*yaml.Node, error) {
+	return BundleFileWithSession(compiler.DefaultSession(), filename)
+}
+
+// BundleFileWithSession is like BundleFile but reads files with the specified session.
+func BundleFileWithSession(session *compiler.Session, filename string) (*yaml.Node, error) {
+	bytes, err := session.ReadBytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	info, err := session.ReadInfoFromBytes(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	return BundleWithSession(session, info, filename)
+}
+
+// Bundle returns a copy of an OpenAPI v2 or v3 document that was read from
+// the named file with the contents of all the files that it references.
+func Bundle(info *yaml.Node, filename string) (*yaml.Node, error) {
+	return BundleWithSession(compiler.DefaultSession(), info, filename)
+}
+
+// BundleWithSession is like Bundle but reads referenced files with the specified session.
+func BundleWithSession(session *compiler.Session, info *yaml.Node, filename string) (*yaml.Node, error) {
+	// Copy the document so that the original is left unchanged.
+	info = compiler.CopyNode(info)
+	root := info
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("bundling requires an OpenAPI v2 or v3 document")
+	}
+	b := &bundler{
+		root:     root,
+		rootFile: compiler.ResolveURI("", filename),
+		refs:     make(map[string]string),
+		names:    make(map[string]map[string]bool),
+		session:  session,
+	}
+	switch {
+	case compiler.MapHasKey(root, "openapi"):
+		b.openAPI3 = true
+	case compiler.MapHasKey(root, "swagger"):
+		b.openAPI3 = false
+	default:
+		return nil, errors.New("bundling requires an OpenAPI v2 or v3 document")
+	}
+	// Reserve the names of components that are already in the document.
+	for _, section := range b.sectionNames() {
+		if m := b.sectionNode(section, false); m != nil {
+			for i := 0; i < len(m.Content); i += 2 {
+				b.reserve(section, m.Content[i].Value)
+			}
+		}
+	}
+	if err := b.inlineComponents(); err != nil {
+		return nil, err
+	}
+	if err := b.bundle(root, nil, b.rootFile); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// inlineComponents replaces components that refer to other files with
+// the contents of those files. All of these references are recorded before
+// any are replaced so that other references to the same files use the
+// existing components.
+func (b *bundler) inlineComponents() error {
+	type component struct {
+		node     *yaml.Node
+		keys     []string
+		filename string
+		fragment string
+	}
+	var components []component
+	for _, section := range b.sectionNames() {
+		m := b.sectionNode(section, false)
+		if m == nil {
+			continue
+		}
+		for i := 0; i < len(m.Content)-1; i += 2 {
+			name, value := m.Content[i].Value, m.Content[i+1]
+			ref := refForNode(value)
+			if ref == "" || strings.HasPrefix(ref, "#") {
+				continue
+			}
+			filename, fragment := b.locate(ref, b.rootFile)
+			keys := compiler.KeysWith(b.sectionPath(section), name)
+			b.refs[filename+"#"+fragment] = "#/" + strings.Join(b.sectionPath(section), "/") + "/" + compiler.EscapePointerToken(name)
+			components = append(components, component{node: value, keys: keys, filename: filename, fragment: fragment})
+		}
+	}
+	for _, c := range components {
+		if err := b.inline(c.node, c.keys, c.filename, c.fragment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inline replaces a node with the bundled contents of a fragment of another file.
+func (b *bundler) inline(node *yaml.Node, keys []string, filename, fragment string) error {
+	content, err := b.readFragment(filename, fragment)
+	if err != nil {
+		return err
+	}
+	content = compiler.CopyNode(content)
+	if err := b.bundle(content, keys, filename); err != nil {
+		return err
+	}
+	*node = *content
+	return nil
+}
+
+// locate returns the file and fragment identified by a reference found in the named file.
+func (b *bundler) locate(ref, filename string) (string, string) {
+	parts := strings.SplitN(ref, "#", 2)
+	fragment := ""
+	if len(parts) == 2 {
+		fragment = parts[1]
+	}
+	if parts[0] != "" {
+		filename = compiler.ResolveURI(filename, parts[0])
+	}
+	return filename, fragment
+}
+
+// sectionNames returns the names of all sections that can hold bundled fragments.
+func (b *bundler) sectionNames() []string {
+	if b.openAPI3 {
+		return []string{"schemas", "parameters", "responses", "requestBodies",
+			"headers", "examples", "links", "callbacks", "securitySchemes"}
+	}
+	return []string{"definitions", "parameters", "responses"}
+}
+
+// sectionPath returns the keys that lead to a section from the document root.
+func (b *bundler) sectionPath(section string) []string {
+	if b.openAPI3 {
+		return []string{"components", section}
+	}
+	return []string{section}
+}
+
+// sectionForKeys returns the section that should hold a fragment that
+// is referenced from the location described by a list of keys.
+func (b *bundler) sectionForKeys(keys []string) string {
+	n := len(keys)
+	sections := openAPI2Sections
+	defaultSection := "definitions"
+	if b.openAPI3 {
+		sections = openAPI3Sections
+		defaultSection = "schemas"
+	}
+	// Keys that name schema properties don't identify sections.
+	if n >= 1 && keys[n-1] == "requestBody" && (n < 2 || keys[n-2] != "properties") {
+		if section, ok := sections[keys[n-1]]; ok {
+			return section
+		}
+	}
+	if n >= 2 && (n < 3 || keys[n-3] != "properties") {
+		if section, ok := sections[keys[n-2]]; ok {
+			return section
+		}
+	}
+	return defaultSection
+}
+
+// sectionNode returns the mapping node of a section, optionally creating it.
+func (b *bundler) sectionNode(section string, create bool) *yaml.Node {
+	m := b.root
+	for _, key := range b.sectionPath(section) {
+		value := compiler.MapValueForKey(m, key)
+		if value == nil {
+			if !create {
+				return nil
+			}
+			value = compiler.NewMappingNode()
+			m.Content = append(m.Content, compiler.NewScalarNodeForString(key), value)
+		}
+		m = value
+	}
+	return m
+}
+
+func (b *bundler) reserve(section, name string) {
+	if b.names[section] == nil {
+		b.names[section] = make(map[string]bool)
+	}
+	b.names[section][name] = true
+}
+
+// uniqueName returns a name that is not yet used in a section.
+func (b *bundler) uniqueName(section, name string) string {
+	if name == "" {
+		name = "Component"
+	}
+	candidate := name
+	for i := 2; b.names[section][candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	b.reserve(section, candidate)
+	return candidate
+}
+
+// bundle rewrites the references in a node that was read from the named file.
+func (b *bundler) bundle(node *yaml.Node, keys []string, filename string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := b.bundle(child, keys, filename); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := b.bundle(child, compiler.KeysWith(keys, strconv.Itoa(i)), filename); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		// There is no section for path items, so they are bundled in place.
+		if ref := refForNode(node); len(keys) == 2 && keys[0] == "paths" && ref != "" {
+			if target, fragment := b.locate(ref, filename); target != b.rootFile {
+				return b.inline(node, keys, target, fragment)
+			}
+		}
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "$ref" && value.Kind == yaml.ScalarNode {
+				ref, err := b.localRef(value.Value, keys, filename)
+				if err != nil {
+					return err
+				}
+				value.Value = ref
+				continue
+			}
+			if err := b.bundle(value, compiler.KeysWith(keys, key.Value), filename); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// localRef returns a local reference for a reference found in the named file,
+// copying the referenced fragment into the bundled document when necessary.
+func (b *bundler) localRef(ref string, keys []string, filename string) (string, error) {
+	target, fragment := b.locate(ref, filename)
+	if target == b.rootFile {
+		// References within the root document are already local.
+		return "#" + fragment, nil
+	}
+	location := target + "#" + fragment
+	if localRef, ok := b.refs[location]; ok {
+		return localRef, nil
+	}
+	node, err := b.readFragment(target, fragment)
+	if err != nil {
+		return "", err
+	}
+	section := b.sectionForKeys(keys)
+	name := b.uniqueName(section, nameForLocation(target, fragment))
+	localRef := "#/" + strings.Join(b.sectionPath(section), "/") + "/" + compiler.EscapePointerToken(name)
+	// Record the reference before bundling the fragment so that cycles terminate.
+	b.refs[location] = localRef
+	node = compiler.CopyNode(node)
+	if err := b.bundle(node, compiler.KeysWith(b.sectionPath(section), name), target); err != nil {
+		return "", err
+	}
+	m := b.sectionNode(section, true)
+	m.Content = append(m.Content, compiler.NewScalarNodeForString(name), node)
+	return localRef, nil
+}
+
+// readFragment reads a file and returns the node identified by a JSON pointer.
+func (b *bundler) readFragment(filename, fragment string) (*yaml.Node, error) {
+	bytes, err := b.session.ReadBytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	node, err := b.session.ReadInfoFromBytes(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	result, err := compiler.NodeForPointer(node, compiler.PointerForFragment(fragment))
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %s#%s", filename, fragment)
+	}
+	return result, nil
+}
+
+// nameForLocation derives a component name from the location of a fragment.
+func nameForLocation(filename, fragment string) string {
+	if fragment != "" {
+		tokens := strings.Split(compiler.PointerForFragment(fragment), "/")
+		if name := compiler.UnescapePointerToken(tokens[len(tokens)-1]); name != "" {
+			return name
+		}
+	}
+	base := path.Base(filepath.ToSlash(filename))
+	return strings.TrimSuffix(base, path.Ext(base))
+}
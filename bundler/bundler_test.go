@@ -0,0 +1,216 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+const recursiveDocument = `
+openapi: 3.0.0
+info:
+  title: Trees
+  version: 1.0.0
+paths:
+  /trees:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/depth'
+      responses:
+        '200':
+          description: A tree.
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Tree'
+components:
+  parameters:
+    depth:
+      name: depth
+      in: query
+      schema:
+        type: integer
+  schemas:
+    Tree:
+      type: object
+      properties:
+        label:
+          $ref: '#/components/schemas/Label'
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Tree'
+    Label:
+      type: string
+`
+
+func TestDereferenceRecursiveSchema(t *testing.T) {
+	document, err := openapi3.ParseDocument([]byte(recursiveDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	result, messages, err := DereferenceOpenAPIv3(document, "recursive.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	operation := result.Paths.Path[0].Value.Get
+	if operation.Parameters[0].GetParameter().GetName() != "depth" {
+		t.Errorf("parameter was not dereferenced")
+	}
+	schema := operation.Responses.ResponseOrReference[0].Value.GetResponse().
+		Content.AdditionalProperties[0].Value.Schema.GetSchema()
+	if schema == nil {
+		t.Fatalf("response schema was not dereferenced")
+	}
+	properties := schema.Properties.AdditionalProperties
+	if properties[0].Value.GetSchema().GetType() != "string" {
+		t.Errorf("label property was not dereferenced")
+	}
+	items := properties[1].Value.GetSchema().Items.SchemaOrReference[0]
+	if items.GetReference().GetXRef() != "#/components/schemas/Tree" {
+		t.Errorf("recursive reference was not preserved: %+v", items)
+	}
+	// One recursive reference is found in the response and one in the component.
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d: %+v", len(messages), messages)
+	}
+	var keys []string
+	for _, message := range messages {
+		if message.Level != plugins.Message_INFO || message.Code != RecursiveReference {
+			t.Errorf("unexpected message: %+v", message)
+		}
+		keys = append(keys, strings.Join(message.Keys, "/"))
+	}
+	if !compiler.StringArrayContainsValue(keys, "paths//trees/get/responses/200/content/application/json/schema/properties/children/items") {
+		t.Errorf("unexpected keys: %+v", keys)
+	}
+}
+
+func TestNameCollisions(t *testing.T) {
+	b := &bundler{names: make(map[string]map[string]bool)}
+	b.reserve("schemas", "Error")
+	for _, want := range []string{"Error2", "Error3"} {
+		if got := b.uniqueName("schemas", "Error"); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]*yaml.Node) {
+	for name, node := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		bytes, err := yaml.Marshal(node)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if err := ioutil.WriteFile(filename, bytes, 0644); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+}
+
+func testSplit(t *testing.T, filename string, expectedFiles []string) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	files, err := SplitOpenAPIv3(document)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, name := range expectedFiles {
+		if files[name] == nil {
+			t.Errorf("missing file %s", name)
+		}
+	}
+	dir, err := ioutil.TempDir("", "split")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, files)
+	// Bundling the split files should reproduce the original document.
+	info, err := BundleFile(filepath.Join(dir, RootFile))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	root := info.Content[0]
+	bundled, err := openapi3.NewDocument(root, compiler.NewContext("$root", root, nil))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !proto.Equal(document, bundled) {
+		t.Errorf("bundled document differs from the original")
+	}
+}
+
+func TestSplitPetstore(t *testing.T) {
+	testSplit(t, "../examples/v3.0/yaml/petstore.yaml", []string{
+		"openapi.yaml",
+		"paths/pets.yaml",
+		"components/schemas/Pet.yaml",
+		"components/schemas/Pets.yaml",
+		"components/schemas/Error.yaml",
+	})
+}
+
+func TestSplitComponents(t *testing.T) {
+	testSplit(t, "../testdata/bundle/openapi-bundled.yaml", []string{
+		"openapi.yaml",
+		"paths/pets.yaml",
+		"components/schemas/Error2.yaml",
+		"components/parameters.yaml",
+		"components/responses.yaml",
+		"components/requestBodies.yaml",
+	})
+}
+
+func TestSplitFileNames(t *testing.T) {
+	s := &splitter{names: make(map[string]map[string]bool)}
+	for _, test := range []struct{ name, want string }{
+		{"Pet", "Pet"},
+		{"pet", "pet_2"},
+		{"a/b c", "a_b_c"},
+		{".hidden", "_.hidden"},
+	} {
+		if got := s.fileName("schemas", test.name); got != test.want {
+			t.Errorf("expected %s, got %s", test.want, got)
+		}
+	}
+	if got := groupForPath("/{id}/items"); got != "id" {
+		t.Errorf("unexpected group %s", got)
+	}
+	if got := relativePath("components/schemas", "paths/pets.yaml"); got != "../../paths/pets.yaml" {
+		t.Errorf("unexpected relative path %s", got)
+	}
+}
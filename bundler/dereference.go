@@ -0,0 +1,158 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// RecursiveReference is the code of messages that report references
+// that were not dereferenced because they refer to one of their ancestors.
+const RecursiveReference = "RECURSIVEREFERENCE"
+
+// dereferencer holds the state of a dereferencing operation.
+type dereferencer struct {
+	// source is an unmodified copy of the document being dereferenced.
+	source *yaml.Node
+	// messages reports recursive references.
+	messages []*plugins.Message
+}
+
+// Dereference returns a copy of an OpenAPI v2 or v3 document that was read
+// from the named file with every reference replaced by the content that it
+// refers to. Recursive references are left in place and reported as INFO messages.
+func Dereference(info *yaml.Node, filename string) (*yaml.Node, []*plugins.Message, error) {
+	return DereferenceWithSession(compiler.DefaultSession(), info, filename)
+}
+
+// DereferenceWithSession is like Dereference but reads referenced files with the specified session.
+func DereferenceWithSession(session *compiler.Session, info *yaml.Node, filename string) (*yaml.Node, []*plugins.Message, error) {
+	info, err := BundleWithSession(session, info, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := info
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	d := &dereferencer{source: compiler.CopyNode(root)}
+	if err := d.dereference(root, "", nil, nil); err != nil {
+		return nil, nil, err
+	}
+	return info, d.messages, nil
+}
+
+// DereferenceOpenAPIv3 returns a copy of an OpenAPI v3 document with every reference
+// replaced by the object that it refers to.
+func DereferenceOpenAPIv3(document *openapi3.Document, sourceName string) (*openapi3.Document, []*plugins.Message, error) {
+	return DereferenceOpenAPIv3WithSession(compiler.DefaultSession(), document, sourceName)
+}
+
+// DereferenceOpenAPIv3WithSession is like DereferenceOpenAPIv3 but reads referenced files with the specified session.
+func DereferenceOpenAPIv3WithSession(session *compiler.Session, document *openapi3.Document, sourceName string) (*openapi3.Document, []*plugins.Message, error) {
+	info, messages, err := DereferenceWithSession(session, document.ToRawInfo(), sourceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := openapi3.NewDocument(info, compiler.NewContextWithSession("$root", info, session, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, messages, nil
+}
+
+// DereferenceOpenAPIv2 returns a copy of an OpenAPI v2 document with every reference
+// replaced by the object that it refers to.
+func DereferenceOpenAPIv2(document *openapi2.Document, sourceName string) (*openapi2.Document, []*plugins.Message, error) {
+	return DereferenceOpenAPIv2WithSession(compiler.DefaultSession(), document, sourceName)
+}
+
+// DereferenceOpenAPIv2WithSession is like DereferenceOpenAPIv2 but reads referenced files with the specified session.
+func DereferenceOpenAPIv2WithSession(session *compiler.Session, document *openapi2.Document, sourceName string) (*openapi2.Document, []*plugins.Message, error) {
+	info, messages, err := DereferenceWithSession(session, document.ToRawInfo(), sourceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := openapi2.NewDocument(info, compiler.NewContextWithSession("$root", info, session, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, messages, nil
+}
+
+// dereference replaces the references in a node. The pointer identifies the
+// location of the node's content in the source document, keys describe the
+// location of the node in the result, and stack holds the source locations
+// of the node's ancestors.
+func (d *dereferencer) dereference(node *yaml.Node, pointer string, keys []string, stack []string) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			index := strconv.Itoa(i)
+			if err := d.dereference(child, pointer+"/"+index, compiler.KeysWith(keys, index), append(stack, pointer)); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		if ref := refForNode(node); strings.HasPrefix(ref, "#") {
+			target := compiler.PointerForFragment(ref[1:])
+			if target == pointer || compiler.StringArrayContainsValue(stack, target) {
+				d.messages = append(d.messages, &plugins.Message{
+					Level: plugins.Message_INFO,
+					Code:  RecursiveReference,
+					Text:  "Recursive reference to " + ref + " was not dereferenced.",
+					Keys:  keys,
+				})
+				return nil
+			}
+			content, err := compiler.NodeForPointer(d.source, target)
+			if err != nil {
+				return fmt.Errorf("could not resolve %s", ref)
+			}
+			replacement := compiler.CopyNode(content)
+			if err := d.dereference(replacement, target, keys, append(stack, pointer)); err != nil {
+				return err
+			}
+			*node = *replacement
+			return nil
+		}
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if err := d.dereference(node.Content[i+1], pointer+"/"+compiler.EscapePointerToken(key), compiler.KeysWith(keys, key), append(stack, pointer)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refForNode returns the value of a node's $ref field or the empty string if it has none.
+func refForNode(node *yaml.Node) string {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
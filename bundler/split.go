@@ -0,0 +1,200 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundler
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+// RootFile is the name of the root file written by SplitOpenAPIv3.
+const RootFile = "openapi.yaml"
+
+// location identifies a fragment of a file.
+type location struct {
+	filename string
+	fragment string
+}
+
+// splitter holds the state of a splitting operation.
+type splitter struct {
+	// files holds the contents of the files being written.
+	files map[string]*yaml.Node
+	// locations maps the local references of components to their new locations.
+	locations map[string]location
+	// names records the file names used in each directory, ignoring case.
+	names map[string]map[string]bool
+}
+
+// SplitOpenAPIv3 divides an OpenAPI v3 document into multiple files and returns
+// their contents keyed by their slash-separated relative paths. Paths are grouped
+// into files under "paths" by their first segment, each schema is written to its
+// own file under "components/schemas", and each other component section is
+// written to a file under "components". The root file refers to all of these.
+func SplitOpenAPIv3(document *openapi3.Document) (map[string]*yaml.Node, error) {
+	root := document.ToRawInfo()
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("invalid document")
+	}
+	s := &splitter{
+		files:     map[string]*yaml.Node{RootFile: root},
+		locations: make(map[string]location),
+		names:     make(map[string]map[string]bool),
+	}
+	components := compiler.MapValueForKey(root, "components")
+	// Choose locations for all components before any references are rewritten.
+	compiler.ForEachEntry(components, func(section string, entries *yaml.Node) {
+		compiler.ForEachEntry(entries, func(name string, value *yaml.Node) {
+			var l location
+			if section == "schemas" {
+				l.filename = "components/schemas/" + s.fileName("components/schemas", name) + ".yaml"
+			} else {
+				l.filename = "components/" + section + ".yaml"
+				l.fragment = "/" + compiler.EscapePointerToken(name)
+			}
+			s.locations["#/components/"+compiler.EscapePointerToken(section)+"/"+compiler.EscapePointerToken(name)] = l
+		})
+	})
+	compiler.ForEachEntry(components, func(section string, entries *yaml.Node) {
+		compiler.ForEachEntry(entries, func(name string, value *yaml.Node) {
+			l := s.locations["#/components/"+compiler.EscapePointerToken(section)+"/"+compiler.EscapePointerToken(name)]
+			s.move(value, l)
+		})
+	})
+	compiler.ForEachEntry(compiler.MapValueForKey(root, "paths"), func(name string, value *yaml.Node) {
+		s.move(value, location{
+			filename: "paths/" + groupForPath(name) + ".yaml",
+			fragment: "/" + compiler.EscapePointerToken(name),
+		})
+	})
+	return s.files, nil
+}
+
+// move copies a node to a new location and replaces it with a reference to that location.
+func (s *splitter) move(node *yaml.Node, l location) {
+	content := compiler.CopyNode(node)
+	s.rewriteRefs(content, l.filename)
+	if l.fragment == "" {
+		s.files[l.filename] = content
+	} else {
+		file := s.files[l.filename]
+		if file == nil {
+			file = compiler.NewMappingNode()
+			s.files[l.filename] = file
+		}
+		key := compiler.UnescapePointerToken(strings.TrimPrefix(l.fragment, "/"))
+		file.Content = append(file.Content, compiler.NewScalarNodeForString(key), content)
+	}
+	*node = *compiler.NewMappingNode()
+	node.Content = append(node.Content,
+		compiler.NewScalarNodeForString("$ref"),
+		compiler.NewScalarNodeForString(refForLocation(RootFile, l)))
+}
+
+// rewriteRefs rewrites the local references in a node that is moved to the named file.
+func (s *splitter) rewriteRefs(node *yaml.Node, filename string) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			s.rewriteRefs(child, filename)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "$ref" && value.Kind == yaml.ScalarNode && strings.HasPrefix(value.Value, "#") {
+				value.Value = refForLocation(filename, s.locationForRef(value.Value))
+			} else {
+				s.rewriteRefs(value, filename)
+			}
+		}
+	}
+}
+
+// locationForRef returns the new location of the target of a local reference.
+func (s *splitter) locationForRef(ref string) location {
+	for prefix, l := range s.locations {
+		if ref == prefix || strings.HasPrefix(ref, prefix+"/") {
+			return location{filename: l.filename, fragment: l.fragment + strings.TrimPrefix(ref, prefix)}
+		}
+	}
+	return location{filename: RootFile, fragment: strings.TrimPrefix(ref, "#")}
+}
+
+// refForLocation returns a reference from the named file to a location.
+func refForLocation(filename string, l location) string {
+	ref := ""
+	if l.filename != filename {
+		ref = relativePath(path.Dir(filename), l.filename)
+	}
+	if l.fragment != "" || ref == "" {
+		ref += "#" + l.fragment
+	}
+	return ref
+}
+
+// relativePath returns a slash-separated path to target from a directory.
+func relativePath(dir, target string) string {
+	if dir == "." {
+		return target
+	}
+	from := strings.Split(dir, "/")
+	to := strings.Split(target, "/")
+	i := 0
+	for i < len(from) && i < len(to)-1 && from[i] == to[i] {
+		i++
+	}
+	return strings.Repeat("../", len(from)-i) + strings.Join(to[i:], "/")
+}
+
+var unsafeFileNameCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fileName returns a name for a file in a directory that is safe to use
+// on all filesystems and that differs from the names of the directory's
+// other files by more than letter case.
+func (s *splitter) fileName(dir, name string) string {
+	name = unsafeFileNameCharacters.ReplaceAllString(name, "_")
+	if name == "" || strings.HasPrefix(name, ".") {
+		name = "_" + name
+	}
+	if s.names[dir] == nil {
+		s.names[dir] = make(map[string]bool)
+	}
+	candidate := name
+	for i := 2; s.names[dir][strings.ToLower(candidate)]; i++ {
+		candidate = name + "_" + strconv.Itoa(i)
+	}
+	s.names[dir][strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// groupForPath returns the name of the file that holds a path,
+// which is derived from the path's first segment.
+func groupForPath(p string) string {
+	segment := strings.Split(strings.TrimPrefix(p, "/"), "/")[0]
+	segment = strings.Trim(segment, "{}")
+	segment = unsafeFileNameCharacters.ReplaceAllString(segment, "_")
+	if segment == "" || strings.HasPrefix(segment, ".") {
+		return "root"
+	}
+	return segment
+}
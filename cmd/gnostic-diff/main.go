@@ -0,0 +1,143 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-diff compares two versions of an OpenAPI description and
+// reports the changes between them, exiting with a nonzero status
+// when any of the changes would break existing clients.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/diff"
+	"github.com/google/gnostic/printer"
+
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// readDocument reads an OpenAPI v2 or v3 document from a JSON, YAML, or
+// binary protocol buffer file and returns it as a protocol buffer message.
+func readDocument(filename string) (proto.Message, error) {
+	bytes, err := compiler.ReadBytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(filename) == ".pb" {
+		d3 := &openapi3.Document{}
+		if err := proto.Unmarshal(bytes, d3); err == nil && d3.Openapi != "" {
+			return d3, nil
+		}
+		d2 := &openapi2.Document{}
+		if err := proto.Unmarshal(bytes, d2); err == nil && d2.Swagger != "" {
+			return d2, nil
+		}
+		return nil, fmt.Errorf("%s is not a binary OpenAPI description", filename)
+	}
+	info, err := compiler.ReadInfoFromBytes(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+		info = info.Content[0]
+	}
+	switch {
+	case compiler.MapHasKey(info, "openapi"):
+		return openapi3.NewDocument(info, compiler.NewContext("$root", info, nil))
+	case compiler.MapHasKey(info, "swagger"):
+		return openapi2.NewDocument(info, compiler.NewContext("$root", info, nil))
+	default:
+		return nil, fmt.Errorf("%s is not an OpenAPI description", filename)
+	}
+}
+
+// compareDocuments compares two documents of the same OpenAPI version.
+func compareDocuments(before, after proto.Message) (*plugins.Messages, error) {
+	switch before := before.(type) {
+	case *openapi3.Document:
+		if after, ok := after.(*openapi3.Document); ok {
+			return diff.CompareOpenAPIv3(before, after), nil
+		}
+	case *openapi2.Document:
+		if after, ok := after.(*openapi2.Document); ok {
+			return diff.CompareOpenAPIv2(before, after)
+		}
+	}
+	return nil, errors.New("documents must use the same OpenAPI version")
+}
+
+func printMessages(code *printer.Code, messages *plugins.Messages) {
+	for _, message := range messages.Messages {
+		code.Print(message.Report())
+	}
+}
+
+func main() {
+	messagesOut := flag.String("messages-out", "", "write messages to a binary protocol buffer file")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gnostic-diff [--messages-out=PATH] BEFORE AFTER\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var documents []proto.Message
+	for _, filename := range args {
+		document, err := readDocument(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(2)
+		}
+		documents = append(documents, document)
+	}
+	messages, err := compareDocuments(documents[0], documents[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(2)
+	}
+
+	if *messagesOut != "" {
+		bytes, err := proto.Marshal(messages)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(2)
+		}
+		if err := ioutil.WriteFile(*messagesOut, bytes, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(2)
+		}
+	} else {
+		code := &printer.Code{}
+		printMessages(code, messages)
+		fmt.Printf("%s", code)
+	}
+
+	if diff.HasBreakingChanges(messages) {
+		os.Exit(1)
+	}
+}
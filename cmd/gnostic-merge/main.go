@@ -0,0 +1,158 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-merge combines several OpenAPI v3 descriptions into one,
+// exiting with a nonzero status when the sources conflict.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/jsonwriter"
+	"github.com/google/gnostic/merge"
+	"github.com/google/gnostic/printer"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// readDocument reads an OpenAPI v3 document from a JSON, YAML, or binary protocol buffer file.
+func readDocument(filename string) (*openapi3.Document, error) {
+	bytes, err := compiler.ReadBytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(filename) == ".pb" {
+		document := &openapi3.Document{}
+		if err := proto.Unmarshal(bytes, document); err != nil {
+			return nil, err
+		}
+		return document, nil
+	}
+	info, err := compiler.ReadInfoFromBytes(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+		info = info.Content[0]
+	}
+	if !compiler.MapHasKey(info, "openapi") {
+		return nil, fmt.Errorf("%s is not an OpenAPI v3 description", filename)
+	}
+	return openapi3.NewDocument(info, compiler.NewContext("$root", info, nil))
+}
+
+// readSource reads a source that is specified as FILE or PREFIX=FILE.
+// Sources are named after their files.
+func readSource(arg string) (*merge.Source, error) {
+	source := &merge.Source{}
+	filename := arg
+	if strings.HasPrefix(arg, "/") {
+		if parts := strings.SplitN(arg, "=", 2); len(parts) == 2 {
+			source.PathPrefix, filename = parts[0], parts[1]
+		}
+	}
+	base := filepath.Base(filename)
+	source.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	document, err := readDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+	source.Document = document
+	return source, nil
+}
+
+func printMessages(code *printer.Code, messages []*plugins.Message) {
+	for _, message := range messages {
+		code.Print(message.Report())
+	}
+}
+
+func main() {
+	out := flag.String("out", "-", "write the merged description to a file instead of stdout; JSON is written for paths ending in \".json\"")
+	messagesOut := flag.String("messages-out", "", "write messages to a binary protocol buffer file")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gnostic-merge [--out=PATH] [--messages-out=PATH] [PREFIX=]SOURCE...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var sources []*merge.Source
+	for _, arg := range args {
+		source, err := readSource(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(2)
+		}
+		sources = append(sources, source)
+	}
+	document, messages, err := merge.MergeOpenAPIv3(sources)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(2)
+	}
+
+	var bytes []byte
+	if strings.HasSuffix(*out, ".json") {
+		bytes, err = jsonwriter.Marshal(document.ToRawInfo())
+	} else {
+		bytes, err = yaml.Marshal(document.ToRawInfo())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(2)
+	}
+	if *out == "-" {
+		os.Stdout.Write(bytes)
+	} else if err := ioutil.WriteFile(*out, bytes, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(2)
+	}
+
+	if *messagesOut != "" {
+		bytes, err := proto.Marshal(&plugins.Messages{Messages: messages})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(2)
+		}
+		if err := ioutil.WriteFile(*messagesOut, bytes, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(2)
+		}
+	} else {
+		code := &printer.Code{}
+		printMessages(code, messages)
+		fmt.Fprintf(os.Stderr, "%s", code)
+	}
+
+	for _, message := range messages {
+		if message.Level == plugins.Message_ERROR {
+			os.Exit(1)
+		}
+	}
+}
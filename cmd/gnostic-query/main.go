@@ -0,0 +1,141 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-query prints the parts of API descriptions that are selected by a
+// JSONPath query, exiting with a nonzero status when nothing is selected.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	discovery "github.com/google/gnostic/discovery"
+	"github.com/google/gnostic/jsonpath"
+
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	openapi31 "github.com/google/gnostic/openapiv31"
+)
+
+// readInfo reads a JSON or YAML file. If compile is true, the file is compiled
+// as an API description and the compiled document is returned.
+func readInfo(filename string, compile bool) (*yaml.Node, error) {
+	bytes, err := compiler.ReadBytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	info, err := compiler.ReadInfoFromBytes(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if !compile {
+		return info, nil
+	}
+	if info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+		info = info.Content[0]
+	}
+	context := compiler.NewContext("$root", info, nil)
+	openapi, _ := compiler.StringForScalarNode(compiler.MapValueForKey(info, "openapi"))
+	kind, _ := compiler.StringForScalarNode(compiler.MapValueForKey(info, "kind"))
+	switch {
+	case compiler.MapHasKey(info, "swagger"):
+		document, err := openapi2.NewDocument(info, context)
+		if err != nil {
+			return nil, err
+		}
+		return document.ToRawInfo(), nil
+	case strings.HasPrefix(openapi, "3.1"):
+		document, err := openapi31.NewDocument(info, context)
+		if err != nil {
+			return nil, err
+		}
+		return document.ToRawInfo(), nil
+	case openapi != "":
+		document, err := openapi3.NewDocument(info, context)
+		if err != nil {
+			return nil, err
+		}
+		return document.ToRawInfo(), nil
+	case kind == "discovery#restDescription":
+		document, err := discovery.NewDocument(info, context)
+		if err != nil {
+			return nil, err
+		}
+		return document.ToRawInfo(), nil
+	}
+	return nil, fmt.Errorf("%s is not an API description", filename)
+}
+
+// location describes the location of a result.
+func location(filename string, r *jsonpath.Result) string {
+	if r.Line == 0 {
+		return filename + ": " + r.NormalizedPath()
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", filename, r.Line, r.Column, r.NormalizedPath())
+}
+
+// indent indents each line of text.
+func indent(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	return "  " + strings.Join(lines, "\n  ") + "\n"
+}
+
+func main() {
+	compile := flag.Bool("compiled", false, "query the compiled documents instead of the source files")
+	pathsOnly := flag.Bool("paths", false, "print the locations of selected nodes but not their values")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gnostic-query [--compiled] [--paths] QUERY SOURCE...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path, err := jsonpath.Parse(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(2)
+	}
+	selected := false
+	for _, filename := range args[1:] {
+		info, err := readInfo(filename, *compile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(2)
+		}
+		for _, r := range path.Query(info) {
+			selected = true
+			fmt.Println(location(filename, r))
+			if *pathsOnly {
+				continue
+			}
+			bytes, err := yaml.Marshal(r.Node)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%+v\n", err)
+				os.Exit(2)
+			}
+			fmt.Print(indent(string(bytes)))
+		}
+	}
+	if !selected {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-split divides an OpenAPI v3 description into a directory of
+// smaller files that can be edited independently.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/bundler"
+	"github.com/google/gnostic/compiler"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+// readDocument reads an OpenAPI v3 document from a JSON, YAML, or binary protocol buffer file.
+func readDocument(filename string) (*openapi3.Document, error) {
+	bytes, err := compiler.ReadBytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(filename) == ".pb" {
+		document := &openapi3.Document{}
+		if err := proto.Unmarshal(bytes, document); err != nil {
+			return nil, err
+		}
+		return document, nil
+	}
+	info, err := compiler.ReadInfoFromBytes(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+		info = info.Content[0]
+	}
+	if !compiler.MapHasKey(info, "openapi") {
+		return nil, fmt.Errorf("%s is not an OpenAPI v3 description", filename)
+	}
+	return openapi3.NewDocument(info, compiler.NewContext("$root", info, nil))
+}
+
+// writeFiles writes files below a directory, creating subdirectories as needed.
+func writeFiles(dir string, files map[string]*yaml.Node) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		bytes, err := yaml.Marshal(files[name])
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, bytes, 0644); err != nil {
+			return err
+		}
+		fmt.Println(filename)
+	}
+	return nil
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gnostic-split SOURCE OUTPUT_DIRECTORY\n")
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	document, err := readDocument(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+	files, err := bundler.SplitOpenAPIv3(document)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+	if err := writeFiles(args[1], files); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
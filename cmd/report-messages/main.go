@@ -44,26 +44,32 @@ func readMessagesFromFileWithName(filename string) *plugins.Messages {
 
 func printMessages(code *printer.Code, messages *plugins.Messages) {
 	for _, message := range messages.Messages {
-		line := fmt.Sprintf("%-7s %-14s %s %+v",
-			message.Level,
-			message.Code,
-			message.Text,
-			message.Keys)
-		code.Print(line)
+		code.Print(message.Report())
 	}
 }
 
 func main() {
+	format := flag.String("format", plugins.MessagesFormatText, "Output format: text, json, sarif, junit, or github.")
 	flag.Parse()
 	args := flag.Args()
 
 	if len(args) != 1 {
-		fmt.Printf("Usage: report-messages <file.pb>\n")
+		fmt.Printf("Usage: report-messages [-format=FORMAT] <file.pb>\n")
 		return
 	}
 
 	messages := readMessagesFromFileWithName(args[0])
 
+	if *format != plugins.MessagesFormatText {
+		bytes, err := plugins.FormatMessages(messages.Messages, *format, args[0])
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(bytes)
+		return
+	}
+
 	code := &printer.Code{}
 	printMessages(code, messages)
 	fmt.Printf("%s", code)
@@ -24,21 +24,80 @@ type Context struct {
 	Name              string
 	Node              *yaml.Node
 	ExtensionHandlers *[]ExtensionHandler
+	Session           *Session
+	// BaseURI is the location of the document that contains Node, if it
+	// differs from the location of the parent's document.
+	BaseURI string
 }
 
 // NewContextWithExtensions returns a new object representing the compiler state
 func NewContextWithExtensions(name string, node *yaml.Node, parent *Context, extensionHandlers *[]ExtensionHandler) *Context {
-	return &Context{Name: name, Node: node, Parent: parent, ExtensionHandlers: extensionHandlers}
+	context := &Context{Name: name, Node: node, Parent: parent, ExtensionHandlers: extensionHandlers}
+	if parent != nil {
+		context.Session = parent.Session
+	}
+	return context
 }
 
 // NewContext returns a new object representing the compiler state
 func NewContext(name string, node *yaml.Node, parent *Context) *Context {
 	if parent != nil {
-		return &Context{Name: name, Node: node, Parent: parent, ExtensionHandlers: parent.ExtensionHandlers}
+		return &Context{Name: name, Node: node, Parent: parent, ExtensionHandlers: parent.ExtensionHandlers, Session: parent.Session}
 	}
 	return &Context{Name: name, Parent: parent, ExtensionHandlers: nil}
 }
 
+// NewContextWithSession returns a new object representing the state of
+// a compiler that reads files using the specified session.
+func NewContextWithSession(name string, node *yaml.Node, session *Session, extensionHandlers *[]ExtensionHandler) *Context {
+	return &Context{Name: name, Node: node, ExtensionHandlers: extensionHandlers, Session: session}
+}
+
+// NewContextWithBaseURI returns a new object representing the state of the
+// compiler as it enters a document that was read from the specified location.
+func NewContextWithBaseURI(name string, node *yaml.Node, parent *Context, baseURI string) *Context {
+	context := NewContextWithExtensions(name, node, parent, nil)
+	if parent != nil {
+		context.ExtensionHandlers = parent.ExtensionHandlers
+	}
+	context.BaseURI = baseURI
+	return context
+}
+
+// GetBaseURI returns the location of the document being compiled.
+// Contexts form a stack of nested documents, so this is the base URI of the
+// nearest context that has one.
+func (context *Context) GetBaseURI() string {
+	for c := context; c != nil; c = c.Parent {
+		if c.BaseURI != "" {
+			return c.BaseURI
+		}
+	}
+	return ""
+}
+
+// ReadInfoForRef reads the fragment identified by a $ref found in this context.
+// It also returns a new context for the fragment whose base URI is the location
+// of the document that contains it.
+func (context *Context) ReadInfoForRef(ref string) (*yaml.Node, *Context, error) {
+	base := context.GetBaseURI()
+	info, err := context.GetSession().ReadInfoForRef(base, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	location, _ := splitFragment(ResolveURI(base, ref))
+	return info, NewContextWithBaseURI("$ref", info, context, location), nil
+}
+
+// GetSession returns the session used to read files in this context.
+// Contexts that aren't associated with a session use the default session.
+func (context *Context) GetSession() *Session {
+	if context == nil || context.Session == nil {
+		return defaultSession
+	}
+	return context.Session
+}
+
 // Description returns a text description of the compiler state
 func (context *Context) Description() string {
 	name := context.Name
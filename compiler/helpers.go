@@ -19,6 +19,7 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -63,6 +64,19 @@ func MapHasKey(m *yaml.Node, key string) bool {
 	return false
 }
 
+// ForEachEntry calls a function for each entry of a mapping node
+// that is not a specification extension.
+func ForEachEntry(m *yaml.Node, f func(key string, value *yaml.Node)) {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i < len(m.Content)-1; i += 2 {
+		if key := m.Content[i].Value; !strings.HasPrefix(key, "x-") {
+			f(key, m.Content[i+1])
+		}
+	}
+}
+
 // MapValueForKey gets the value of a map value for a specified key.
 func MapValueForKey(m *yaml.Node, key string) *yaml.Node {
 	if m == nil {
@@ -318,6 +332,22 @@ func NewScalarNodeForInt(i int64) *yaml.Node {
 	}
 }
 
+// CopyNode returns a deep copy of a node.
+func CopyNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	result := *node
+	if node.Content != nil {
+		result.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			result.Content[i] = CopyNode(child)
+		}
+	}
+	result.Alias = CopyNode(node.Alias)
+	return &result
+}
+
 // PluralProperties returns the string "properties" pluralized.
 func PluralProperties(count int) string {
 	if count == 1 {
@@ -346,6 +376,14 @@ func StringArrayContainsValues(array []string, values []string) bool {
 	return true
 }
 
+// KeysWith returns a copy of a list of keys with additional keys appended.
+// Keys describe paths to nodes, so copying keeps paths that share a prefix separate.
+func KeysWith(keys []string, more ...string) []string {
+	result := make([]string, 0, len(keys)+len(more))
+	result = append(result, keys...)
+	return append(result, more...)
+}
+
 // StringValue returns the string value of an item.
 func StringValue(item interface{}) (value string, ok bool) {
 	value, ok = item.(string)
@@ -0,0 +1,123 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Loader reads the contents of API descriptions and the files that they reference.
+// Locations are file names or URLs.
+type Loader interface {
+	Load(location string) ([]byte, error)
+}
+
+// FileLoader reads files from the local filesystem.
+type FileLoader struct{}
+
+// Load reads the named file.
+func (FileLoader) Load(location string) ([]byte, error) {
+	return ioutil.ReadFile(location)
+}
+
+// HTTPLoader fetches URLs with HTTP GET requests.
+type HTTPLoader struct {
+	// Client sends the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// Header holds additional headers, such as credentials, to send with each request.
+	Header http.Header
+}
+
+// Load fetches the contents of a URL.
+func (l *HTTPLoader) Load(location string) ([]byte, error) {
+	request, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range l.Header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Error downloading %s: %s", location, response.Status)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+// MapLoader reads the contents of files from a map keyed by location.
+// Locations that are not in the map are passed to Next if it is non-nil,
+// so a MapLoader can also overlay in-memory files on another Loader.
+type MapLoader struct {
+	Files map[string][]byte
+	Next  Loader
+}
+
+// Load returns the contents of a location.
+func (l *MapLoader) Load(location string) ([]byte, error) {
+	if bytes, ok := l.Files[location]; ok {
+		return bytes, nil
+	}
+	if l.Next != nil {
+		return l.Next.Load(location)
+	}
+	return nil, &os.PathError{Op: "open", Path: location, Err: os.ErrNotExist}
+}
+
+// URLLoader passes locations that are URLs to Remote and all others to Local.
+type URLLoader struct {
+	Remote Loader
+	Local  Loader
+}
+
+// Load reads the contents of a location.
+func (l *URLLoader) Load(location string) ([]byte, error) {
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" {
+		return l.Remote.Load(location)
+	}
+	return l.Local.Load(location)
+}
+
+// NewDefaultLoader returns the Loader that is used when no other is specified.
+// It fetches URLs with http.DefaultClient and reads all other locations from
+// the local filesystem.
+func NewDefaultLoader() Loader {
+	return &URLLoader{Remote: &HTTPLoader{}, Local: FileLoader{}}
+}
+
+// SetLoader sets the Loader used by the default session to read API descriptions
+// and the files that they reference. Passing nil restores the default Loader.
+func SetLoader(l Loader) {
+	defaultSession.SetLoader(l)
+}
+
+// GetLoader returns the Loader used by the default session to read API
+// descriptions and the files that they reference.
+func GetLoader() Loader {
+	return defaultSession.GetLoader()
+}
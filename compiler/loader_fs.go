@@ -0,0 +1,34 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.16
+// +build go1.16
+
+package compiler
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+)
+
+// FSLoader reads files from a file system such as an embed.FS.
+type FSLoader struct {
+	FS fs.FS
+}
+
+// Load reads the named file.
+func (l FSLoader) Load(location string) ([]byte, error) {
+	return fs.ReadFile(l.FS, path.Clean(filepath.ToSlash(location)))
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.16
+// +build go1.16
+
+package compiler
+
+import (
+	"testing/fstest"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *LoaderTestingSuite) TestFSLoader(c *check.C) {
+	loader := FSLoader{FS: fstest.MapFS{
+		"specs/api.yaml": &fstest.MapFile{Data: []byte("openapi: 3.0.0")},
+	}}
+	bytes, err := loader.Load("./specs/api.yaml")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(bytes), check.Equals, "openapi: 3.0.0")
+	_, err = loader.Load("specs/missing.yaml")
+	c.Assert(err, check.NotNil)
+}
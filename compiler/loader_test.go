@@ -0,0 +1,94 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"gopkg.in/check.v1"
+)
+
+type LoaderTestingSuite struct{}
+
+var _ = check.Suite(&LoaderTestingSuite{})
+
+func (s *LoaderTestingSuite) TearDownTest(c *check.C) {
+	SetLoader(nil)
+	ClearCaches()
+}
+
+func (s *LoaderTestingSuite) TestHTTPLoaderSendsHeaders(c *check.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, "openapi: 3.0.0")
+	}))
+	defer server.Close()
+
+	_, err := (&HTTPLoader{}).Load(server.URL + "/openapi.yaml")
+	c.Assert(err, check.NotNil)
+
+	loader := &HTTPLoader{
+		Client: server.Client(),
+		Header: http.Header{"Authorization": []string{"Bearer secret"}},
+	}
+	bytes, err := loader.Load(server.URL + "/openapi.yaml")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(bytes), check.Equals, "openapi: 3.0.0")
+
+	// Remote files are read with the current loader.
+	SetLoader(&URLLoader{Remote: loader, Local: FileLoader{}})
+	bytes, err = ReadBytesForFile(server.URL + "/openapi.yaml")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(bytes), check.Equals, "openapi: 3.0.0")
+}
+
+func (s *LoaderTestingSuite) TestMapLoader(c *check.C) {
+	loader := &MapLoader{Files: map[string][]byte{"memory.yaml": []byte("a: b")}}
+	bytes, err := loader.Load("memory.yaml")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(bytes), check.Equals, "a: b")
+	_, err = loader.Load("testdata/petstore.yaml")
+	c.Assert(os.IsNotExist(err), check.Equals, true)
+
+	// Files that are not in the map are read by the next loader.
+	loader.Next = FileLoader{}
+	bytes, err = loader.Load("testdata/petstore.yaml")
+	c.Assert(err, check.IsNil)
+	c.Assert(len(bytes) > 0, check.Equals, true)
+}
+
+func (s *LoaderTestingSuite) TestReadInfoForRefWithLoader(c *check.C) {
+	SetLoader(&MapLoader{Files: map[string][]byte{
+		"specs/api.yaml":  []byte("openapi: 3.0.0"),
+		"specs/Pet.yaml":  []byte("type: object"),
+		"specs/refs.yaml": []byte("Pet:\n  type: string"),
+	}})
+	info, err := ReadInfoForRef("specs/api.yaml", "Pet.yaml")
+	c.Assert(err, check.IsNil)
+	c.Assert(MapHasKey(info, "type"), check.Equals, true)
+	info, err = ReadInfoForRef("specs/api.yaml", "refs.yaml#/Pet")
+	c.Assert(err, check.IsNil)
+	value, ok := StringForScalarNode(MapValueForKey(info, "type"))
+	c.Assert(ok, check.Equals, true)
+	c.Assert(value, check.Equals, "string")
+	_, err = ReadInfoForRef("specs/api.yaml", "Missing.yaml")
+	c.Assert(err, check.NotNil)
+}
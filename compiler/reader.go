@@ -16,140 +16,125 @@ package compiler
 
 import (
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"net/url"
-	"path/filepath"
-	"strings"
-	"sync"
 
 	yaml "gopkg.in/yaml.v3"
 )
 
 var verboseReader = false
 
-var fileCache map[string][]byte
-var infoCache map[string]*yaml.Node
-
-var fileCacheEnable = true
-var infoCacheEnable = true
-
-// These locks are used to synchronize accesses to the fileCache and infoCache
-// maps (above). They are global state and can throw thread-related errors
-// when modified from separate goroutines. The general strategy is to protect
-// all public functions in this file with mutex Lock() calls. As a result, to
-// avoid deadlock, these public functions should not call other public
-// functions, so some public functions have private equivalents.
+// Caching is enabled by default in new sessions. The locks in each Session
+// synchronize accesses to its file and info caches. The general strategy
+// is to protect all public methods in this file with mutex Lock() calls.
+// As a result, to avoid deadlock, these public methods should not call
+// other public methods, so some public methods have private equivalents.
 // In the future, we might consider replacing the maps with sync.Map and
 // eliminating these mutexes.
-var fileCacheMutex sync.Mutex
-var infoCacheMutex sync.Mutex
 
-func initializeFileCache() {
-	if fileCache == nil {
-		fileCache = make(map[string][]byte, 0)
+func (s *Session) initializeFileCache() {
+	if s.fileCache == nil {
+		s.fileCache = make(map[string][]byte, 0)
 	}
 }
 
-func initializeInfoCache() {
-	if infoCache == nil {
-		infoCache = make(map[string]*yaml.Node, 0)
+func (s *Session) initializeInfoCache() {
+	if s.infoCache == nil {
+		s.infoCache = make(map[string]*yaml.Node, 0)
 	}
 }
 
 // EnableFileCache turns on file caching.
-func EnableFileCache() {
-	fileCacheMutex.Lock()
-	defer fileCacheMutex.Unlock()
-	fileCacheEnable = true
+func (s *Session) EnableFileCache() {
+	s.fileCacheMutex.Lock()
+	defer s.fileCacheMutex.Unlock()
+	s.fileCacheEnable = true
 }
 
 // EnableInfoCache turns on parsed info caching.
-func EnableInfoCache() {
-	infoCacheMutex.Lock()
-	defer infoCacheMutex.Unlock()
-	infoCacheEnable = true
+func (s *Session) EnableInfoCache() {
+	s.infoCacheMutex.Lock()
+	defer s.infoCacheMutex.Unlock()
+	s.infoCacheEnable = true
 }
 
 // DisableFileCache turns off file caching.
-func DisableFileCache() {
-	fileCacheMutex.Lock()
-	defer fileCacheMutex.Unlock()
-	fileCacheEnable = false
+func (s *Session) DisableFileCache() {
+	s.fileCacheMutex.Lock()
+	defer s.fileCacheMutex.Unlock()
+	s.fileCacheEnable = false
 }
 
 // DisableInfoCache turns off parsed info caching.
-func DisableInfoCache() {
-	infoCacheMutex.Lock()
-	defer infoCacheMutex.Unlock()
-	infoCacheEnable = false
+func (s *Session) DisableInfoCache() {
+	s.infoCacheMutex.Lock()
+	defer s.infoCacheMutex.Unlock()
+	s.infoCacheEnable = false
 }
 
 // RemoveFromFileCache removes an entry from the file cache.
-func RemoveFromFileCache(fileurl string) {
-	fileCacheMutex.Lock()
-	defer fileCacheMutex.Unlock()
-	if !fileCacheEnable {
+func (s *Session) RemoveFromFileCache(fileurl string) {
+	s.fileCacheMutex.Lock()
+	defer s.fileCacheMutex.Unlock()
+	if !s.fileCacheEnable {
 		return
 	}
-	initializeFileCache()
-	delete(fileCache, fileurl)
+	s.initializeFileCache()
+	delete(s.fileCache, fileurl)
 }
 
 // RemoveFromInfoCache removes an entry from the info cache.
-func RemoveFromInfoCache(filename string) {
-	infoCacheMutex.Lock()
-	defer infoCacheMutex.Unlock()
-	if !infoCacheEnable {
+func (s *Session) RemoveFromInfoCache(filename string) {
+	s.infoCacheMutex.Lock()
+	defer s.infoCacheMutex.Unlock()
+	if !s.infoCacheEnable {
 		return
 	}
-	initializeInfoCache()
-	delete(infoCache, filename)
+	s.initializeInfoCache()
+	delete(s.infoCache, filename)
 }
 
 // GetInfoCache returns the info cache map.
-func GetInfoCache() map[string]*yaml.Node {
-	infoCacheMutex.Lock()
-	defer infoCacheMutex.Unlock()
-	if infoCache == nil {
-		initializeInfoCache()
+func (s *Session) GetInfoCache() map[string]*yaml.Node {
+	s.infoCacheMutex.Lock()
+	defer s.infoCacheMutex.Unlock()
+	if s.infoCache == nil {
+		s.initializeInfoCache()
 	}
-	return infoCache
+	return s.infoCache
 }
 
 // ClearFileCache clears the file cache.
-func ClearFileCache() {
-	fileCacheMutex.Lock()
-	defer fileCacheMutex.Unlock()
-	fileCache = make(map[string][]byte, 0)
+func (s *Session) ClearFileCache() {
+	s.fileCacheMutex.Lock()
+	defer s.fileCacheMutex.Unlock()
+	s.fileCache = make(map[string][]byte, 0)
 }
 
 // ClearInfoCache clears the info cache.
-func ClearInfoCache() {
-	infoCacheMutex.Lock()
-	defer infoCacheMutex.Unlock()
-	infoCache = make(map[string]*yaml.Node)
+func (s *Session) ClearInfoCache() {
+	s.infoCacheMutex.Lock()
+	defer s.infoCacheMutex.Unlock()
+	s.infoCache = make(map[string]*yaml.Node)
 }
 
 // ClearCaches clears all caches.
-func ClearCaches() {
-	ClearFileCache()
-	ClearInfoCache()
+func (s *Session) ClearCaches() {
+	s.ClearFileCache()
+	s.ClearInfoCache()
 }
 
-// FetchFile gets a specified file from the local filesystem or a remote location.
-func FetchFile(fileurl string) ([]byte, error) {
-	fileCacheMutex.Lock()
-	defer fileCacheMutex.Unlock()
-	return fetchFile(fileurl)
+// FetchFile gets a specified file from a remote location using the session's Loader.
+func (s *Session) FetchFile(fileurl string) ([]byte, error) {
+	s.fileCacheMutex.Lock()
+	defer s.fileCacheMutex.Unlock()
+	return s.fetchFile(fileurl)
 }
 
-func fetchFile(fileurl string) ([]byte, error) {
-	var bytes []byte
-	initializeFileCache()
-	if fileCacheEnable {
-		bytes, ok := fileCache[fileurl]
+func (s *Session) fetchFile(fileurl string) ([]byte, error) {
+	s.initializeFileCache()
+	if s.fileCacheEnable {
+		bytes, ok := s.fileCache[fileurl]
 		if ok {
 			if verboseReader {
 				log.Printf("Cache hit %s", fileurl)
@@ -160,41 +145,33 @@ func fetchFile(fileurl string) ([]byte, error) {
 			log.Printf("Fetching %s", fileurl)
 		}
 	}
-	response, err := http.Get(fileurl)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("Error downloading %s: %s", fileurl, response.Status)
-	}
-	bytes, err = ioutil.ReadAll(response.Body)
-	if fileCacheEnable && err == nil {
-		fileCache[fileurl] = bytes
+	bytes, err := s.GetLoader().Load(fileurl)
+	if s.fileCacheEnable && err == nil {
+		s.fileCache[fileurl] = bytes
 	}
 	return bytes, err
 }
 
 // ReadBytesForFile reads the bytes of a file.
-func ReadBytesForFile(filename string) ([]byte, error) {
-	fileCacheMutex.Lock()
-	defer fileCacheMutex.Unlock()
-	return readBytesForFile(filename)
+func (s *Session) ReadBytesForFile(filename string) ([]byte, error) {
+	s.fileCacheMutex.Lock()
+	defer s.fileCacheMutex.Unlock()
+	return s.readBytesForFile(filename)
 }
 
-func readBytesForFile(filename string) ([]byte, error) {
+func (s *Session) readBytesForFile(filename string) ([]byte, error) {
 	// is the filename a url?
 	fileurl, _ := url.Parse(filename)
 	if fileurl.Scheme != "" {
 		// yes, fetch it
-		bytes, err := fetchFile(filename)
+		bytes, err := s.fetchFile(filename)
 		if err != nil {
 			return nil, err
 		}
 		return bytes, nil
 	}
 	// no, it's a local filename
-	bytes, err := ioutil.ReadFile(filename)
+	bytes, err := s.GetLoader().Load(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -202,16 +179,16 @@ func readBytesForFile(filename string) ([]byte, error) {
 }
 
 // ReadInfoFromBytes unmarshals a file as a *yaml.Node.
-func ReadInfoFromBytes(filename string, bytes []byte) (*yaml.Node, error) {
-	infoCacheMutex.Lock()
-	defer infoCacheMutex.Unlock()
-	return readInfoFromBytes(filename, bytes)
+func (s *Session) ReadInfoFromBytes(filename string, bytes []byte) (*yaml.Node, error) {
+	s.infoCacheMutex.Lock()
+	defer s.infoCacheMutex.Unlock()
+	return s.readInfoFromBytes(filename, bytes)
 }
 
-func readInfoFromBytes(filename string, bytes []byte) (*yaml.Node, error) {
-	initializeInfoCache()
-	if infoCacheEnable {
-		cachedInfo, ok := infoCache[filename]
+func (s *Session) readInfoFromBytes(filename string, bytes []byte) (*yaml.Node, error) {
+	s.initializeInfoCache()
+	if s.infoCacheEnable {
+		cachedInfo, ok := s.infoCache[filename]
 		if ok {
 			if verboseReader {
 				log.Printf("Cache hit info for file %s", filename)
@@ -227,81 +204,133 @@ func readInfoFromBytes(filename string, bytes []byte) (*yaml.Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	if infoCacheEnable && len(filename) > 0 {
-		infoCache[filename] = &info
+	if s.infoCacheEnable && len(filename) > 0 {
+		s.infoCache[filename] = &info
 	}
 	return &info, nil
 }
 
 // ReadInfoForRef reads a file and return the fragment needed to resolve a $ref.
-func ReadInfoForRef(basefile string, ref string) (*yaml.Node, error) {
-	fileCacheMutex.Lock()
-	defer fileCacheMutex.Unlock()
-	infoCacheMutex.Lock()
-	defer infoCacheMutex.Unlock()
-	initializeInfoCache()
-	if infoCacheEnable {
-		info, ok := infoCache[ref]
+// The ref is resolved relative to basefile, which should be the location of
+// the document that contains it, and its fragment is treated as a JSON Pointer.
+func (s *Session) ReadInfoForRef(basefile string, ref string) (*yaml.Node, error) {
+	s.fileCacheMutex.Lock()
+	defer s.fileCacheMutex.Unlock()
+	s.infoCacheMutex.Lock()
+	defer s.infoCacheMutex.Unlock()
+	s.initializeInfoCache()
+	filename, fragment := splitFragment(ResolveURI(basefile, ref))
+	// References to entire files share the cache entries of the files.
+	location := filename
+	if fragment != "" {
+		location += "#" + fragment
+	}
+	if s.infoCacheEnable {
+		info, ok := s.infoCache[location]
 		if ok {
 			if verboseReader {
-				log.Printf("Cache hit for ref %s#%s", basefile, ref)
+				log.Printf("Cache hit for ref %s", location)
+			}
+			if info != nil && info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+				info = info.Content[0]
 			}
 			return info, nil
 		}
 		if verboseReader {
-			log.Printf("Reading info for ref %s#%s", basefile, ref)
+			log.Printf("Reading info for ref %s", location)
 		}
 	}
-	basedir, _ := filepath.Split(basefile)
-	parts := strings.Split(ref, "#")
-	var filename string
-	if parts[0] != "" {
-		filename = parts[0]
-		if _, err := url.ParseRequestURI(parts[0]); err != nil {
-			// It is not an URL, so the file is local
-			filename = basedir + parts[0]
-		}
-	} else {
-		filename = basefile
-	}
-	bytes, err := readBytesForFile(filename)
+	bytes, err := s.readBytesForFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	info, err := readInfoFromBytes(filename, bytes)
-	if info != nil && info.Kind == yaml.DocumentNode {
-		info = info.Content[0]
-	}
+	info, err := s.readInfoFromBytes(filename, bytes)
 	if err != nil {
-		log.Printf("File error: %v\n", err)
-	} else {
-		if info == nil {
-			return nil, NewError(nil, fmt.Sprintf("could not resolve %s", ref))
-		}
-		if len(parts) > 1 {
-			path := strings.Split(parts[1], "/")
-			for i, key := range path {
-				if i > 0 {
-					m := info
-					if true {
-						found := false
-						for i := 0; i < len(m.Content); i += 2 {
-							if m.Content[i].Value == key {
-								info = m.Content[i+1]
-								found = true
-							}
-						}
-						if !found {
-							infoCache[ref] = nil
-							return nil, NewError(nil, fmt.Sprintf("could not resolve %s", ref))
-						}
-					}
-				}
-			}
+		return nil, err
+	}
+	info, err = NodeForPointer(info, PointerForFragment(fragment))
+	if err != nil || info == nil {
+		// Unresolved references are cached so that each is only reported once.
+		if s.infoCacheEnable {
+			s.infoCache[location] = nil
 		}
+		return nil, NewError(nil, fmt.Sprintf("could not resolve %s", ref))
 	}
-	if infoCacheEnable {
-		infoCache[ref] = info
+	if s.infoCacheEnable && fragment != "" {
+		s.infoCache[location] = info
 	}
 	return info, nil
 }
+
+// The following functions operate on the default session.
+
+// EnableFileCache turns on file caching in the default session.
+func EnableFileCache() {
+	defaultSession.EnableFileCache()
+}
+
+// EnableInfoCache turns on parsed info caching in the default session.
+func EnableInfoCache() {
+	defaultSession.EnableInfoCache()
+}
+
+// DisableFileCache turns off file caching in the default session.
+func DisableFileCache() {
+	defaultSession.DisableFileCache()
+}
+
+// DisableInfoCache turns off parsed info caching in the default session.
+func DisableInfoCache() {
+	defaultSession.DisableInfoCache()
+}
+
+// RemoveFromFileCache removes an entry from the default session's file cache.
+func RemoveFromFileCache(fileurl string) {
+	defaultSession.RemoveFromFileCache(fileurl)
+}
+
+// RemoveFromInfoCache removes an entry from the default session's info cache.
+func RemoveFromInfoCache(filename string) {
+	defaultSession.RemoveFromInfoCache(filename)
+}
+
+// GetInfoCache returns the default session's info cache map.
+func GetInfoCache() map[string]*yaml.Node {
+	return defaultSession.GetInfoCache()
+}
+
+// ClearFileCache clears the default session's file cache.
+func ClearFileCache() {
+	defaultSession.ClearFileCache()
+}
+
+// ClearInfoCache clears the default session's info cache.
+func ClearInfoCache() {
+	defaultSession.ClearInfoCache()
+}
+
+// ClearCaches clears all caches of the default session.
+func ClearCaches() {
+	defaultSession.ClearCaches()
+}
+
+// FetchFile gets a specified file from a remote location using the default session.
+func FetchFile(fileurl string) ([]byte, error) {
+	return defaultSession.FetchFile(fileurl)
+}
+
+// ReadBytesForFile reads the bytes of a file using the default session.
+func ReadBytesForFile(filename string) ([]byte, error) {
+	return defaultSession.ReadBytesForFile(filename)
+}
+
+// ReadInfoFromBytes unmarshals a file as a *yaml.Node using the default session.
+func ReadInfoFromBytes(filename string, bytes []byte) (*yaml.Node, error) {
+	return defaultSession.ReadInfoFromBytes(filename, bytes)
+}
+
+// ReadInfoForRef reads a file and return the fragment needed to resolve a $ref
+// using the default session.
+func ReadInfoForRef(basefile string, ref string) (*yaml.Node, error) {
+	return defaultSession.ReadInfoForRef(basefile, ref)
+}
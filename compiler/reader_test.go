@@ -57,9 +57,9 @@ func (s *ReaderTestingSuite) TestRemoveFromInfoCache(c *check.C) {
 	petstore, err := ReadInfoFromBytes(fileName, yamlBytes)
 	c.Assert(err, check.IsNil)
 	c.Assert(petstore, check.NotNil)
-	c.Assert(len(infoCache), check.Equals, 1)
+	c.Assert(len(defaultSession.infoCache), check.Equals, 1)
 	RemoveFromInfoCache(fileName)
-	c.Assert(len(infoCache), check.Equals, 0)
+	c.Assert(len(defaultSession.infoCache), check.Equals, 0)
 }
 
 func (s *ReaderTestingSuite) TestDisableInfoCache(c *check.C) {
@@ -71,7 +71,7 @@ func (s *ReaderTestingSuite) TestDisableInfoCache(c *check.C) {
 	petstore, err := ReadInfoFromBytes(fileName, yamlBytes)
 	c.Assert(err, check.IsNil)
 	c.Assert(petstore, check.NotNil)
-	c.Assert(len(infoCache), check.Equals, 0)
+	c.Assert(len(defaultSession.infoCache), check.Equals, 0)
 	EnableInfoCache()
 }
 
@@ -80,9 +80,9 @@ func (s *ReaderTestingSuite) TestRemoveFromFileCache(c *check.C) {
 	yamlBytes, err := FetchFile(fileUrl)
 	c.Assert(err, check.IsNil)
 	c.Assert(len(yamlBytes) > 0, check.Equals, true)
-	c.Assert(len(fileCache), check.Equals, 1)
+	c.Assert(len(defaultSession.fileCache), check.Equals, 1)
 	RemoveFromFileCache(fileUrl)
-	c.Assert(len(fileCache), check.Equals, 0)
+	c.Assert(len(defaultSession.fileCache), check.Equals, 0)
 }
 
 func (s *ReaderTestingSuite) TestDisableFileCache(c *check.C) {
@@ -91,6 +91,6 @@ func (s *ReaderTestingSuite) TestDisableFileCache(c *check.C) {
 	yamlBytes, err := FetchFile(fileUrl)
 	c.Assert(err, check.IsNil)
 	c.Assert(len(yamlBytes) > 0, check.Equals, true)
-	c.Assert(len(fileCache), check.Equals, 0)
+	c.Assert(len(defaultSession.fileCache), check.Equals, 0)
 	EnableFileCache()
 }
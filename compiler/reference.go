@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ResolveURI returns the location of the target of a reference that was found
+// in the document at base, resolving relative references as described in RFC 3986.
+// References to local files are resolved relative to the directory of base and
+// are percent-decoded; URLs are left encoded. The fragment of the reference,
+// if any, is kept unchanged. A reference that has only a fragment refers to base.
+func ResolveURI(base, ref string) string {
+	location, fragment := splitFragment(ref)
+	base, _ = splitFragment(base)
+	if location == "" {
+		location = base
+	} else {
+		location = resolveLocation(base, location)
+	}
+	if strings.Contains(ref, "#") {
+		return location + "#" + fragment
+	}
+	return location
+}
+
+// resolveLocation resolves a file name or URL relative to the file name or URL of a base document.
+func resolveLocation(base, location string) string {
+	if isURL(location) {
+		return location
+	}
+	if isURL(base) {
+		b, err := url.Parse(base)
+		if err != nil {
+			return location
+		}
+		r, err := url.Parse(location)
+		if err != nil {
+			return location
+		}
+		return b.ResolveReference(r).String()
+	}
+	if s, err := url.PathUnescape(location); err == nil {
+		location = s
+	}
+	location = filepath.FromSlash(location)
+	if filepath.IsAbs(location) || base == "" {
+		return filepath.Clean(location)
+	}
+	return filepath.Join(filepath.Dir(base), location)
+}
+
+// isURL returns true if a location has a URI scheme.
+// Single-letter schemes are treated as Windows drive letters.
+func isURL(location string) bool {
+	u, err := url.Parse(location)
+	return err == nil && len(u.Scheme) > 1
+}
+
+// splitFragment divides a reference into a location and a fragment.
+func splitFragment(ref string) (string, string) {
+	if i := strings.Index(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// NodeForPointer returns the node identified by a JSON Pointer (RFC 6901).
+// Tokens are unescaped, so "~1" refers to "/" and "~0" refers to "~", and
+// tokens that are applied to sequences are treated as indexes.
+func NodeForPointer(node *yaml.Node, pointer string) (*yaml.Node, error) {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if pointer == "" {
+		return node, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = UnescapePointerToken(token)
+		if node != nil && node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		if node == nil {
+			return nil, fmt.Errorf("%q not found", pointer)
+		}
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i < len(node.Content)-1; i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			// Indexes must be decimal numbers without leading zeros.
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node.Content) &&
+				(token == "0" || !strings.HasPrefix(token, "0")) {
+				next = node.Content[index]
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%q not found", pointer)
+		}
+		node = next
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node, nil
+}
+
+// UnescapePointerToken decodes a JSON Pointer token as described in RFC 6901.
+func UnescapePointerToken(token string) string {
+	token = strings.Replace(token, "~1", "/", -1)
+	return strings.Replace(token, "~0", "~", -1)
+}
+
+// EscapePointerToken encodes a string as a JSON Pointer token as described in RFC 6901.
+func EscapePointerToken(token string) string {
+	token = strings.Replace(token, "~", "~0", -1)
+	return strings.Replace(token, "/", "~1", -1)
+}
+
+// PointerForFragment returns the JSON Pointer represented by the fragment
+// of a URI, which may be percent-encoded as described in RFC 6901, section 6.
+func PointerForFragment(fragment string) string {
+	if s, err := url.PathUnescape(fragment); err == nil {
+		return s
+	}
+	return fragment
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"path/filepath"
+
+	"gopkg.in/check.v1"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type ReferenceTestingSuite struct{}
+
+var _ = check.Suite(&ReferenceTestingSuite{})
+
+func (s *ReferenceTestingSuite) TestResolveURI(c *check.C) {
+	for _, test := range []struct {
+		base, ref, location string
+	}{
+		{"api.yaml", "#/definitions/Pet", "api.yaml#/definitions/Pet"},
+		{"specs/api.yaml", "Pet.yaml", filepath.FromSlash("specs/Pet.yaml")},
+		{"specs/api.yaml", "../common/Error.yaml#/Error", filepath.FromSlash("common/Error.yaml") + "#/Error"},
+		{"specs/schemas/Pet.yaml", "./Tag.yaml", filepath.FromSlash("specs/schemas/Tag.yaml")},
+		{"specs/api.yaml#/paths", "my%20file.yaml", filepath.FromSlash("specs/my file.yaml")},
+		{"https://example.com/specs/api.yaml", "schemas/Pet.yaml#/Pet", "https://example.com/specs/schemas/Pet.yaml#/Pet"},
+		{"https://example.com/specs/api.yaml", "../my%20file.yaml", "https://example.com/my%20file.yaml"},
+		{"https://example.com/specs/api.yaml", "#/components", "https://example.com/specs/api.yaml#/components"},
+		{"specs/api.yaml", "https://example.com/Pet.yaml", "https://example.com/Pet.yaml"},
+	} {
+		c.Assert(ResolveURI(test.base, test.ref), check.Equals, test.location, check.Commentf("%s relative to %s", test.ref, test.base))
+	}
+}
+
+func (s *ReferenceTestingSuite) TestNodeForPointer(c *check.C) {
+	var node yaml.Node
+	err := yaml.Unmarshal([]byte(`
+a/b: slash
+m~n: tilde
+"~1": escaped
+list: [zero, one, {name: two}]
+`), &node)
+	c.Assert(err, check.IsNil)
+	for pointer, value := range map[string]string{
+		"/a~1b":        "slash",
+		"/m~0n":        "tilde",
+		"/~01":         "escaped",
+		"/list/0":      "zero",
+		"/list/2/name": "two",
+	} {
+		result, err := NodeForPointer(&node, pointer)
+		c.Assert(err, check.IsNil, check.Commentf(pointer))
+		c.Assert(result.Value, check.Equals, value, check.Commentf(pointer))
+	}
+	result, err := NodeForPointer(&node, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Kind, check.Equals, yaml.MappingNode)
+	for _, pointer := range []string{"/missing", "/list/3", "/list/01", "/list/-1", "/list/name", "a~1b"} {
+		_, err := NodeForPointer(&node, pointer)
+		c.Assert(err, check.NotNil, check.Commentf(pointer))
+	}
+	c.Assert(PointerForFragment("/phone%20number"), check.Equals, "/phone number")
+	c.Assert(EscapePointerToken("m~n/o"), check.Equals, "m~0n~1o")
+	c.Assert(UnescapePointerToken("m~0n~1o"), check.Equals, "m~n/o")
+}
+
+func (s *ReferenceTestingSuite) TestNestedReferences(c *check.C) {
+	session := NewSession()
+	session.SetLoader(&MapLoader{Files: map[string][]byte{
+		filepath.FromSlash("specs/api.yaml"):           []byte("pet:\n  $ref: schemas/Pet.yaml#/Pet\n"),
+		filepath.FromSlash("specs/schemas/Pet.yaml"):   []byte("Pet:\n  owner:\n    $ref: '../../common/types.yaml#/definitions/owner~1name'\n  tag:\n    $ref: Tag.yaml\n"),
+		filepath.FromSlash("specs/schemas/Tag.yaml"):   []byte("type: string\n"),
+		filepath.FromSlash("common/types.yaml"):        []byte("definitions:\n  owner/name:\n    $ref: '#/strings/0'\nstrings:\n  - type: string\n"),
+		filepath.FromSlash("specs/schemas/types.yaml"): []byte("definitions: {}\n"),
+	}})
+	root := NewContextWithSession("$root", nil, session, nil)
+	root.BaseURI = filepath.FromSlash("specs/api.yaml")
+
+	pet, petContext, err := root.ReadInfoForRef("schemas/Pet.yaml#/Pet")
+	c.Assert(err, check.IsNil)
+	c.Assert(MapHasKey(pet, "owner"), check.Equals, true)
+	c.Assert(petContext.GetBaseURI(), check.Equals, filepath.FromSlash("specs/schemas/Pet.yaml"))
+
+	// References in the nested file are resolved relative to that file.
+	tag, _, err := petContext.ReadInfoForRef("Tag.yaml")
+	c.Assert(err, check.IsNil)
+	c.Assert(MapHasKey(tag, "type"), check.Equals, true)
+	owner, ownerContext, err := petContext.ReadInfoForRef("../../common/types.yaml#/definitions/owner~1name")
+	c.Assert(err, check.IsNil)
+	c.Assert(ownerContext.GetBaseURI(), check.Equals, filepath.FromSlash("common/types.yaml"))
+	ref, ok := StringForScalarNode(MapValueForKey(owner, "$ref"))
+	c.Assert(ok, check.Equals, true)
+	name, _, err := ownerContext.ReadInfoForRef(ref)
+	c.Assert(err, check.IsNil)
+	value, _ := StringForScalarNode(MapValueForKey(name, "type"))
+	c.Assert(value, check.Equals, "string")
+
+	// Leaving a nested document restores the base URI of its parent.
+	c.Assert(ownerContext.Parent.GetBaseURI(), check.Equals, filepath.FromSlash("specs/schemas/Pet.yaml"))
+	c.Assert(root.GetBaseURI(), check.Equals, filepath.FromSlash("specs/api.yaml"))
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"sync"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Session holds the Loader and caches used to read API descriptions and the
+// files that they reference. Sessions share no state, so documents that are
+// compiled in different sessions can be compiled concurrently.
+type Session struct {
+	loader      Loader
+	loaderMutex sync.Mutex
+
+	fileCache       map[string][]byte
+	fileCacheEnable bool
+	fileCacheMutex  sync.Mutex
+
+	infoCache       map[string]*yaml.Node
+	infoCacheEnable bool
+	infoCacheMutex  sync.Mutex
+}
+
+// NewSession returns a new session that reads files with the default Loader
+// and caches everything that it reads.
+func NewSession() *Session {
+	return &Session{
+		loader:          NewDefaultLoader(),
+		fileCacheEnable: true,
+		infoCacheEnable: true,
+	}
+}
+
+// defaultSession is used by the package-level reader functions and by
+// contexts that aren't associated with a session.
+var defaultSession = NewSession()
+
+// DefaultSession returns the session used when no other is specified.
+func DefaultSession() *Session {
+	return defaultSession
+}
+
+// SetLoader sets the Loader used to read API descriptions and the files
+// that they reference. Passing nil restores the default Loader.
+func (s *Session) SetLoader(l Loader) {
+	s.loaderMutex.Lock()
+	defer s.loaderMutex.Unlock()
+	if l == nil {
+		l = NewDefaultLoader()
+	}
+	s.loader = l
+}
+
+// GetLoader returns the Loader used to read API descriptions and the files
+// that they reference.
+func (s *Session) GetLoader() Loader {
+	s.loaderMutex.Lock()
+	defer s.loaderMutex.Unlock()
+	return s.loader
+}
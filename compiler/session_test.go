@@ -0,0 +1,87 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"sync"
+
+	"gopkg.in/check.v1"
+)
+
+type SessionTestingSuite struct{}
+
+var _ = check.Suite(&SessionTestingSuite{})
+
+func (s *SessionTestingSuite) TearDownTest(c *check.C) {
+	ClearCaches()
+}
+
+func (s *SessionTestingSuite) TestSessionsAreIndependent(c *check.C) {
+	first := NewSession()
+	first.SetLoader(&MapLoader{Files: map[string][]byte{"api.yaml": []byte("name: first")}})
+	second := NewSession()
+	second.SetLoader(&MapLoader{Files: map[string][]byte{"api.yaml": []byte("name: second")}})
+
+	info, err := first.ReadInfoForRef("api.yaml", "#/name")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.Value, check.Equals, "first")
+	info, err = second.ReadInfoForRef("api.yaml", "#/name")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.Value, check.Equals, "second")
+
+	// Clearing one session's caches leaves the others unchanged.
+	count := len(first.GetInfoCache())
+	c.Assert(count > 0, check.Equals, true)
+	second.ClearCaches()
+	c.Assert(len(first.GetInfoCache()), check.Equals, count)
+	c.Assert(len(second.GetInfoCache()), check.Equals, 0)
+
+	// The default session is not used by either of them.
+	c.Assert(len(defaultSession.GetInfoCache()), check.Equals, 0)
+	c.Assert(len(defaultSession.fileCache), check.Equals, 0)
+}
+
+func (s *SessionTestingSuite) TestConcurrentSessions(c *check.C) {
+	var wg sync.WaitGroup
+	values := make([]string, 8)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			session := NewSession()
+			session.SetLoader(&MapLoader{Files: map[string][]byte{
+				"api.yaml": []byte("name: " + string(rune('a'+i))),
+			}})
+			info, err := session.ReadInfoForRef("api.yaml", "#/name")
+			if err == nil {
+				values[i] = info.Value
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, value := range values {
+		c.Assert(value, check.Equals, string(rune('a'+i)))
+	}
+}
+
+func (s *SessionTestingSuite) TestContextSession(c *check.C) {
+	session := NewSession()
+	root := NewContextWithSession("$root", nil, session, nil)
+	child := NewContext("child", nil, root)
+	c.Assert(child.GetSession(), check.Equals, session)
+	c.Assert(NewContext("$root", nil, nil).GetSession(), check.Equals, DefaultSession())
+	var none *Context
+	c.Assert(none.GetSession(), check.Equals, DefaultSession())
+}
@@ -0,0 +1,1071 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+const (
+	defaultMediaType  = "application/json"
+	formMediaType     = "application/x-www-form-urlencoded"
+	multipartFormType = "multipart/form-data"
+)
+
+// messageList collects the warnings reported by a conversion.
+type messageList struct {
+	messages []*plugins.Message
+}
+
+func (l *messageList) warn(keys []string, code string, text string) {
+	// Shared parameters are converted for each operation, so skip repeated warnings.
+	for _, m := range l.messages {
+		if m.Code == code && strings.Join(m.Keys, "/") == strings.Join(keys, "/") {
+			return
+		}
+	}
+	l.messages = append(l.messages,
+		&plugins.Message{
+			Level: plugins.Message_WARNING,
+			Code:  code,
+			Text:  text,
+			Keys:  keys})
+}
+
+// openAPI3Converter holds the state of an OpenAPI v2 to v3 conversion.
+// Constructs that can't be represented in OpenAPI v3 are reported as messages.
+type openAPI3Converter struct {
+	messageList
+	source *openapi2.Document
+}
+
+// openAPI3RefForOpenAPI2Ref rewrites a reference into an OpenAPI v2 document
+// so that it points to the corresponding OpenAPI v3 component.
+func openAPI3RefForOpenAPI2Ref(d *openapi2.Document, ref string) string {
+	i := strings.Index(ref, "#")
+	if i < 0 {
+		return ref
+	}
+	file, fragment := ref[:i], ref[i+1:]
+	switch {
+	case strings.HasPrefix(fragment, "/definitions/"):
+		fragment = "/components/schemas/" + strings.TrimPrefix(fragment, "/definitions/")
+	case strings.HasPrefix(fragment, "/responses/"):
+		fragment = "/components/responses/" + strings.TrimPrefix(fragment, "/responses/")
+	case strings.HasPrefix(fragment, "/parameters/"):
+		name := strings.TrimPrefix(fragment, "/parameters/")
+		if file == "" && openAPI2BodyParameterForName(d, name) != nil {
+			fragment = "/components/requestBodies/" + name
+		} else {
+			fragment = "/components/parameters/" + name
+		}
+	}
+	return file + "#" + fragment
+}
+
+// openAPI2ParameterForRef returns the locally-defined parameter named by a reference.
+func openAPI2ParameterForRef(d *openapi2.Document, ref string) *openapi2.Parameter {
+	if !strings.HasPrefix(ref, "#/parameters/") || d.Parameters == nil {
+		return nil
+	}
+	name := strings.TrimPrefix(ref, "#/parameters/")
+	for _, pair := range d.Parameters.AdditionalProperties {
+		if pair.Name == name {
+			return pair.Value
+		}
+	}
+	return nil
+}
+
+func openAPI2BodyParameterForName(d *openapi2.Document, name string) *openapi2.BodyParameter {
+	if p := openAPI2ParameterForRef(d, "#/parameters/"+name); p != nil {
+		return p.GetBodyParameter()
+	}
+	return nil
+}
+
+func buildOpenAPI3AnyForOpenAPI2Any(a *openapi2.Any) *openapi3.Any {
+	if a == nil {
+		return nil
+	}
+	return &openapi3.Any{Value: a.Value, Yaml: a.Yaml}
+}
+
+func buildOpenAPI3AnysForOpenAPI2Anys(anys []*openapi2.Any) []*openapi3.Any {
+	var result []*openapi3.Any
+	for _, a := range anys {
+		result = append(result, buildOpenAPI3AnyForOpenAPI2Any(a))
+	}
+	return result
+}
+
+func buildOpenAPI3SpecificationExtensionsForOpenAPI2(extensions []*openapi2.NamedAny) []*openapi3.NamedAny {
+	var result []*openapi3.NamedAny
+	for _, pair := range extensions {
+		result = append(result, &openapi3.NamedAny{Name: pair.Name, Value: buildOpenAPI3AnyForOpenAPI2Any(pair.Value)})
+	}
+	return result
+}
+
+func (c *openAPI3Converter) buildOpenAPI3DefaultForOpenAPI2Any(keys []string, a *openapi2.Any) *openapi3.DefaultType {
+	if a == nil {
+		return nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(a.Yaml), &node); err != nil || len(node.Content) == 0 {
+		return nil
+	}
+	root := node.Content[0]
+	// Only scalar defaults can be represented in the OpenAPI v3 model.
+	if root.Kind != yaml.ScalarNode {
+		c.warn(keys, "DEFAULT", "Only scalar default values are supported in OpenAPI v3, the default value was removed.")
+		return nil
+	}
+	defaultType, err := openapi3.NewDefaultType(root, compiler.NewContext("default", root, nil))
+	if err != nil {
+		return nil
+	}
+	return defaultType
+}
+
+func buildOpenAPI3ExternalDocsForOpenAPI2ExternalDocs(docs *openapi2.ExternalDocs) *openapi3.ExternalDocs {
+	if docs == nil {
+		return nil
+	}
+	return &openapi3.ExternalDocs{
+		Description:            docs.Description,
+		Url:                    docs.Url,
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(docs.VendorExtension),
+	}
+}
+
+func buildOpenAPI3InfoForOpenAPI2Info(info *openapi2.Info) *openapi3.Info {
+	if info == nil {
+		return nil
+	}
+	i := &openapi3.Info{
+		Title:                  info.Title,
+		Version:                info.Version,
+		Description:            info.Description,
+		TermsOfService:         info.TermsOfService,
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(info.VendorExtension),
+	}
+	if contact := info.Contact; contact != nil {
+		i.Contact = &openapi3.Contact{
+			Name:                   contact.Name,
+			Url:                    contact.Url,
+			Email:                  contact.Email,
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(contact.VendorExtension),
+		}
+	}
+	if license := info.License; license != nil {
+		i.License = &openapi3.License{
+			Name:                   license.Name,
+			Url:                    license.Url,
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(license.VendorExtension),
+		}
+	}
+	return i
+}
+
+// buildOpenAPI3ServersForOpenAPI2Schemes combines a host, base path and list of schemes into server objects.
+func buildOpenAPI3ServersForOpenAPI2Schemes(host, basePath string, schemes []string) []*openapi3.Server {
+	if host == "" && basePath == "" {
+		return nil
+	}
+	if basePath == "" {
+		basePath = "/"
+	}
+	if host == "" {
+		return []*openapi3.Server{{Url: basePath}}
+	}
+	if len(schemes) == 0 {
+		// Without schemes, use a scheme-relative URL.
+		return []*openapi3.Server{{Url: "//" + host + basePath}}
+	}
+	servers := make([]*openapi3.Server, 0)
+	for _, scheme := range schemes {
+		servers = append(servers, &openapi3.Server{Url: scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+func buildOpenAPI3TagsForOpenAPI2Tags(tags []*openapi2.Tag) []*openapi3.Tag {
+	var result []*openapi3.Tag
+	for _, tag := range tags {
+		result = append(result, &openapi3.Tag{
+			Name:                   tag.Name,
+			Description:            tag.Description,
+			ExternalDocs:           buildOpenAPI3ExternalDocsForOpenAPI2ExternalDocs(tag.ExternalDocs),
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(tag.VendorExtension),
+		})
+	}
+	return result
+}
+
+func buildOpenAPI3SecurityForOpenAPI2Security(security []*openapi2.SecurityRequirement) []*openapi3.SecurityRequirement {
+	var result []*openapi3.SecurityRequirement
+	for _, requirement := range security {
+		r := &openapi3.SecurityRequirement{}
+		for _, pair := range requirement.AdditionalProperties {
+			scopes := &openapi3.StringArray{}
+			if pair.Value != nil {
+				scopes.Value = pair.Value.Value
+			}
+			r.AdditionalProperties = append(r.AdditionalProperties,
+				&openapi3.NamedStringArray{Name: pair.Name, Value: scopes})
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func (c *openAPI3Converter) buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema(keys []string, schema *openapi2.Schema) *openapi3.SchemaOrReference {
+	if ref := schema.XRef; ref != "" {
+		return &openapi3.SchemaOrReference{
+			Oneof: &openapi3.SchemaOrReference_Reference{
+				Reference: &openapi3.Reference{
+					XRef: openAPI3RefForOpenAPI2Ref(c.source, ref),
+				},
+			},
+		}
+	}
+	return &openapi3.SchemaOrReference{
+		Oneof: &openapi3.SchemaOrReference_Schema{
+			Schema: c.buildOpenAPI3SchemaForOpenAPI2Schema(keys, schema),
+		},
+	}
+}
+
+func (c *openAPI3Converter) buildOpenAPI3SchemaForOpenAPI2Schema(keys []string, schema *openapi2.Schema) *openapi3.Schema {
+	s := &openapi3.Schema{
+		Format:           schema.Format,
+		Title:            schema.Title,
+		Description:      schema.Description,
+		Default:          c.buildOpenAPI3DefaultForOpenAPI2Any(compiler.KeysWith(keys, "default"), schema.Default),
+		MultipleOf:       schema.MultipleOf,
+		Maximum:          schema.Maximum,
+		ExclusiveMaximum: schema.ExclusiveMaximum,
+		Minimum:          schema.Minimum,
+		ExclusiveMinimum: schema.ExclusiveMinimum,
+		MaxLength:        schema.MaxLength,
+		MinLength:        schema.MinLength,
+		Pattern:          schema.Pattern,
+		MaxItems:         schema.MaxItems,
+		MinItems:         schema.MinItems,
+		UniqueItems:      schema.UniqueItems,
+		MaxProperties:    schema.MaxProperties,
+		MinProperties:    schema.MinProperties,
+		Required:         schema.Required,
+		Enum:             buildOpenAPI3AnysForOpenAPI2Anys(schema.Enum),
+		ReadOnly:         schema.ReadOnly,
+		ExternalDocs:     buildOpenAPI3ExternalDocsForOpenAPI2ExternalDocs(schema.ExternalDocs),
+		Example:          buildOpenAPI3AnyForOpenAPI2Any(schema.Example),
+	}
+	if schema.Type != nil {
+		for _, typeName := range schema.Type.Value {
+			if typeName == "null" {
+				s.Nullable = true
+			} else if s.Type == "" {
+				s.Type = typeName
+			} else {
+				c.warn(compiler.KeysWith(keys, "type"), "SCHEMATYPE", "Multiple schema types are not supported in OpenAPI v3, ignoring "+typeName+".")
+			}
+		}
+	}
+	if s.Type == "file" {
+		s.Type = "string"
+		s.Format = "binary"
+	}
+	for _, pair := range schema.VendorExtension {
+		// x-nullable was a common extension for what OpenAPI v3 supports directly.
+		if pair.Name == "x-nullable" {
+			s.Nullable = strings.TrimSpace(pair.Value.GetYaml()) == "true"
+			continue
+		}
+		s.SpecificationExtension = append(s.SpecificationExtension,
+			&openapi3.NamedAny{Name: pair.Name, Value: buildOpenAPI3AnyForOpenAPI2Any(pair.Value)})
+	}
+	if schema.Discriminator != "" {
+		s.Discriminator = &openapi3.Discriminator{PropertyName: schema.Discriminator}
+	}
+	if xml := schema.Xml; xml != nil {
+		s.Xml = &openapi3.Xml{
+			Name:                   xml.Name,
+			Namespace:              xml.Namespace,
+			Prefix:                 xml.Prefix,
+			Attribute:              xml.Attribute,
+			Wrapped:                xml.Wrapped,
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(xml.VendorExtension),
+		}
+	}
+	if schema.Items != nil && len(schema.Items.Schema) > 0 {
+		s.Items = &openapi3.ItemsItem{}
+		itemsKeys := compiler.KeysWith(keys, "items")
+		for i, item := range schema.Items.Schema {
+			if len(schema.Items.Schema) > 1 {
+				itemsKeys = compiler.KeysWith(keys, "items", strconv.Itoa(i))
+			}
+			s.Items.SchemaOrReference = append(s.Items.SchemaOrReference, c.buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema(itemsKeys, item))
+		}
+	}
+	for i, item := range schema.AllOf {
+		s.AllOf = append(s.AllOf, c.buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema(compiler.KeysWith(keys, "allOf", strconv.Itoa(i)), item))
+	}
+	if schema.Properties != nil && len(schema.Properties.AdditionalProperties) > 0 {
+		s.Properties = &openapi3.Properties{}
+		for _, pair := range schema.Properties.AdditionalProperties {
+			s.Properties.AdditionalProperties = append(s.Properties.AdditionalProperties,
+				&openapi3.NamedSchemaOrReference{
+					Name:  pair.Name,
+					Value: c.buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema(compiler.KeysWith(keys, "properties", pair.Name), pair.Value),
+				})
+		}
+	}
+	if additionalProperties := schema.AdditionalProperties; additionalProperties != nil {
+		if additionalSchema := additionalProperties.GetSchema(); additionalSchema != nil {
+			s.AdditionalProperties = &openapi3.AdditionalPropertiesItem{
+				Oneof: &openapi3.AdditionalPropertiesItem_SchemaOrReference{
+					SchemaOrReference: c.buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema(compiler.KeysWith(keys, "additionalProperties"), additionalSchema),
+				},
+			}
+		} else {
+			s.AdditionalProperties = &openapi3.AdditionalPropertiesItem{
+				Oneof: &openapi3.AdditionalPropertiesItem_Boolean{
+					Boolean: additionalProperties.GetBoolean(),
+				},
+			}
+		}
+	}
+	return s
+}
+
+// buildOpenAPI3SchemaForOpenAPI2PrimitivesItems builds a schema for the primitive types
+// that OpenAPI v2 allows for non-body parameters, headers, and their items.
+func (c *openAPI3Converter) buildOpenAPI3SchemaForOpenAPI2PrimitivesItems(keys []string, items *openapi2.PrimitivesItems) *openapi3.Schema {
+	s := &openapi3.Schema{
+		Type:                   items.Type,
+		Format:                 items.Format,
+		Default:                c.buildOpenAPI3DefaultForOpenAPI2Any(compiler.KeysWith(keys, "default"), items.Default),
+		Maximum:                items.Maximum,
+		ExclusiveMaximum:       items.ExclusiveMaximum,
+		Minimum:                items.Minimum,
+		ExclusiveMinimum:       items.ExclusiveMinimum,
+		MaxLength:              items.MaxLength,
+		MinLength:              items.MinLength,
+		Pattern:                items.Pattern,
+		MaxItems:               items.MaxItems,
+		MinItems:               items.MinItems,
+		UniqueItems:            items.UniqueItems,
+		Enum:                   buildOpenAPI3AnysForOpenAPI2Anys(items.Enum),
+		MultipleOf:             items.MultipleOf,
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(items.VendorExtension),
+	}
+	if s.Type == "file" {
+		s.Type = "string"
+		s.Format = "binary"
+	}
+	if items.Items != nil {
+		s.Items = &openapi3.ItemsItem{
+			SchemaOrReference: []*openapi3.SchemaOrReference{
+				{
+					Oneof: &openapi3.SchemaOrReference_Schema{
+						Schema: c.buildOpenAPI3SchemaForOpenAPI2PrimitivesItems(compiler.KeysWith(keys, "items"), items.Items),
+					},
+				},
+			},
+		}
+	}
+	return s
+}
+
+// primitivesItemsForNonBodyParameter collects the type information of a non-body parameter.
+// The parameter-specific fields are returned separately.
+func primitivesItemsForNonBodyParameter(p *openapi2.NonBodyParameter) (items *openapi2.PrimitivesItems, name, in, description string, required, allowEmptyValue bool) {
+	switch {
+	case p.GetHeaderParameterSubSchema() != nil:
+		q := p.GetHeaderParameterSubSchema()
+		items = &openapi2.PrimitivesItems{
+			Type: q.Type, Format: q.Format, Items: q.Items, CollectionFormat: q.CollectionFormat, Default: q.Default,
+			Maximum: q.Maximum, ExclusiveMaximum: q.ExclusiveMaximum, Minimum: q.Minimum, ExclusiveMinimum: q.ExclusiveMinimum,
+			MaxLength: q.MaxLength, MinLength: q.MinLength, Pattern: q.Pattern, MaxItems: q.MaxItems, MinItems: q.MinItems,
+			UniqueItems: q.UniqueItems, Enum: q.Enum, MultipleOf: q.MultipleOf, VendorExtension: q.VendorExtension,
+		}
+		return items, q.Name, "header", q.Description, q.Required, false
+	case p.GetFormDataParameterSubSchema() != nil:
+		q := p.GetFormDataParameterSubSchema()
+		items = &openapi2.PrimitivesItems{
+			Type: q.Type, Format: q.Format, Items: q.Items, CollectionFormat: q.CollectionFormat, Default: q.Default,
+			Maximum: q.Maximum, ExclusiveMaximum: q.ExclusiveMaximum, Minimum: q.Minimum, ExclusiveMinimum: q.ExclusiveMinimum,
+			MaxLength: q.MaxLength, MinLength: q.MinLength, Pattern: q.Pattern, MaxItems: q.MaxItems, MinItems: q.MinItems,
+			UniqueItems: q.UniqueItems, Enum: q.Enum, MultipleOf: q.MultipleOf, VendorExtension: q.VendorExtension,
+		}
+		return items, q.Name, "formData", q.Description, q.Required, q.AllowEmptyValue
+	case p.GetQueryParameterSubSchema() != nil:
+		q := p.GetQueryParameterSubSchema()
+		items = &openapi2.PrimitivesItems{
+			Type: q.Type, Format: q.Format, Items: q.Items, CollectionFormat: q.CollectionFormat, Default: q.Default,
+			Maximum: q.Maximum, ExclusiveMaximum: q.ExclusiveMaximum, Minimum: q.Minimum, ExclusiveMinimum: q.ExclusiveMinimum,
+			MaxLength: q.MaxLength, MinLength: q.MinLength, Pattern: q.Pattern, MaxItems: q.MaxItems, MinItems: q.MinItems,
+			UniqueItems: q.UniqueItems, Enum: q.Enum, MultipleOf: q.MultipleOf, VendorExtension: q.VendorExtension,
+		}
+		return items, q.Name, "query", q.Description, q.Required, q.AllowEmptyValue
+	case p.GetPathParameterSubSchema() != nil:
+		q := p.GetPathParameterSubSchema()
+		items = &openapi2.PrimitivesItems{
+			Type: q.Type, Format: q.Format, Items: q.Items, CollectionFormat: q.CollectionFormat, Default: q.Default,
+			Maximum: q.Maximum, ExclusiveMaximum: q.ExclusiveMaximum, Minimum: q.Minimum, ExclusiveMinimum: q.ExclusiveMinimum,
+			MaxLength: q.MaxLength, MinLength: q.MinLength, Pattern: q.Pattern, MaxItems: q.MaxItems, MinItems: q.MinItems,
+			UniqueItems: q.UniqueItems, Enum: q.Enum, MultipleOf: q.MultipleOf, VendorExtension: q.VendorExtension,
+		}
+		return items, q.Name, "path", q.Description, q.Required, false
+	}
+	return nil, "", "", "", false, false
+}
+
+// buildOpenAPI3ParameterForOpenAPI2NonBodyParameter converts query, header, and path parameters.
+// Form parameters become request bodies and are handled separately.
+func (c *openAPI3Converter) buildOpenAPI3ParameterForOpenAPI2NonBodyParameter(keys []string, p *openapi2.NonBodyParameter) *openapi3.Parameter {
+	items, name, in, description, required, allowEmptyValue := primitivesItemsForNonBodyParameter(p)
+	if items == nil || in == "formData" {
+		return nil
+	}
+	parameter := &openapi3.Parameter{
+		Name:            name,
+		In:              in,
+		Description:     description,
+		Required:        required,
+		AllowEmptyValue: allowEmptyValue,
+		Schema: &openapi3.SchemaOrReference{
+			Oneof: &openapi3.SchemaOrReference_Schema{
+				Schema: c.buildOpenAPI3SchemaForOpenAPI2PrimitivesItems(keys, items),
+			},
+		},
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(items.VendorExtension),
+	}
+	// Map collection formats to their closest style equivalents.
+	switch items.CollectionFormat {
+	case "", "csv":
+		// Query parameters need explode: false for csv, but Parameter.Explode
+		// is only written when it is true, so these parameters explode like multi.
+		if in == "query" && items.Type == "array" {
+			c.warn(keys, "COLLECTIONFORMAT", "The csv collection format can't be written for query parameters in OpenAPI v3, the parameter uses the multi format.")
+		}
+	case "ssv":
+		parameter.Style = "spaceDelimited"
+	case "pipes":
+		parameter.Style = "pipeDelimited"
+	case "multi":
+		parameter.Style = "form"
+		parameter.Explode = true
+	case "tsv":
+		c.warn(compiler.KeysWith(keys, "collectionFormat"), "COLLECTIONFORMAT", "The tsv collection format is not supported in OpenAPI v3 and was removed.")
+	}
+	return parameter
+}
+
+func (c *openAPI3Converter) buildOpenAPI3HeadersForOpenAPI2Headers(keys []string, headers *openapi2.Headers) *openapi3.HeadersOrReferences {
+	if headers == nil || len(headers.AdditionalProperties) == 0 {
+		return nil
+	}
+	h := &openapi3.HeadersOrReferences{}
+	for _, pair := range headers.AdditionalProperties {
+		header := pair.Value
+		items := &openapi2.PrimitivesItems{
+			Type: header.Type, Format: header.Format, Items: header.Items, CollectionFormat: header.CollectionFormat, Default: header.Default,
+			Maximum: header.Maximum, ExclusiveMaximum: header.ExclusiveMaximum, Minimum: header.Minimum, ExclusiveMinimum: header.ExclusiveMinimum,
+			MaxLength: header.MaxLength, MinLength: header.MinLength, Pattern: header.Pattern, MaxItems: header.MaxItems, MinItems: header.MinItems,
+			UniqueItems: header.UniqueItems, Enum: header.Enum, MultipleOf: header.MultipleOf,
+		}
+		h.AdditionalProperties = append(h.AdditionalProperties, &openapi3.NamedHeaderOrReference{
+			Name: pair.Name,
+			Value: &openapi3.HeaderOrReference{
+				Oneof: &openapi3.HeaderOrReference_Header{
+					Header: &openapi3.Header{
+						Description: header.Description,
+						Schema: &openapi3.SchemaOrReference{
+							Oneof: &openapi3.SchemaOrReference_Schema{
+								Schema: c.buildOpenAPI3SchemaForOpenAPI2PrimitivesItems(compiler.KeysWith(keys, pair.Name), items),
+							},
+						},
+						SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(header.VendorExtension),
+					},
+				},
+			},
+		})
+	}
+	return h
+}
+
+// buildOpenAPI3MediaTypes builds a content map that uses the same schema for each media type.
+func buildOpenAPI3MediaTypes(mediaTypes []string, schema *openapi3.SchemaOrReference) *openapi3.MediaTypes {
+	content := &openapi3.MediaTypes{}
+	for _, mediaType := range mediaTypes {
+		content.AdditionalProperties = append(content.AdditionalProperties, &openapi3.NamedMediaType{
+			Name:  mediaType,
+			Value: &openapi3.MediaType{Schema: schema},
+		})
+	}
+	return content
+}
+
+func (c *openAPI3Converter) buildOpenAPI3RequestBodyForOpenAPI2BodyParameter(keys []string, p *openapi2.BodyParameter, consumes []string) *openapi3.RequestBody {
+	if len(consumes) == 0 {
+		consumes = []string{defaultMediaType}
+	}
+	var schema *openapi3.SchemaOrReference
+	if p.Schema != nil {
+		schema = c.buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema(compiler.KeysWith(keys, "schema"), p.Schema)
+	}
+	return &openapi3.RequestBody{
+		Description:            p.Description,
+		Required:               p.Required,
+		Content:                buildOpenAPI3MediaTypes(consumes, schema),
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(p.VendorExtension),
+	}
+}
+
+// buildOpenAPI3RequestBodyForOpenAPI2FormDataParameters gathers form parameters into the properties of an object schema.
+// The keys of each parameter are listed in parameterKeys.
+func (c *openAPI3Converter) buildOpenAPI3RequestBodyForOpenAPI2FormDataParameters(parameterKeys [][]string, parameters []*openapi2.FormDataParameterSubSchema, consumes []string) *openapi3.RequestBody {
+	s := &openapi3.Schema{
+		Type:       "object",
+		Properties: &openapi3.Properties{},
+	}
+	hasFile := false
+	for i, p := range parameters {
+		property := c.buildOpenAPI3SchemaForOpenAPI2PrimitivesItems(parameterKeys[i], &openapi2.PrimitivesItems{
+			Type: p.Type, Format: p.Format, Items: p.Items, Default: p.Default,
+			Maximum: p.Maximum, ExclusiveMaximum: p.ExclusiveMaximum, Minimum: p.Minimum, ExclusiveMinimum: p.ExclusiveMinimum,
+			MaxLength: p.MaxLength, MinLength: p.MinLength, Pattern: p.Pattern, MaxItems: p.MaxItems, MinItems: p.MinItems,
+			UniqueItems: p.UniqueItems, Enum: p.Enum, MultipleOf: p.MultipleOf, VendorExtension: p.VendorExtension,
+		})
+		property.Description = p.Description
+		if p.Type == "file" {
+			hasFile = true
+		}
+		s.Properties.AdditionalProperties = append(s.Properties.AdditionalProperties,
+			&openapi3.NamedSchemaOrReference{
+				Name: p.Name,
+				Value: &openapi3.SchemaOrReference{
+					Oneof: &openapi3.SchemaOrReference_Schema{Schema: property},
+				},
+			})
+		if p.Required {
+			s.Required = append(s.Required, p.Name)
+		}
+	}
+	// Form parameters can only be sent with form media types.
+	mediaTypes := make([]string, 0)
+	for _, mediaType := range consumes {
+		if mediaType == formMediaType || mediaType == multipartFormType {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		if hasFile {
+			mediaTypes = append(mediaTypes, multipartFormType)
+		} else {
+			mediaTypes = append(mediaTypes, formMediaType)
+		}
+	}
+	return &openapi3.RequestBody{
+		Content: buildOpenAPI3MediaTypes(mediaTypes, &openapi3.SchemaOrReference{
+			Oneof: &openapi3.SchemaOrReference_Schema{Schema: s},
+		}),
+	}
+}
+
+func (c *openAPI3Converter) buildOpenAPI3ResponseForOpenAPI2Response(keys []string, response *openapi2.Response, produces []string) *openapi3.Response {
+	if len(produces) == 0 {
+		produces = []string{defaultMediaType}
+	}
+	r := &openapi3.Response{
+		Description:            response.Description,
+		Headers:                c.buildOpenAPI3HeadersForOpenAPI2Headers(compiler.KeysWith(keys, "headers"), response.Headers),
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(response.VendorExtension),
+	}
+	if response.Schema != nil {
+		var schema *openapi3.SchemaOrReference
+		if s := response.Schema.GetSchema(); s != nil {
+			schema = c.buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema(compiler.KeysWith(keys, "schema"), s)
+		} else if fileSchema := response.Schema.GetFileSchema(); fileSchema != nil {
+			schema = &openapi3.SchemaOrReference{
+				Oneof: &openapi3.SchemaOrReference_Schema{
+					Schema: &openapi3.Schema{
+						Type:        "string",
+						Format:      "binary",
+						Title:       fileSchema.Title,
+						Description: fileSchema.Description,
+					},
+				},
+			}
+		}
+		r.Content = buildOpenAPI3MediaTypes(produces, schema)
+	}
+	if response.Examples != nil {
+		if r.Content == nil {
+			r.Content = &openapi3.MediaTypes{}
+		}
+		for _, pair := range response.Examples.AdditionalProperties {
+			mediaType := openAPI3MediaTypeForName(r.Content, pair.Name)
+			mediaType.Example = buildOpenAPI3AnyForOpenAPI2Any(pair.Value)
+		}
+	}
+	return r
+}
+
+// openAPI3MediaTypeForName finds or adds the named media type in a content map.
+func openAPI3MediaTypeForName(content *openapi3.MediaTypes, name string) *openapi3.MediaType {
+	for _, pair := range content.AdditionalProperties {
+		if pair.Name == name {
+			return pair.Value
+		}
+	}
+	mediaType := &openapi3.MediaType{}
+	content.AdditionalProperties = append(content.AdditionalProperties,
+		&openapi3.NamedMediaType{Name: name, Value: mediaType})
+	return mediaType
+}
+
+func (c *openAPI3Converter) buildOpenAPI3ResponseOrReferenceForOpenAPI2ResponseValue(keys []string, value *openapi2.ResponseValue, produces []string) *openapi3.ResponseOrReference {
+	if ref := value.GetJsonReference(); ref != nil {
+		return &openapi3.ResponseOrReference{
+			Oneof: &openapi3.ResponseOrReference_Reference{
+				Reference: &openapi3.Reference{
+					XRef:        openAPI3RefForOpenAPI2Ref(c.source, ref.XRef),
+					Description: ref.Description,
+				},
+			},
+		}
+	}
+	return &openapi3.ResponseOrReference{
+		Oneof: &openapi3.ResponseOrReference_Response{
+			Response: c.buildOpenAPI3ResponseForOpenAPI2Response(keys, value.GetResponse(), produces),
+		},
+	}
+}
+
+func (c *openAPI3Converter) buildOpenAPI3ResponsesForOpenAPI2Responses(keys []string, responses *openapi2.Responses, produces []string) *openapi3.Responses {
+	if responses == nil {
+		return nil
+	}
+	r := &openapi3.Responses{
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(responses.VendorExtension),
+	}
+	for _, pair := range responses.ResponseCode {
+		value := c.buildOpenAPI3ResponseOrReferenceForOpenAPI2ResponseValue(compiler.KeysWith(keys, pair.Name), pair.Value, produces)
+		if pair.Name == "default" {
+			r.Default = value
+		} else {
+			r.ResponseOrReference = append(r.ResponseOrReference,
+				&openapi3.NamedResponseOrReference{Name: pair.Name, Value: value})
+		}
+	}
+	return r
+}
+
+// openAPI2ParameterKey identifies a parameter by its name and location
+// so that operation parameters can override path item parameters.
+func openAPI2ParameterKey(d *openapi2.Document, item *openapi2.ParametersItem) string {
+	p := item.GetParameter()
+	if ref := item.GetJsonReference(); ref != nil {
+		if p = openAPI2ParameterForRef(d, ref.XRef); p == nil {
+			return ref.XRef
+		}
+	}
+	if body := p.GetBodyParameter(); body != nil {
+		return "body"
+	}
+	_, name, in, _, _, _ := primitivesItemsForNonBodyParameter(p.GetNonBodyParameter())
+	return in + ":" + name
+}
+
+// parameterKeys returns the keys of each parameter in a list of parameters.
+func parameterKeys(keys []string, parameters []*openapi2.ParametersItem) [][]string {
+	result := make([][]string, len(parameters))
+	for i := range parameters {
+		result[i] = compiler.KeysWith(keys, strconv.Itoa(i))
+	}
+	return result
+}
+
+// mergeOpenAPI2Parameters combines path item parameters with operation parameters.
+// The keys of the combined parameters are returned with them.
+func mergeOpenAPI2Parameters(d *openapi2.Document, pathKeys [][]string, pathParameters []*openapi2.ParametersItem, operationKeys [][]string, operationParameters []*openapi2.ParametersItem) ([][]string, []*openapi2.ParametersItem) {
+	overridden := make(map[string]bool)
+	for _, item := range operationParameters {
+		overridden[openAPI2ParameterKey(d, item)] = true
+	}
+	keys := make([][]string, 0)
+	parameters := make([]*openapi2.ParametersItem, 0)
+	for i, item := range pathParameters {
+		if !overridden[openAPI2ParameterKey(d, item)] {
+			keys = append(keys, pathKeys[i])
+			parameters = append(parameters, item)
+		}
+	}
+	return append(keys, operationKeys...), append(parameters, operationParameters...)
+}
+
+// buildOpenAPI3ParametersForOpenAPI2Parameters converts a list of parameters, returning any request body they describe.
+// The keys of each parameter are listed in itemKeys.
+func (c *openAPI3Converter) buildOpenAPI3ParametersForOpenAPI2Parameters(keys []string, itemKeys [][]string, items []*openapi2.ParametersItem, consumes []string) ([]*openapi3.ParameterOrReference, *openapi3.RequestBodyOrReference) {
+	d := c.source
+	parameters := make([]*openapi3.ParameterOrReference, 0)
+	var requestBody *openapi3.RequestBodyOrReference
+	formParameters := make([]*openapi2.FormDataParameterSubSchema, 0)
+	formParameterKeys := make([][]string, 0)
+	for i, item := range items {
+		p := item.GetParameter()
+		if ref := item.GetJsonReference(); ref != nil {
+			p = openAPI2ParameterForRef(d, ref.XRef)
+			// Body parameters and parameters that can't be found locally remain references.
+			if p == nil || p.GetBodyParameter() != nil {
+				xref := openAPI3RefForOpenAPI2Ref(d, ref.XRef)
+				if p != nil {
+					requestBody = &openapi3.RequestBodyOrReference{
+						Oneof: &openapi3.RequestBodyOrReference_Reference{
+							Reference: &openapi3.Reference{XRef: xref},
+						},
+					}
+				} else {
+					parameters = append(parameters, &openapi3.ParameterOrReference{
+						Oneof: &openapi3.ParameterOrReference_Reference{
+							Reference: &openapi3.Reference{XRef: xref},
+						},
+					})
+				}
+				continue
+			}
+			// Form parameters are inlined into the request body, all others are referenced.
+			if p.GetNonBodyParameter().GetFormDataParameterSubSchema() == nil {
+				parameters = append(parameters, &openapi3.ParameterOrReference{
+					Oneof: &openapi3.ParameterOrReference_Reference{
+						Reference: &openapi3.Reference{XRef: openAPI3RefForOpenAPI2Ref(d, ref.XRef)},
+					},
+				})
+				continue
+			}
+		}
+		if body := p.GetBodyParameter(); body != nil {
+			requestBody = &openapi3.RequestBodyOrReference{
+				Oneof: &openapi3.RequestBodyOrReference_RequestBody{
+					RequestBody: c.buildOpenAPI3RequestBodyForOpenAPI2BodyParameter(itemKeys[i], body, consumes),
+				},
+			}
+		} else if form := p.GetNonBodyParameter().GetFormDataParameterSubSchema(); form != nil {
+			formParameters = append(formParameters, form)
+			formParameterKeys = append(formParameterKeys, itemKeys[i])
+		} else if parameter := c.buildOpenAPI3ParameterForOpenAPI2NonBodyParameter(itemKeys[i], p.GetNonBodyParameter()); parameter != nil {
+			parameters = append(parameters, &openapi3.ParameterOrReference{
+				Oneof: &openapi3.ParameterOrReference_Parameter{Parameter: parameter},
+			})
+		}
+	}
+	if len(formParameters) > 0 {
+		if requestBody != nil {
+			c.warn(keys, "FORMPARAMETERS", "Form parameters can't be combined with a body parameter and were removed.")
+		} else {
+			requestBody = &openapi3.RequestBodyOrReference{
+				Oneof: &openapi3.RequestBodyOrReference_RequestBody{
+					RequestBody: c.buildOpenAPI3RequestBodyForOpenAPI2FormDataParameters(formParameterKeys, formParameters, consumes),
+				},
+			}
+		}
+	}
+	return parameters, requestBody
+}
+
+func (c *openAPI3Converter) buildOpenAPI3OperationForOpenAPI2Operation(keys []string, operation *openapi2.Operation, pathKeys [][]string, pathParameters []*openapi2.ParametersItem) *openapi3.Operation {
+	if operation == nil {
+		return nil
+	}
+	d := c.source
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = d.Consumes
+	}
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = d.Produces
+	}
+	parameterKeys, parameterItems := mergeOpenAPI2Parameters(d, pathKeys, pathParameters,
+		parameterKeys(compiler.KeysWith(keys, "parameters"), operation.Parameters), operation.Parameters)
+	parameters, requestBody := c.buildOpenAPI3ParametersForOpenAPI2Parameters(keys, parameterKeys, parameterItems, consumes)
+	if len(parameters) == 0 {
+		parameters = nil
+	}
+	o := &openapi3.Operation{
+		Tags:                   operation.Tags,
+		Summary:                operation.Summary,
+		Description:            operation.Description,
+		ExternalDocs:           buildOpenAPI3ExternalDocsForOpenAPI2ExternalDocs(operation.ExternalDocs),
+		OperationId:            operation.OperationId,
+		Parameters:             parameters,
+		RequestBody:            requestBody,
+		Responses:              c.buildOpenAPI3ResponsesForOpenAPI2Responses(compiler.KeysWith(keys, "responses"), operation.Responses, produces),
+		Deprecated:             operation.Deprecated,
+		Security:               buildOpenAPI3SecurityForOpenAPI2Security(operation.Security),
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(operation.VendorExtension),
+	}
+	if len(operation.Schemes) > 0 {
+		o.Servers = buildOpenAPI3ServersForOpenAPI2Schemes(d.Host, d.BasePath, operation.Schemes)
+	}
+	return o
+}
+
+func (c *openAPI3Converter) buildOpenAPI3PathItemForOpenAPI2PathItem(keys []string, pathItem *openapi2.PathItem) *openapi3.PathItem {
+	p := &openapi3.PathItem{
+		XRef:                   pathItem.XRef,
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(pathItem.VendorExtension),
+	}
+	// Path-level body and form parameters must be moved into each operation's request body,
+	// so path-level parameters are only kept here when all of them are simple parameters.
+	pathKeys := parameterKeys(compiler.KeysWith(keys, "parameters"), pathItem.Parameters)
+	var operationKeys [][]string
+	var operationParameters []*openapi2.ParametersItem
+	if c.hasOpenAPI2RequestBodyParameters(pathItem.Parameters) {
+		operationKeys = pathKeys
+		operationParameters = pathItem.Parameters
+	} else {
+		parameters, _ := c.buildOpenAPI3ParametersForOpenAPI2Parameters(keys, pathKeys, pathItem.Parameters, c.source.Consumes)
+		if len(parameters) > 0 {
+			p.Parameters = parameters
+		}
+	}
+	p.Get = c.buildOpenAPI3OperationForOpenAPI2Operation(compiler.KeysWith(keys, "get"), pathItem.Get, operationKeys, operationParameters)
+	p.Put = c.buildOpenAPI3OperationForOpenAPI2Operation(compiler.KeysWith(keys, "put"), pathItem.Put, operationKeys, operationParameters)
+	p.Post = c.buildOpenAPI3OperationForOpenAPI2Operation(compiler.KeysWith(keys, "post"), pathItem.Post, operationKeys, operationParameters)
+	p.Delete = c.buildOpenAPI3OperationForOpenAPI2Operation(compiler.KeysWith(keys, "delete"), pathItem.Delete, operationKeys, operationParameters)
+	p.Options = c.buildOpenAPI3OperationForOpenAPI2Operation(compiler.KeysWith(keys, "options"), pathItem.Options, operationKeys, operationParameters)
+	p.Head = c.buildOpenAPI3OperationForOpenAPI2Operation(compiler.KeysWith(keys, "head"), pathItem.Head, operationKeys, operationParameters)
+	p.Patch = c.buildOpenAPI3OperationForOpenAPI2Operation(compiler.KeysWith(keys, "patch"), pathItem.Patch, operationKeys, operationParameters)
+	return p
+}
+
+// hasOpenAPI2RequestBodyParameters returns true if any of a list of parameters
+// is a body or form parameter.
+func (c *openAPI3Converter) hasOpenAPI2RequestBodyParameters(items []*openapi2.ParametersItem) bool {
+	for _, item := range items {
+		p := item.GetParameter()
+		if ref := item.GetJsonReference(); ref != nil {
+			p = openAPI2ParameterForRef(c.source, ref.XRef)
+		}
+		if p.GetBodyParameter() != nil || p.GetNonBodyParameter().GetFormDataParameterSubSchema() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func buildOpenAPI3OauthFlow(authorizationUrl, tokenUrl string, scopes *openapi2.Oauth2Scopes) *openapi3.OauthFlow {
+	flow := &openapi3.OauthFlow{
+		AuthorizationUrl: authorizationUrl,
+		TokenUrl:         tokenUrl,
+		Scopes:           &openapi3.Strings{},
+	}
+	if scopes != nil {
+		for _, pair := range scopes.AdditionalProperties {
+			flow.Scopes.AdditionalProperties = append(flow.Scopes.AdditionalProperties,
+				&openapi3.NamedString{Name: pair.Name, Value: pair.Value})
+		}
+	}
+	return flow
+}
+
+func buildOpenAPI3SecuritySchemeForOpenAPI2SecurityDefinitionsItem(item *openapi2.SecurityDefinitionsItem) *openapi3.SecurityScheme {
+	switch {
+	case item.GetBasicAuthenticationSecurity() != nil:
+		s := item.GetBasicAuthenticationSecurity()
+		return &openapi3.SecurityScheme{
+			Type:                   "http",
+			Scheme:                 "basic",
+			Description:            s.Description,
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(s.VendorExtension),
+		}
+	case item.GetApiKeySecurity() != nil:
+		s := item.GetApiKeySecurity()
+		return &openapi3.SecurityScheme{
+			Type:                   "apiKey",
+			Name:                   s.Name,
+			In:                     s.In,
+			Description:            s.Description,
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(s.VendorExtension),
+		}
+	case item.GetOauth2ImplicitSecurity() != nil:
+		s := item.GetOauth2ImplicitSecurity()
+		return &openapi3.SecurityScheme{
+			Type:        "oauth2",
+			Description: s.Description,
+			Flows: &openapi3.OauthFlows{
+				Implicit: buildOpenAPI3OauthFlow(s.AuthorizationUrl, "", s.Scopes),
+			},
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(s.VendorExtension),
+		}
+	case item.GetOauth2PasswordSecurity() != nil:
+		s := item.GetOauth2PasswordSecurity()
+		return &openapi3.SecurityScheme{
+			Type:        "oauth2",
+			Description: s.Description,
+			Flows: &openapi3.OauthFlows{
+				Password: buildOpenAPI3OauthFlow("", s.TokenUrl, s.Scopes),
+			},
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(s.VendorExtension),
+		}
+	case item.GetOauth2ApplicationSecurity() != nil:
+		s := item.GetOauth2ApplicationSecurity()
+		return &openapi3.SecurityScheme{
+			Type:        "oauth2",
+			Description: s.Description,
+			Flows: &openapi3.OauthFlows{
+				ClientCredentials: buildOpenAPI3OauthFlow("", s.TokenUrl, s.Scopes),
+			},
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(s.VendorExtension),
+		}
+	case item.GetOauth2AccessCodeSecurity() != nil:
+		s := item.GetOauth2AccessCodeSecurity()
+		return &openapi3.SecurityScheme{
+			Type:        "oauth2",
+			Description: s.Description,
+			Flows: &openapi3.OauthFlows{
+				AuthorizationCode: buildOpenAPI3OauthFlow(s.AuthorizationUrl, s.TokenUrl, s.Scopes),
+			},
+			SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(s.VendorExtension),
+		}
+	}
+	return nil
+}
+
+func (c *openAPI3Converter) buildOpenAPI3ComponentsForOpenAPI2Document() *openapi3.Components {
+	d := c.source
+	components := &openapi3.Components{}
+	if d.Definitions != nil && len(d.Definitions.AdditionalProperties) > 0 {
+		components.Schemas = &openapi3.SchemasOrReferences{}
+		for _, pair := range d.Definitions.AdditionalProperties {
+			components.Schemas.AdditionalProperties = append(components.Schemas.AdditionalProperties,
+				&openapi3.NamedSchemaOrReference{
+					Name:  pair.Name,
+					Value: c.buildOpenAPI3SchemaOrReferenceForOpenAPI2Schema([]string{"definitions", pair.Name}, pair.Value),
+				})
+		}
+	}
+	if d.Parameters != nil {
+		for _, pair := range d.Parameters.AdditionalProperties {
+			if body := pair.Value.GetBodyParameter(); body != nil {
+				if components.RequestBodies == nil {
+					components.RequestBodies = &openapi3.RequestBodiesOrReferences{}
+				}
+				components.RequestBodies.AdditionalProperties = append(components.RequestBodies.AdditionalProperties,
+					&openapi3.NamedRequestBodyOrReference{
+						Name: pair.Name,
+						Value: &openapi3.RequestBodyOrReference{
+							Oneof: &openapi3.RequestBodyOrReference_RequestBody{
+								RequestBody: c.buildOpenAPI3RequestBodyForOpenAPI2BodyParameter([]string{"parameters", pair.Name}, body, d.Consumes),
+							},
+						},
+					})
+			} else if parameter := c.buildOpenAPI3ParameterForOpenAPI2NonBodyParameter([]string{"parameters", pair.Name}, pair.Value.GetNonBodyParameter()); parameter != nil {
+				if components.Parameters == nil {
+					components.Parameters = &openapi3.ParametersOrReferences{}
+				}
+				components.Parameters.AdditionalProperties = append(components.Parameters.AdditionalProperties,
+					&openapi3.NamedParameterOrReference{
+						Name: pair.Name,
+						Value: &openapi3.ParameterOrReference{
+							Oneof: &openapi3.ParameterOrReference_Parameter{Parameter: parameter},
+						},
+					})
+			}
+		}
+	}
+	if d.Responses != nil && len(d.Responses.AdditionalProperties) > 0 {
+		components.Responses = &openapi3.ResponsesOrReferences{}
+		for _, pair := range d.Responses.AdditionalProperties {
+			components.Responses.AdditionalProperties = append(components.Responses.AdditionalProperties,
+				&openapi3.NamedResponseOrReference{
+					Name: pair.Name,
+					Value: &openapi3.ResponseOrReference{
+						Oneof: &openapi3.ResponseOrReference_Response{
+							Response: c.buildOpenAPI3ResponseForOpenAPI2Response([]string{"responses", pair.Name}, pair.Value, d.Produces),
+						},
+					},
+				})
+		}
+	}
+	if d.SecurityDefinitions != nil && len(d.SecurityDefinitions.AdditionalProperties) > 0 {
+		components.SecuritySchemes = &openapi3.SecuritySchemesOrReferences{}
+		for _, pair := range d.SecurityDefinitions.AdditionalProperties {
+			scheme := buildOpenAPI3SecuritySchemeForOpenAPI2SecurityDefinitionsItem(pair.Value)
+			if scheme == nil {
+				c.warn([]string{"securityDefinitions", pair.Name}, "SECURITYDEFINITION", "Security definition "+pair.Name+" could not be converted and was removed.")
+				continue
+			}
+			components.SecuritySchemes.AdditionalProperties = append(components.SecuritySchemes.AdditionalProperties,
+				&openapi3.NamedSecuritySchemeOrReference{
+					Name: pair.Name,
+					Value: &openapi3.SecuritySchemeOrReference{
+						Oneof: &openapi3.SecuritySchemeOrReference_SecurityScheme{SecurityScheme: scheme},
+					},
+				})
+		}
+	}
+	return components
+}
+
+// OpenAPIv2ToOpenAPIv3 returns an OpenAPI v3 representation of an OpenAPI v2 document.
+func OpenAPIv2ToOpenAPIv3(d2 *openapi2.Document) (*openapi3.Document, error) {
+	d3, _, err := OpenAPIv2ToOpenAPIv3WithMessages(d2)
+	return d3, err
+}
+
+// OpenAPIv2ToOpenAPIv3WithMessages is like OpenAPIv2ToOpenAPIv3 but also returns
+// warnings about any parts of the document that could not be converted.
+func OpenAPIv2ToOpenAPIv3WithMessages(d2 *openapi2.Document) (*openapi3.Document, []*plugins.Message, error) {
+	if d2 == nil {
+		return nil, nil, errors.New("no OpenAPI v2 document to convert")
+	}
+	c := &openAPI3Converter{source: d2, messageList: messageList{messages: make([]*plugins.Message, 0)}}
+	d := &openapi3.Document{
+		Openapi:                "3.0.0",
+		Info:                   buildOpenAPI3InfoForOpenAPI2Info(d2.Info),
+		Servers:                buildOpenAPI3ServersForOpenAPI2Schemes(d2.Host, d2.BasePath, d2.Schemes),
+		Security:               buildOpenAPI3SecurityForOpenAPI2Security(d2.Security),
+		Tags:                   buildOpenAPI3TagsForOpenAPI2Tags(d2.Tags),
+		ExternalDocs:           buildOpenAPI3ExternalDocsForOpenAPI2ExternalDocs(d2.ExternalDocs),
+		SpecificationExtension: buildOpenAPI3SpecificationExtensionsForOpenAPI2(d2.VendorExtension),
+	}
+	d.Paths = &openapi3.Paths{}
+	if d2.Paths != nil {
+		for _, pair := range d2.Paths.Path {
+			d.Paths.Path = append(d.Paths.Path, &openapi3.NamedPathItem{
+				Name:  pair.Name,
+				Value: c.buildOpenAPI3PathItemForOpenAPI2PathItem([]string{"paths", pair.Name}, pair.Value),
+			})
+		}
+		d.Paths.SpecificationExtension = buildOpenAPI3SpecificationExtensionsForOpenAPI2(d2.Paths.VendorExtension)
+	}
+	d.Components = c.buildOpenAPI3ComponentsForOpenAPI2Document()
+	return d, c.messages, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	openapi2 "github.com/google/gnostic/openapiv2"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+func TestOpenAPIv2ToOpenAPIv3Warnings(t *testing.T) {
+	bytes, err := ioutil.ReadFile("../testdata/v2.0/yaml/unconvertible.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d2, err := openapi2.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d3, messages, err := OpenAPIv2ToOpenAPIv3WithMessages(d2)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := map[string]string{
+		"COLLECTIONFORMAT": "paths//pets/parameters/0/collectionFormat",
+		"FORMPARAMETERS":   "paths//pets/post",
+		"SCHEMATYPE":       "definitions/Pet/properties/id/type",
+		"DEFAULT":          "definitions/Pet/properties/tags/default",
+	}
+	if len(messages) != len(expected) {
+		t.Errorf("expected %d messages, got %d: %+v", len(expected), len(messages), messages)
+	}
+	for _, message := range messages {
+		if message.Level != plugins.Message_WARNING {
+			t.Errorf("unexpected level for %s: %s", message.Code, message.Level)
+		}
+		if keys, ok := expected[message.Code]; !ok || keys != strings.Join(message.Keys, "/") {
+			t.Errorf("unexpected message %s at %s", message.Code, strings.Join(message.Keys, "/"))
+		}
+	}
+	post := d3.Paths.Path[0].Value.Post
+	if post.RequestBody.GetRequestBody().GetContent().GetAdditionalProperties()[0].Name != "application/json" {
+		t.Errorf("expected a JSON request body, got %+v", post.RequestBody)
+	}
+}
+
+func TestOpenAPIv2CollectionFormatRoundTrip(t *testing.T) {
+	d2, err := openapi2.ParseDocument([]byte(`swagger: "2.0"
+info:
+  title: Collection formats
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: tags
+          in: query
+          type: array
+          items:
+            type: string
+          collectionFormat: csv
+        - name: ids
+          in: query
+          type: array
+          items:
+            type: string
+          collectionFormat: multi
+        - name: names
+          in: header
+          type: array
+          items:
+            type: string
+      responses:
+        "200":
+          description: OK
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d3, messages, err := OpenAPIv2ToOpenAPIv3WithMessages(d2)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(messages) != 1 || messages[0].Code != "COLLECTIONFORMAT" || strings.Join(messages[0].Keys, "/") != "paths//pets/get/parameters/0" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+	d2, _, err = OpenAPIv3ToOpenAPIv2(d3)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// csv query arrays can't be written in OpenAPI v3, so they come back as multi.
+	expected := []string{"multi", "multi", "csv"}
+	parameters := d2.Paths.Path[0].Value.Get.Parameters
+	if len(parameters) != len(expected) {
+		t.Fatalf("expected %d parameters, got %d", len(expected), len(parameters))
+	}
+	for i, parameter := range parameters {
+		items := parameter.GetParameter().GetNonBodyParameter()
+		var format string
+		if q := items.GetQueryParameterSubSchema(); q != nil {
+			format = q.CollectionFormat
+		} else {
+			format = items.GetHeaderParameterSubSchema().CollectionFormat
+		}
+		if format != expected[i] {
+			t.Errorf("parameter %d: expected collectionFormat %s, got %s", i, expected[i], format)
+		}
+	}
+}
@@ -0,0 +1,1029 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// openAPI2Converter holds the state of an OpenAPI v3 to v2 conversion.
+// Constructs that can't be represented in OpenAPI v2 are reported as messages.
+type openAPI2Converter struct {
+	messageList
+	source *openapi3.Document
+}
+
+// openAPI2RefForOpenAPI3Ref rewrites a reference to an OpenAPI v3 component
+// so that it points to the corresponding OpenAPI v2 location.
+func openAPI2RefForOpenAPI3Ref(ref string) string {
+	i := strings.Index(ref, "#")
+	if i < 0 {
+		return ref
+	}
+	file, fragment := ref[:i], ref[i+1:]
+	switch {
+	case strings.HasPrefix(fragment, "/components/schemas/"):
+		fragment = "/definitions/" + strings.TrimPrefix(fragment, "/components/schemas/")
+	case strings.HasPrefix(fragment, "/components/responses/"):
+		fragment = "/responses/" + strings.TrimPrefix(fragment, "/components/responses/")
+	case strings.HasPrefix(fragment, "/components/parameters/"):
+		fragment = "/parameters/" + strings.TrimPrefix(fragment, "/components/parameters/")
+	case strings.HasPrefix(fragment, "/components/requestBodies/"):
+		fragment = "/parameters/" + strings.TrimPrefix(fragment, "/components/requestBodies/")
+	}
+	return file + "#" + fragment
+}
+
+// schemaForSchemaOrReference follows local schema references to find a schema.
+func (c *openAPI2Converter) schemaForSchemaOrReference(s *openapi3.SchemaOrReference) *openapi3.Schema {
+	seen := make(map[string]bool)
+	for s != nil {
+		if schema := s.GetSchema(); schema != nil {
+			return schema
+		}
+		ref := s.GetReference().GetXRef()
+		if seen[ref] || !strings.HasPrefix(ref, "#/components/schemas/") {
+			return nil
+		}
+		seen[ref] = true
+		s = nil
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		for _, pair := range c.source.GetComponents().GetSchemas().GetAdditionalProperties() {
+			if pair.Name == name {
+				s = pair.Value
+			}
+		}
+	}
+	return nil
+}
+
+// requestBodyForRequestBodyOrReference follows a local request body reference.
+func (c *openAPI2Converter) requestBodyForRequestBodyOrReference(r *openapi3.RequestBodyOrReference) *openapi3.RequestBody {
+	if requestBody := r.GetRequestBody(); requestBody != nil {
+		return requestBody
+	}
+	name := strings.TrimPrefix(r.GetReference().GetXRef(), "#/components/requestBodies/")
+	for _, pair := range c.source.GetComponents().GetRequestBodies().GetAdditionalProperties() {
+		if pair.Name == name {
+			return pair.Value.GetRequestBody()
+		}
+	}
+	return nil
+}
+
+// headerForHeaderOrReference follows a local header reference.
+func (c *openAPI2Converter) headerForHeaderOrReference(h *openapi3.HeaderOrReference) *openapi3.Header {
+	if header := h.GetHeader(); header != nil {
+		return header
+	}
+	name := strings.TrimPrefix(h.GetReference().GetXRef(), "#/components/headers/")
+	for _, pair := range c.source.GetComponents().GetHeaders().GetAdditionalProperties() {
+		if pair.Name == name {
+			return pair.Value.GetHeader()
+		}
+	}
+	return nil
+}
+
+func buildOpenAPI2AnyForOpenAPI3Any(a *openapi3.Any) *openapi2.Any {
+	if a == nil {
+		return nil
+	}
+	return &openapi2.Any{Value: a.Value, Yaml: a.Yaml}
+}
+
+func buildOpenAPI2AnysForOpenAPI3Anys(anys []*openapi3.Any) []*openapi2.Any {
+	var result []*openapi2.Any
+	for _, a := range anys {
+		result = append(result, buildOpenAPI2AnyForOpenAPI3Any(a))
+	}
+	return result
+}
+
+func buildOpenAPI2AnyForNode(node *yaml.Node) *openapi2.Any {
+	a, _ := openapi2.NewAny(node, nil)
+	return a
+}
+
+func buildOpenAPI2VendorExtensionsForOpenAPI3(extensions []*openapi3.NamedAny) []*openapi2.NamedAny {
+	var result []*openapi2.NamedAny
+	for _, pair := range extensions {
+		result = append(result, &openapi2.NamedAny{Name: pair.Name, Value: buildOpenAPI2AnyForOpenAPI3Any(pair.Value)})
+	}
+	return result
+}
+
+func buildOpenAPI2DefaultForOpenAPI3DefaultType(defaultType *openapi3.DefaultType) *openapi2.Any {
+	if defaultType == nil {
+		return nil
+	}
+	return buildOpenAPI2AnyForNode(defaultType.ToRawInfo())
+}
+
+func buildOpenAPI2ExternalDocsForOpenAPI3ExternalDocs(docs *openapi3.ExternalDocs) *openapi2.ExternalDocs {
+	if docs == nil {
+		return nil
+	}
+	return &openapi2.ExternalDocs{
+		Description:     docs.Description,
+		Url:             docs.Url,
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(docs.SpecificationExtension),
+	}
+}
+
+func buildOpenAPI2InfoForOpenAPI3Info(info *openapi3.Info) *openapi2.Info {
+	if info == nil {
+		return nil
+	}
+	i := &openapi2.Info{
+		Title:           info.Title,
+		Version:         info.Version,
+		Description:     info.Description,
+		TermsOfService:  info.TermsOfService,
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(info.SpecificationExtension),
+	}
+	if contact := info.Contact; contact != nil {
+		i.Contact = &openapi2.Contact{
+			Name:            contact.Name,
+			Url:             contact.Url,
+			Email:           contact.Email,
+			VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(contact.SpecificationExtension),
+		}
+	}
+	if license := info.License; license != nil {
+		i.License = &openapi2.License{
+			Name:            license.Name,
+			Url:             license.Url,
+			VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(license.SpecificationExtension),
+		}
+	}
+	return i
+}
+
+func buildOpenAPI2TagsForOpenAPI3Tags(tags []*openapi3.Tag) []*openapi2.Tag {
+	var result []*openapi2.Tag
+	for _, tag := range tags {
+		result = append(result, &openapi2.Tag{
+			Name:            tag.Name,
+			Description:     tag.Description,
+			ExternalDocs:    buildOpenAPI2ExternalDocsForOpenAPI3ExternalDocs(tag.ExternalDocs),
+			VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(tag.SpecificationExtension),
+		})
+	}
+	return result
+}
+
+func buildOpenAPI2SecurityForOpenAPI3Security(security []*openapi3.SecurityRequirement) []*openapi2.SecurityRequirement {
+	var result []*openapi2.SecurityRequirement
+	for _, requirement := range security {
+		r := &openapi2.SecurityRequirement{}
+		for _, pair := range requirement.AdditionalProperties {
+			scopes := &openapi2.StringArray{}
+			if pair.Value != nil {
+				scopes.Value = pair.Value.Value
+			}
+			r.AdditionalProperties = append(r.AdditionalProperties,
+				&openapi2.NamedStringArray{Name: pair.Name, Value: scopes})
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// urlForServer expands a server URL using the default values of its variables.
+func urlForServer(server *openapi3.Server) string {
+	u := server.Url
+	for _, pair := range server.GetVariables().GetAdditionalProperties() {
+		u = strings.Replace(u, "{"+pair.Name+"}", pair.Value.GetDefault(), -1)
+	}
+	return u
+}
+
+// addOpenAPI2HostForOpenAPI3Servers collapses a list of servers into a host, base path, and schemes.
+// Servers that differ from the first only in their scheme contribute additional schemes.
+func (c *openAPI2Converter) addOpenAPI2HostForOpenAPI3Servers(d *openapi2.Document, servers []*openapi3.Server) {
+	for i, server := range servers {
+		keys := []string{"servers", strconv.Itoa(i)}
+		if server.Variables != nil && len(server.Variables.AdditionalProperties) > 0 {
+			c.warn(keys, "SERVERVARIABLES", "Server variables are not supported in OpenAPI v2, using default values.")
+		}
+		u, err := url.Parse(urlForServer(server))
+		if err != nil {
+			c.warn(keys, "SERVERURL", "Server URL could not be parsed: "+err.Error())
+			continue
+		}
+		basePath := u.Path
+		if basePath == "" {
+			basePath = "/"
+		}
+		if i == 0 {
+			d.Host = u.Host
+			d.BasePath = basePath
+		} else if u.Host != d.Host || basePath != d.BasePath {
+			c.warn(keys, "MULTIPLESERVERS", "Multiple servers are not supported in OpenAPI v2, ignoring "+server.Url+".")
+			continue
+		}
+		if u.Scheme != "" && !compiler.StringArrayContainsValue(d.Schemes, u.Scheme) {
+			d.Schemes = append(d.Schemes, u.Scheme)
+		}
+	}
+}
+
+func (c *openAPI2Converter) buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(keys []string, s *openapi3.SchemaOrReference) *openapi2.Schema {
+	if ref := s.GetReference(); ref != nil {
+		return &openapi2.Schema{XRef: openAPI2RefForOpenAPI3Ref(ref.XRef)}
+	}
+	return c.buildOpenAPI2SchemaForOpenAPI3Schema(keys, s.GetSchema())
+}
+
+func (c *openAPI2Converter) buildOpenAPI2SchemaForOpenAPI3Schema(keys []string, schema *openapi3.Schema) *openapi2.Schema {
+	if schema == nil {
+		return nil
+	}
+	s := &openapi2.Schema{
+		Format:           schema.Format,
+		Title:            schema.Title,
+		Description:      schema.Description,
+		Default:          buildOpenAPI2DefaultForOpenAPI3DefaultType(schema.Default),
+		MultipleOf:       schema.MultipleOf,
+		Maximum:          schema.Maximum,
+		ExclusiveMaximum: schema.ExclusiveMaximum,
+		Minimum:          schema.Minimum,
+		ExclusiveMinimum: schema.ExclusiveMinimum,
+		MaxLength:        schema.MaxLength,
+		MinLength:        schema.MinLength,
+		Pattern:          schema.Pattern,
+		MaxItems:         schema.MaxItems,
+		MinItems:         schema.MinItems,
+		UniqueItems:      schema.UniqueItems,
+		MaxProperties:    schema.MaxProperties,
+		MinProperties:    schema.MinProperties,
+		Required:         schema.Required,
+		Enum:             buildOpenAPI2AnysForOpenAPI3Anys(schema.Enum),
+		ReadOnly:         schema.ReadOnly,
+		ExternalDocs:     buildOpenAPI2ExternalDocsForOpenAPI3ExternalDocs(schema.ExternalDocs),
+		Example:          buildOpenAPI2AnyForOpenAPI3Any(schema.Example),
+		VendorExtension:  buildOpenAPI2VendorExtensionsForOpenAPI3(schema.SpecificationExtension),
+	}
+	if schema.Type != "" {
+		s.Type = &openapi2.TypeItem{Value: []string{schema.Type}}
+	}
+	if schema.Nullable {
+		// x-nullable is the common extension for nullable values in OpenAPI v2.
+		s.VendorExtension = append(s.VendorExtension, &openapi2.NamedAny{
+			Name:  "x-nullable",
+			Value: &openapi2.Any{Yaml: "true\n"},
+		})
+	}
+	if discriminator := schema.Discriminator; discriminator != nil {
+		s.Discriminator = discriminator.PropertyName
+		if discriminator.Mapping != nil && len(discriminator.Mapping.AdditionalProperties) > 0 {
+			c.warn(compiler.KeysWith(keys, "discriminator", "mapping"), "DISCRIMINATORMAPPING", "Discriminator mappings are not supported in OpenAPI v2.")
+		}
+	}
+	if xml := schema.Xml; xml != nil {
+		s.Xml = &openapi2.Xml{
+			Name:            xml.Name,
+			Namespace:       xml.Namespace,
+			Prefix:          xml.Prefix,
+			Attribute:       xml.Attribute,
+			Wrapped:         xml.Wrapped,
+			VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(xml.SpecificationExtension),
+		}
+	}
+	if schema.Items != nil && len(schema.Items.SchemaOrReference) > 0 {
+		s.Items = &openapi2.ItemsItem{}
+		for _, item := range schema.Items.SchemaOrReference {
+			s.Items.Schema = append(s.Items.Schema, c.buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "items"), item))
+		}
+	}
+	for i, item := range schema.AllOf {
+		s.AllOf = append(s.AllOf, c.buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "allOf", strconv.Itoa(i)), item))
+	}
+	if len(schema.OneOf) > 0 {
+		c.warn(compiler.KeysWith(keys, "oneOf"), "ONEOF", "oneOf is not supported in OpenAPI v2 and was removed.")
+	}
+	if len(schema.AnyOf) > 0 {
+		c.warn(compiler.KeysWith(keys, "anyOf"), "ANYOF", "anyOf is not supported in OpenAPI v2 and was removed.")
+	}
+	if schema.Not != nil {
+		c.warn(compiler.KeysWith(keys, "not"), "NOT", "not is not supported in OpenAPI v2 and was removed.")
+	}
+	if schema.WriteOnly {
+		c.warn(compiler.KeysWith(keys, "writeOnly"), "WRITEONLY", "writeOnly is not supported in OpenAPI v2 and was removed.")
+	}
+	if schema.Properties != nil && len(schema.Properties.AdditionalProperties) > 0 {
+		s.Properties = &openapi2.Properties{}
+		for _, pair := range schema.Properties.AdditionalProperties {
+			s.Properties.AdditionalProperties = append(s.Properties.AdditionalProperties,
+				&openapi2.NamedSchema{
+					Name:  pair.Name,
+					Value: c.buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "properties", pair.Name), pair.Value),
+				})
+		}
+	}
+	if additionalProperties := schema.AdditionalProperties; additionalProperties != nil {
+		if additionalSchema := additionalProperties.GetSchemaOrReference(); additionalSchema != nil {
+			s.AdditionalProperties = &openapi2.AdditionalPropertiesItem{
+				Oneof: &openapi2.AdditionalPropertiesItem_Schema{
+					Schema: c.buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "additionalProperties"), additionalSchema),
+				},
+			}
+		} else {
+			s.AdditionalProperties = &openapi2.AdditionalPropertiesItem{
+				Oneof: &openapi2.AdditionalPropertiesItem_Boolean{
+					Boolean: additionalProperties.GetBoolean(),
+				},
+			}
+		}
+	}
+	return s
+}
+
+// buildOpenAPI2PrimitivesItemsForOpenAPI3Schema describes the primitive types
+// that OpenAPI v2 allows for non-body parameters, headers, and their items.
+func (c *openAPI2Converter) buildOpenAPI2PrimitivesItemsForOpenAPI3Schema(keys []string, s *openapi3.SchemaOrReference) *openapi2.PrimitivesItems {
+	schema := c.schemaForSchemaOrReference(s)
+	if schema == nil {
+		c.warn(compiler.KeysWith(keys, "schema"), "SCHEMA", "Schema could not be resolved to a primitive type.")
+		return &openapi2.PrimitivesItems{}
+	}
+	if schema.Type == "object" || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 || len(schema.AllOf) > 0 {
+		c.warn(compiler.KeysWith(keys, "schema"), "COMPLEXSCHEMA", "Only primitive and array schemas are supported here in OpenAPI v2.")
+	}
+	items := &openapi2.PrimitivesItems{
+		Type:             schema.Type,
+		Format:           schema.Format,
+		Default:          buildOpenAPI2DefaultForOpenAPI3DefaultType(schema.Default),
+		Maximum:          schema.Maximum,
+		ExclusiveMaximum: schema.ExclusiveMaximum,
+		Minimum:          schema.Minimum,
+		ExclusiveMinimum: schema.ExclusiveMinimum,
+		MaxLength:        schema.MaxLength,
+		MinLength:        schema.MinLength,
+		Pattern:          schema.Pattern,
+		MaxItems:         schema.MaxItems,
+		MinItems:         schema.MinItems,
+		UniqueItems:      schema.UniqueItems,
+		Enum:             buildOpenAPI2AnysForOpenAPI3Anys(schema.Enum),
+		MultipleOf:       schema.MultipleOf,
+	}
+	if items.Type == "object" {
+		items.Type = "string"
+	}
+	if schema.Items != nil && len(schema.Items.SchemaOrReference) > 0 {
+		items.Items = c.buildOpenAPI2PrimitivesItemsForOpenAPI3Schema(compiler.KeysWith(keys, "items"), schema.Items.SchemaOrReference[0])
+	}
+	return items
+}
+
+// collectionFormatForStyle maps a parameter style to its closest collection format.
+func collectionFormatForStyle(in, style string) string {
+	switch style {
+	case "spaceDelimited":
+		return "ssv"
+	case "pipeDelimited":
+		return "pipes"
+	case "form", "":
+		// form is the default style for query parameters and explodes by default.
+		// Compiled parameters don't record whether explode was specified, so
+		// explicit form styles are treated like the default.
+		if in == "query" {
+			return "multi"
+		}
+	}
+	return "csv"
+}
+
+func (c *openAPI2Converter) buildOpenAPI2ParameterForOpenAPI3Parameter(keys []string, p *openapi3.Parameter) *openapi2.Parameter {
+	schema := p.Schema
+	if schema == nil && p.Content != nil && len(p.Content.AdditionalProperties) > 0 {
+		schema = p.Content.AdditionalProperties[0].Value.GetSchema()
+	}
+	items := c.buildOpenAPI2PrimitivesItemsForOpenAPI3Schema(keys, schema)
+	if items.Type == "array" {
+		items.CollectionFormat = collectionFormatForStyle(p.In, p.Style)
+	}
+	vendorExtension := buildOpenAPI2VendorExtensionsForOpenAPI3(p.SpecificationExtension)
+	var nonBodyParameter *openapi2.NonBodyParameter
+	switch p.In {
+	case "query":
+		nonBodyParameter = &openapi2.NonBodyParameter{
+			Oneof: &openapi2.NonBodyParameter_QueryParameterSubSchema{
+				QueryParameterSubSchema: &openapi2.QueryParameterSubSchema{
+					Required: p.Required, In: p.In, Description: p.Description, Name: p.Name, AllowEmptyValue: p.AllowEmptyValue,
+					Type: items.Type, Format: items.Format, Items: items.Items, CollectionFormat: items.CollectionFormat, Default: items.Default,
+					Maximum: items.Maximum, ExclusiveMaximum: items.ExclusiveMaximum, Minimum: items.Minimum, ExclusiveMinimum: items.ExclusiveMinimum,
+					MaxLength: items.MaxLength, MinLength: items.MinLength, Pattern: items.Pattern, MaxItems: items.MaxItems, MinItems: items.MinItems,
+					UniqueItems: items.UniqueItems, Enum: items.Enum, MultipleOf: items.MultipleOf, VendorExtension: vendorExtension,
+				},
+			},
+		}
+	case "header":
+		nonBodyParameter = &openapi2.NonBodyParameter{
+			Oneof: &openapi2.NonBodyParameter_HeaderParameterSubSchema{
+				HeaderParameterSubSchema: &openapi2.HeaderParameterSubSchema{
+					Required: p.Required, In: p.In, Description: p.Description, Name: p.Name,
+					Type: items.Type, Format: items.Format, Items: items.Items, CollectionFormat: items.CollectionFormat, Default: items.Default,
+					Maximum: items.Maximum, ExclusiveMaximum: items.ExclusiveMaximum, Minimum: items.Minimum, ExclusiveMinimum: items.ExclusiveMinimum,
+					MaxLength: items.MaxLength, MinLength: items.MinLength, Pattern: items.Pattern, MaxItems: items.MaxItems, MinItems: items.MinItems,
+					UniqueItems: items.UniqueItems, Enum: items.Enum, MultipleOf: items.MultipleOf, VendorExtension: vendorExtension,
+				},
+			},
+		}
+	case "path":
+		nonBodyParameter = &openapi2.NonBodyParameter{
+			Oneof: &openapi2.NonBodyParameter_PathParameterSubSchema{
+				PathParameterSubSchema: &openapi2.PathParameterSubSchema{
+					Required: true, In: p.In, Description: p.Description, Name: p.Name,
+					Type: items.Type, Format: items.Format, Items: items.Items, CollectionFormat: items.CollectionFormat, Default: items.Default,
+					Maximum: items.Maximum, ExclusiveMaximum: items.ExclusiveMaximum, Minimum: items.Minimum, ExclusiveMinimum: items.ExclusiveMinimum,
+					MaxLength: items.MaxLength, MinLength: items.MinLength, Pattern: items.Pattern, MaxItems: items.MaxItems, MinItems: items.MinItems,
+					UniqueItems: items.UniqueItems, Enum: items.Enum, MultipleOf: items.MultipleOf, VendorExtension: vendorExtension,
+				},
+			},
+		}
+	case "cookie":
+		c.warn(keys, "COOKIEPARAMETER", "Cookie parameters are not supported in OpenAPI v2 and were removed.")
+		return nil
+	default:
+		c.warn(keys, "PARAMETERLOCATION", "Unknown parameter location "+p.In+".")
+		return nil
+	}
+	return &openapi2.Parameter{
+		Oneof: &openapi2.Parameter_NonBodyParameter{NonBodyParameter: nonBodyParameter},
+	}
+}
+
+func (c *openAPI2Converter) buildOpenAPI2ParametersItemForOpenAPI3ParameterOrReference(keys []string, p *openapi3.ParameterOrReference) *openapi2.ParametersItem {
+	if ref := p.GetReference(); ref != nil {
+		return &openapi2.ParametersItem{
+			Oneof: &openapi2.ParametersItem_JsonReference{
+				JsonReference: &openapi2.JsonReference{XRef: openAPI2RefForOpenAPI3Ref(ref.XRef), Description: ref.Description},
+			},
+		}
+	}
+	parameter := c.buildOpenAPI2ParameterForOpenAPI3Parameter(keys, p.GetParameter())
+	if parameter == nil {
+		return nil
+	}
+	return &openapi2.ParametersItem{
+		Oneof: &openapi2.ParametersItem_Parameter{Parameter: parameter},
+	}
+}
+
+func (c *openAPI2Converter) buildOpenAPI2ParametersForOpenAPI3Parameters(keys []string, parameters []*openapi3.ParameterOrReference) []*openapi2.ParametersItem {
+	var result []*openapi2.ParametersItem
+	for i, p := range parameters {
+		if item := c.buildOpenAPI2ParametersItemForOpenAPI3ParameterOrReference(compiler.KeysWith(keys, "parameters", strconv.Itoa(i)), p); item != nil {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func isFormMediaType(mediaType string) bool {
+	return mediaType == formMediaType || mediaType == multipartFormType
+}
+
+// mediaTypeNames returns the names of the media types in a content map.
+func mediaTypeNames(content *openapi3.MediaTypes) []string {
+	var names []string
+	for _, pair := range content.GetAdditionalProperties() {
+		names = append(names, pair.Name)
+	}
+	return names
+}
+
+// preferredMediaType returns the JSON media type in a content map if there is one, or else the first.
+func preferredMediaType(content *openapi3.MediaTypes) *openapi3.NamedMediaType {
+	pairs := content.GetAdditionalProperties()
+	for _, pair := range pairs {
+		if pair.Name == defaultMediaType {
+			return pair
+		}
+	}
+	if len(pairs) > 0 {
+		return pairs[0]
+	}
+	return nil
+}
+
+// buildOpenAPI2ParametersForOpenAPI3RequestBody lowers a request body into a body parameter or a list of form parameters.
+func (c *openAPI2Converter) buildOpenAPI2ParametersForOpenAPI3RequestBody(keys []string, r *openapi3.RequestBodyOrReference) ([]*openapi2.ParametersItem, []string) {
+	requestBody := c.requestBodyForRequestBodyOrReference(r)
+	if requestBody == nil {
+		c.warn(keys, "REQUESTBODY", "Request body could not be resolved.")
+		return nil, nil
+	}
+	consumes := mediaTypeNames(requestBody.Content)
+	formMediaTypes := make([]string, 0)
+	for _, mediaType := range consumes {
+		if isFormMediaType(mediaType) {
+			formMediaTypes = append(formMediaTypes, mediaType)
+		}
+	}
+	if len(formMediaTypes) > 0 {
+		if len(formMediaTypes) < len(consumes) {
+			c.warn(compiler.KeysWith(keys, "content"), "MIXEDMEDIATYPES", "Form and non-form media types can't be combined in OpenAPI v2, keeping only form media types.")
+		}
+		return c.buildOpenAPI2FormDataParametersForOpenAPI3RequestBody(keys, requestBody, formMediaTypes[0]), formMediaTypes
+	}
+	if ref := r.GetReference(); ref != nil {
+		return []*openapi2.ParametersItem{
+			{
+				Oneof: &openapi2.ParametersItem_JsonReference{
+					JsonReference: &openapi2.JsonReference{XRef: openAPI2RefForOpenAPI3Ref(ref.XRef)},
+				},
+			},
+		}, consumes
+	}
+	body := c.buildOpenAPI2BodyParameterForOpenAPI3RequestBody(keys, requestBody)
+	return []*openapi2.ParametersItem{
+		{
+			Oneof: &openapi2.ParametersItem_Parameter{
+				Parameter: &openapi2.Parameter{
+					Oneof: &openapi2.Parameter_BodyParameter{BodyParameter: body},
+				},
+			},
+		},
+	}, consumes
+}
+
+func (c *openAPI2Converter) buildOpenAPI2BodyParameterForOpenAPI3RequestBody(keys []string, requestBody *openapi3.RequestBody) *openapi2.BodyParameter {
+	body := &openapi2.BodyParameter{
+		Name:            "body",
+		In:              "body",
+		Description:     requestBody.Description,
+		Required:        requestBody.Required,
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(requestBody.SpecificationExtension),
+	}
+	if pair := preferredMediaType(requestBody.Content); pair != nil {
+		body.Schema = c.buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "content", pair.Name, "schema"), pair.Value.Schema)
+		for _, other := range requestBody.Content.AdditionalProperties {
+			if other != pair && !proto.Equal(other.Value.Schema, pair.Value.Schema) {
+				c.warn(compiler.KeysWith(keys, "content", other.Name), "MEDIATYPESCHEMA", "OpenAPI v2 uses one schema for all media types, ignoring the schema for "+other.Name+".")
+			}
+		}
+	}
+	if body.Schema == nil {
+		body.Schema = &openapi2.Schema{}
+	}
+	return body
+}
+
+func (c *openAPI2Converter) buildOpenAPI2FormDataParametersForOpenAPI3RequestBody(keys []string, requestBody *openapi3.RequestBody, mediaType string) []*openapi2.ParametersItem {
+	var result []*openapi2.ParametersItem
+	var schemaOrReference *openapi3.SchemaOrReference
+	for _, pair := range requestBody.Content.AdditionalProperties {
+		if pair.Name == mediaType {
+			schemaOrReference = pair.Value.Schema
+		}
+	}
+	keys = compiler.KeysWith(keys, "content", mediaType, "schema")
+	schema := c.schemaForSchemaOrReference(schemaOrReference)
+	if schema == nil || schema.Properties == nil {
+		c.warn(keys, "FORMSCHEMA", "Form request bodies must be described with an object schema.")
+		return nil
+	}
+	for _, pair := range schema.Properties.AdditionalProperties {
+		items := c.buildOpenAPI2PrimitivesItemsForOpenAPI3Schema(compiler.KeysWith(keys, "properties", pair.Name), pair.Value)
+		if items.Type == "string" && items.Format == "binary" {
+			items.Type = "file"
+			items.Format = ""
+		}
+		if items.Type == "array" {
+			items.CollectionFormat = "multi"
+		}
+		description := ""
+		if property := c.schemaForSchemaOrReference(pair.Value); property != nil {
+			description = property.Description
+		}
+		result = append(result, &openapi2.ParametersItem{
+			Oneof: &openapi2.ParametersItem_Parameter{
+				Parameter: &openapi2.Parameter{
+					Oneof: &openapi2.Parameter_NonBodyParameter{
+						NonBodyParameter: &openapi2.NonBodyParameter{
+							Oneof: &openapi2.NonBodyParameter_FormDataParameterSubSchema{
+								FormDataParameterSubSchema: &openapi2.FormDataParameterSubSchema{
+									Required: compiler.StringArrayContainsValue(schema.Required, pair.Name), In: "formData", Description: description, Name: pair.Name,
+									Type: items.Type, Format: items.Format, Items: items.Items, CollectionFormat: items.CollectionFormat, Default: items.Default,
+									Maximum: items.Maximum, ExclusiveMaximum: items.ExclusiveMaximum, Minimum: items.Minimum, ExclusiveMinimum: items.ExclusiveMinimum,
+									MaxLength: items.MaxLength, MinLength: items.MinLength, Pattern: items.Pattern, MaxItems: items.MaxItems, MinItems: items.MinItems,
+									UniqueItems: items.UniqueItems, Enum: items.Enum, MultipleOf: items.MultipleOf,
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	return result
+}
+
+func (c *openAPI2Converter) buildOpenAPI2HeadersForOpenAPI3Headers(keys []string, headers *openapi3.HeadersOrReferences) *openapi2.Headers {
+	if headers == nil || len(headers.AdditionalProperties) == 0 {
+		return nil
+	}
+	h := &openapi2.Headers{}
+	for _, pair := range headers.AdditionalProperties {
+		header := c.headerForHeaderOrReference(pair.Value)
+		if header == nil {
+			c.warn(compiler.KeysWith(keys, pair.Name), "HEADER", "Header could not be resolved.")
+			continue
+		}
+		items := c.buildOpenAPI2PrimitivesItemsForOpenAPI3Schema(compiler.KeysWith(keys, pair.Name), header.Schema)
+		h.AdditionalProperties = append(h.AdditionalProperties, &openapi2.NamedHeader{
+			Name: pair.Name,
+			Value: &openapi2.Header{
+				Description: header.Description,
+				Type:        items.Type, Format: items.Format, Items: items.Items, CollectionFormat: items.CollectionFormat, Default: items.Default,
+				Maximum: items.Maximum, ExclusiveMaximum: items.ExclusiveMaximum, Minimum: items.Minimum, ExclusiveMinimum: items.ExclusiveMinimum,
+				MaxLength: items.MaxLength, MinLength: items.MinLength, Pattern: items.Pattern, MaxItems: items.MaxItems, MinItems: items.MinItems,
+				UniqueItems: items.UniqueItems, Enum: items.Enum, MultipleOf: items.MultipleOf,
+				VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(header.SpecificationExtension),
+			},
+		})
+	}
+	return h
+}
+
+func (c *openAPI2Converter) buildOpenAPI2ResponseForOpenAPI3Response(keys []string, response *openapi3.Response) *openapi2.Response {
+	r := &openapi2.Response{
+		Description:     response.Description,
+		Headers:         c.buildOpenAPI2HeadersForOpenAPI3Headers(compiler.KeysWith(keys, "headers"), response.Headers),
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(response.SpecificationExtension),
+	}
+	if pair := preferredMediaType(response.Content); pair != nil && pair.Value.Schema != nil {
+		r.Schema = &openapi2.SchemaItem{
+			Oneof: &openapi2.SchemaItem_Schema{
+				Schema: c.buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "content", pair.Name, "schema"), pair.Value.Schema),
+			},
+		}
+		for _, other := range response.Content.AdditionalProperties {
+			if other != pair && !proto.Equal(other.Value.Schema, pair.Value.Schema) {
+				c.warn(compiler.KeysWith(keys, "content", other.Name), "MEDIATYPESCHEMA", "OpenAPI v2 uses one schema for all media types, ignoring the schema for "+other.Name+".")
+			}
+		}
+	}
+	for _, pair := range response.GetContent().GetAdditionalProperties() {
+		if pair.Value.Example != nil {
+			if r.Examples == nil {
+				r.Examples = &openapi2.Examples{}
+			}
+			r.Examples.AdditionalProperties = append(r.Examples.AdditionalProperties,
+				&openapi2.NamedAny{Name: pair.Name, Value: buildOpenAPI2AnyForOpenAPI3Any(pair.Value.Example)})
+		}
+	}
+	if response.Links != nil && len(response.Links.AdditionalProperties) > 0 {
+		c.warn(compiler.KeysWith(keys, "links"), "LINKS", "Links are not supported in OpenAPI v2 and were removed.")
+	}
+	return r
+}
+
+func (c *openAPI2Converter) buildOpenAPI2ResponseValueForOpenAPI3ResponseOrReference(keys []string, r *openapi3.ResponseOrReference) *openapi2.ResponseValue {
+	if ref := r.GetReference(); ref != nil {
+		return &openapi2.ResponseValue{
+			Oneof: &openapi2.ResponseValue_JsonReference{
+				JsonReference: &openapi2.JsonReference{XRef: openAPI2RefForOpenAPI3Ref(ref.XRef), Description: ref.Description},
+			},
+		}
+	}
+	return &openapi2.ResponseValue{
+		Oneof: &openapi2.ResponseValue_Response{
+			Response: c.buildOpenAPI2ResponseForOpenAPI3Response(keys, r.GetResponse()),
+		},
+	}
+}
+
+// buildOpenAPI2ResponsesForOpenAPI3Responses converts responses and returns the media types that they produce.
+func (c *openAPI2Converter) buildOpenAPI2ResponsesForOpenAPI3Responses(keys []string, responses *openapi3.Responses) (*openapi2.Responses, []string) {
+	if responses == nil {
+		return nil, nil
+	}
+	r := &openapi2.Responses{
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(responses.SpecificationExtension),
+	}
+	produces := make([]string, 0)
+	addProduces := func(value *openapi3.ResponseOrReference) {
+		for _, mediaType := range mediaTypeNames(value.GetResponse().GetContent()) {
+			if !compiler.StringArrayContainsValue(produces, mediaType) {
+				produces = append(produces, mediaType)
+			}
+		}
+	}
+	for _, pair := range responses.ResponseOrReference {
+		addProduces(pair.Value)
+		r.ResponseCode = append(r.ResponseCode, &openapi2.NamedResponseValue{
+			Name:  pair.Name,
+			Value: c.buildOpenAPI2ResponseValueForOpenAPI3ResponseOrReference(compiler.KeysWith(keys, pair.Name), pair.Value),
+		})
+	}
+	if responses.Default != nil {
+		addProduces(responses.Default)
+		r.ResponseCode = append(r.ResponseCode, &openapi2.NamedResponseValue{
+			Name:  "default",
+			Value: c.buildOpenAPI2ResponseValueForOpenAPI3ResponseOrReference(compiler.KeysWith(keys, "default"), responses.Default),
+		})
+	}
+	return r, produces
+}
+
+func (c *openAPI2Converter) buildOpenAPI2OperationForOpenAPI3Operation(keys []string, operation *openapi3.Operation) *openapi2.Operation {
+	if operation == nil {
+		return nil
+	}
+	o := &openapi2.Operation{
+		Tags:            operation.Tags,
+		Summary:         operation.Summary,
+		Description:     operation.Description,
+		ExternalDocs:    buildOpenAPI2ExternalDocsForOpenAPI3ExternalDocs(operation.ExternalDocs),
+		OperationId:     operation.OperationId,
+		Parameters:      c.buildOpenAPI2ParametersForOpenAPI3Parameters(keys, operation.Parameters),
+		Deprecated:      operation.Deprecated,
+		Security:        buildOpenAPI2SecurityForOpenAPI3Security(operation.Security),
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(operation.SpecificationExtension),
+	}
+	if operation.RequestBody != nil {
+		parameters, consumes := c.buildOpenAPI2ParametersForOpenAPI3RequestBody(compiler.KeysWith(keys, "requestBody"), operation.RequestBody)
+		o.Parameters = append(o.Parameters, parameters...)
+		o.Consumes = consumes
+	}
+	o.Responses, o.Produces = c.buildOpenAPI2ResponsesForOpenAPI3Responses(compiler.KeysWith(keys, "responses"), operation.Responses)
+	if operation.Callbacks != nil && len(operation.Callbacks.AdditionalProperties) > 0 {
+		c.warn(compiler.KeysWith(keys, "callbacks"), "CALLBACKS", "Callbacks are not supported in OpenAPI v2 and were removed.")
+	}
+	if len(operation.Servers) > 0 {
+		c.warn(compiler.KeysWith(keys, "servers"), "OPERATIONSERVERS", "Operation servers are not supported in OpenAPI v2 and were removed.")
+	}
+	return o
+}
+
+func (c *openAPI2Converter) buildOpenAPI2PathItemForOpenAPI3PathItem(keys []string, pathItem *openapi3.PathItem) *openapi2.PathItem {
+	p := &openapi2.PathItem{
+		XRef:            pathItem.XRef,
+		Parameters:      c.buildOpenAPI2ParametersForOpenAPI3Parameters(keys, pathItem.Parameters),
+		Get:             c.buildOpenAPI2OperationForOpenAPI3Operation(compiler.KeysWith(keys, "get"), pathItem.Get),
+		Put:             c.buildOpenAPI2OperationForOpenAPI3Operation(compiler.KeysWith(keys, "put"), pathItem.Put),
+		Post:            c.buildOpenAPI2OperationForOpenAPI3Operation(compiler.KeysWith(keys, "post"), pathItem.Post),
+		Delete:          c.buildOpenAPI2OperationForOpenAPI3Operation(compiler.KeysWith(keys, "delete"), pathItem.Delete),
+		Options:         c.buildOpenAPI2OperationForOpenAPI3Operation(compiler.KeysWith(keys, "options"), pathItem.Options),
+		Head:            c.buildOpenAPI2OperationForOpenAPI3Operation(compiler.KeysWith(keys, "head"), pathItem.Head),
+		Patch:           c.buildOpenAPI2OperationForOpenAPI3Operation(compiler.KeysWith(keys, "patch"), pathItem.Patch),
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(pathItem.SpecificationExtension),
+	}
+	if pathItem.Trace != nil {
+		c.warn(compiler.KeysWith(keys, "trace"), "TRACE", "TRACE operations are not supported in OpenAPI v2 and were removed.")
+	}
+	if len(pathItem.Servers) > 0 {
+		c.warn(compiler.KeysWith(keys, "servers"), "PATHSERVERS", "Path item servers are not supported in OpenAPI v2 and were removed.")
+	}
+	return p
+}
+
+func buildOpenAPI2ScopesForOpenAPI3Scopes(scopes *openapi3.Strings) *openapi2.Oauth2Scopes {
+	s := &openapi2.Oauth2Scopes{}
+	for _, pair := range scopes.GetAdditionalProperties() {
+		s.AdditionalProperties = append(s.AdditionalProperties, &openapi2.NamedString{Name: pair.Name, Value: pair.Value})
+	}
+	return s
+}
+
+func (c *openAPI2Converter) buildOpenAPI2SecurityDefinitionsItemForOpenAPI3SecurityScheme(keys []string, s *openapi3.SecurityScheme) *openapi2.SecurityDefinitionsItem {
+	vendorExtension := buildOpenAPI2VendorExtensionsForOpenAPI3(s.SpecificationExtension)
+	switch s.Type {
+	case "http":
+		if strings.ToLower(s.Scheme) == "basic" {
+			return &openapi2.SecurityDefinitionsItem{
+				Oneof: &openapi2.SecurityDefinitionsItem_BasicAuthenticationSecurity{
+					BasicAuthenticationSecurity: &openapi2.BasicAuthenticationSecurity{
+						Type: "basic", Description: s.Description, VendorExtension: vendorExtension,
+					},
+				},
+			}
+		}
+		// Other HTTP schemes are passed in the Authorization header.
+		c.warn(keys, "HTTPSCHEME", "The "+s.Scheme+" HTTP authentication scheme is not supported in OpenAPI v2, using an Authorization header API key.")
+		return &openapi2.SecurityDefinitionsItem{
+			Oneof: &openapi2.SecurityDefinitionsItem_ApiKeySecurity{
+				ApiKeySecurity: &openapi2.ApiKeySecurity{
+					Type: "apiKey", Name: "Authorization", In: "header", Description: s.Description, VendorExtension: vendorExtension,
+				},
+			},
+		}
+	case "apiKey":
+		if s.In == "cookie" {
+			c.warn(keys, "COOKIEPARAMETER", "Cookie API keys are not supported in OpenAPI v2 and were removed.")
+			return nil
+		}
+		return &openapi2.SecurityDefinitionsItem{
+			Oneof: &openapi2.SecurityDefinitionsItem_ApiKeySecurity{
+				ApiKeySecurity: &openapi2.ApiKeySecurity{
+					Type: "apiKey", Name: s.Name, In: s.In, Description: s.Description, VendorExtension: vendorExtension,
+				},
+			},
+		}
+	case "oauth2":
+		flows := s.Flows
+		count := 0
+		var item *openapi2.SecurityDefinitionsItem
+		if flow := flows.GetImplicit(); flow != nil {
+			count++
+			item = &openapi2.SecurityDefinitionsItem{
+				Oneof: &openapi2.SecurityDefinitionsItem_Oauth2ImplicitSecurity{
+					Oauth2ImplicitSecurity: &openapi2.Oauth2ImplicitSecurity{
+						Type: "oauth2", Flow: "implicit", Scopes: buildOpenAPI2ScopesForOpenAPI3Scopes(flow.Scopes),
+						AuthorizationUrl: flow.AuthorizationUrl, Description: s.Description, VendorExtension: vendorExtension,
+					},
+				},
+			}
+		}
+		if flow := flows.GetPassword(); flow != nil {
+			count++
+			item = &openapi2.SecurityDefinitionsItem{
+				Oneof: &openapi2.SecurityDefinitionsItem_Oauth2PasswordSecurity{
+					Oauth2PasswordSecurity: &openapi2.Oauth2PasswordSecurity{
+						Type: "oauth2", Flow: "password", Scopes: buildOpenAPI2ScopesForOpenAPI3Scopes(flow.Scopes),
+						TokenUrl: flow.TokenUrl, Description: s.Description, VendorExtension: vendorExtension,
+					},
+				},
+			}
+		}
+		if flow := flows.GetClientCredentials(); flow != nil {
+			count++
+			item = &openapi2.SecurityDefinitionsItem{
+				Oneof: &openapi2.SecurityDefinitionsItem_Oauth2ApplicationSecurity{
+					Oauth2ApplicationSecurity: &openapi2.Oauth2ApplicationSecurity{
+						Type: "oauth2", Flow: "application", Scopes: buildOpenAPI2ScopesForOpenAPI3Scopes(flow.Scopes),
+						TokenUrl: flow.TokenUrl, Description: s.Description, VendorExtension: vendorExtension,
+					},
+				},
+			}
+		}
+		if flow := flows.GetAuthorizationCode(); flow != nil {
+			count++
+			item = &openapi2.SecurityDefinitionsItem{
+				Oneof: &openapi2.SecurityDefinitionsItem_Oauth2AccessCodeSecurity{
+					Oauth2AccessCodeSecurity: &openapi2.Oauth2AccessCodeSecurity{
+						Type: "oauth2", Flow: "accessCode", Scopes: buildOpenAPI2ScopesForOpenAPI3Scopes(flow.Scopes),
+						AuthorizationUrl: flow.AuthorizationUrl, TokenUrl: flow.TokenUrl, Description: s.Description, VendorExtension: vendorExtension,
+					},
+				},
+			}
+		}
+		if count > 1 {
+			c.warn(compiler.KeysWith(keys, "flows"), "OAUTHFLOWS", "OpenAPI v2 allows only one OAuth2 flow per security definition, using the last one.")
+		}
+		if item == nil {
+			c.warn(compiler.KeysWith(keys, "flows"), "OAUTHFLOWS", "OAuth2 security scheme has no flows.")
+		}
+		return item
+	default:
+		c.warn(keys, "SECURITYSCHEME", "The "+s.Type+" security scheme type is not supported in OpenAPI v2 and was removed.")
+		return nil
+	}
+}
+
+func (c *openAPI2Converter) addOpenAPI2DefinitionsForOpenAPI3Components(d *openapi2.Document, components *openapi3.Components) {
+	if components == nil {
+		return
+	}
+	keys := []string{"components"}
+	if components.Schemas != nil && len(components.Schemas.AdditionalProperties) > 0 {
+		d.Definitions = &openapi2.Definitions{}
+		for _, pair := range components.Schemas.AdditionalProperties {
+			d.Definitions.AdditionalProperties = append(d.Definitions.AdditionalProperties,
+				&openapi2.NamedSchema{
+					Name:  pair.Name,
+					Value: c.buildOpenAPI2SchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "schemas", pair.Name), pair.Value),
+				})
+		}
+	}
+	addParameter := func(name string, parameter *openapi2.Parameter) {
+		if d.Parameters == nil {
+			d.Parameters = &openapi2.ParameterDefinitions{}
+		}
+		d.Parameters.AdditionalProperties = append(d.Parameters.AdditionalProperties,
+			&openapi2.NamedParameter{Name: name, Value: parameter})
+	}
+	for _, pair := range components.GetParameters().GetAdditionalProperties() {
+		p := pair.Value.GetParameter()
+		if p == nil {
+			c.warn(compiler.KeysWith(keys, "parameters", pair.Name), "PARAMETERREFERENCE", "Parameter definitions can't be references in OpenAPI v2.")
+			continue
+		}
+		if parameter := c.buildOpenAPI2ParameterForOpenAPI3Parameter(compiler.KeysWith(keys, "parameters", pair.Name), p); parameter != nil {
+			addParameter(pair.Name, parameter)
+		}
+	}
+	// Request bodies become body parameters. Form request bodies are inlined where they are used.
+	for _, pair := range components.GetRequestBodies().GetAdditionalProperties() {
+		requestBody := pair.Value.GetRequestBody()
+		if requestBody == nil {
+			continue
+		}
+		if pair := preferredMediaType(requestBody.Content); pair != nil && isFormMediaType(pair.Name) {
+			continue
+		}
+		for _, other := range components.GetParameters().GetAdditionalProperties() {
+			if other.Name == pair.Name {
+				c.warn(compiler.KeysWith(keys, "requestBodies", pair.Name), "REQUESTBODYNAME", "Request body "+pair.Name+" has the same name as a parameter.")
+			}
+		}
+		addParameter(pair.Name, &openapi2.Parameter{
+			Oneof: &openapi2.Parameter_BodyParameter{
+				BodyParameter: c.buildOpenAPI2BodyParameterForOpenAPI3RequestBody(compiler.KeysWith(keys, "requestBodies", pair.Name), requestBody),
+			},
+		})
+	}
+	if components.Responses != nil && len(components.Responses.AdditionalProperties) > 0 {
+		d.Responses = &openapi2.ResponseDefinitions{}
+		for _, pair := range components.Responses.AdditionalProperties {
+			response := pair.Value.GetResponse()
+			if response == nil {
+				c.warn(compiler.KeysWith(keys, "responses", pair.Name), "RESPONSEREFERENCE", "Response definitions can't be references in OpenAPI v2.")
+				continue
+			}
+			d.Responses.AdditionalProperties = append(d.Responses.AdditionalProperties,
+				&openapi2.NamedResponse{
+					Name:  pair.Name,
+					Value: c.buildOpenAPI2ResponseForOpenAPI3Response(compiler.KeysWith(keys, "responses", pair.Name), response),
+				})
+		}
+	}
+	for _, pair := range components.GetSecuritySchemes().GetAdditionalProperties() {
+		scheme := pair.Value.GetSecurityScheme()
+		if scheme == nil {
+			continue
+		}
+		item := c.buildOpenAPI2SecurityDefinitionsItemForOpenAPI3SecurityScheme(compiler.KeysWith(keys, "securitySchemes", pair.Name), scheme)
+		if item == nil {
+			continue
+		}
+		if d.SecurityDefinitions == nil {
+			d.SecurityDefinitions = &openapi2.SecurityDefinitions{}
+		}
+		d.SecurityDefinitions.AdditionalProperties = append(d.SecurityDefinitions.AdditionalProperties,
+			&openapi2.NamedSecurityDefinitionsItem{Name: pair.Name, Value: item})
+	}
+	if components.Callbacks != nil && len(components.Callbacks.AdditionalProperties) > 0 {
+		c.warn(compiler.KeysWith(keys, "callbacks"), "CALLBACKS", "Callbacks are not supported in OpenAPI v2 and were removed.")
+	}
+	if components.Links != nil && len(components.Links.AdditionalProperties) > 0 {
+		c.warn(compiler.KeysWith(keys, "links"), "LINKS", "Links are not supported in OpenAPI v2 and were removed.")
+	}
+}
+
+// OpenAPIv3ToOpenAPIv2 returns an OpenAPI v2 representation of an OpenAPI v3 document
+// along with warnings about any parts of the document that could not be converted.
+func OpenAPIv3ToOpenAPIv2(d3 *openapi3.Document) (*openapi2.Document, []*plugins.Message, error) {
+	if d3 == nil {
+		return nil, nil, errors.New("no OpenAPI v3 document to convert")
+	}
+	c := &openAPI2Converter{source: d3, messageList: messageList{messages: make([]*plugins.Message, 0)}}
+	d := &openapi2.Document{
+		Swagger:         "2.0",
+		Info:            buildOpenAPI2InfoForOpenAPI3Info(d3.Info),
+		Security:        buildOpenAPI2SecurityForOpenAPI3Security(d3.Security),
+		Tags:            buildOpenAPI2TagsForOpenAPI3Tags(d3.Tags),
+		ExternalDocs:    buildOpenAPI2ExternalDocsForOpenAPI3ExternalDocs(d3.ExternalDocs),
+		VendorExtension: buildOpenAPI2VendorExtensionsForOpenAPI3(d3.SpecificationExtension),
+	}
+	c.addOpenAPI2HostForOpenAPI3Servers(d, d3.Servers)
+	d.Paths = &openapi2.Paths{}
+	if d3.Paths != nil {
+		for _, pair := range d3.Paths.Path {
+			d.Paths.Path = append(d.Paths.Path, &openapi2.NamedPathItem{
+				Name:  pair.Name,
+				Value: c.buildOpenAPI2PathItemForOpenAPI3PathItem([]string{"paths", pair.Name}, pair.Value),
+			})
+		}
+		d.Paths.VendorExtension = buildOpenAPI2VendorExtensionsForOpenAPI3(d3.Paths.SpecificationExtension)
+	}
+	c.addOpenAPI2DefinitionsForOpenAPI3Components(d, d3.Components)
+	return d, c.messages, nil
+}
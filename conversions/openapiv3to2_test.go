@@ -0,0 +1,131 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+func TestOpenAPIv3ToOpenAPIv2Warnings(t *testing.T) {
+	bytes, err := ioutil.ReadFile("../testdata/v3.0/yaml/unconvertible.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d3, err := openapi3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d2, messages, err := OpenAPIv3ToOpenAPIv2(d3)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := map[string]string{
+		"MULTIPLESERVERS": "servers/2",
+		"COOKIEPARAMETER": "paths//pets/post/parameters/0",
+		"CALLBACKS":       "paths//pets/post/callbacks",
+		"ONEOF":           "components/schemas/Pet/properties/kind/oneOf",
+		"HTTPSCHEME":      "components/securitySchemes/bearer",
+	}
+	if len(messages) != len(expected) {
+		t.Errorf("expected %d messages, got %d: %+v", len(expected), len(messages), messages)
+	}
+	for _, message := range messages {
+		if message.Level != plugins.Message_WARNING {
+			t.Errorf("unexpected level for %s: %s", message.Code, message.Level)
+		}
+		if keys, ok := expected[message.Code]; !ok || keys != strings.Join(message.Keys, "/") {
+			t.Errorf("unexpected message %s at %s", message.Code, strings.Join(message.Keys, "/"))
+		}
+	}
+	if d2.Host != "api.example.com" || d2.BasePath != "/v1" || strings.Join(d2.Schemes, ",") != "https,http" {
+		t.Errorf("servers were not collapsed: %s %s %v", d2.Host, d2.BasePath, d2.Schemes)
+	}
+	post := d2.Paths.Path[0].Value.Post
+	if len(post.Parameters) != 2 || post.Parameters[1].GetParameter().GetBodyParameter() == nil {
+		t.Errorf("expected a query parameter and a body parameter, got %+v", post.Parameters)
+	}
+	put := d2.Paths.Path[1].Value.Put
+	if len(put.Parameters) != 3 {
+		t.Fatalf("expected a path parameter and two form parameters, got %+v", put.Parameters)
+	}
+	photo := put.Parameters[1].GetParameter().GetNonBodyParameter().GetFormDataParameterSubSchema()
+	if photo == nil || photo.Type != "file" || !photo.Required {
+		t.Errorf("expected a required file parameter, got %+v", photo)
+	}
+}
+
+func TestOpenAPIv3StyleToCollectionFormat(t *testing.T) {
+	d3, err := openapi3.ParseDocument([]byte(`openapi: 3.0.0
+info:
+  title: Styles
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: a
+          in: query
+          schema: {type: array, items: {type: string}}
+        - name: b
+          in: query
+          style: form
+          schema: {type: array, items: {type: string}}
+        - name: c
+          in: query
+          style: form
+          explode: true
+          schema: {type: array, items: {type: string}}
+        - name: d
+          in: query
+          style: spaceDelimited
+          schema: {type: array, items: {type: string}}
+        - name: e
+          in: header
+          schema: {type: array, items: {type: string}}
+      responses:
+        "200":
+          description: OK
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d2, _, err := OpenAPIv3ToOpenAPIv2(d3)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// An explicit form style explodes by default, like an omitted style.
+	expected := []string{"multi", "multi", "multi", "ssv", "csv"}
+	parameters := d2.Paths.Path[0].Value.Get.Parameters
+	if len(parameters) != len(expected) {
+		t.Fatalf("expected %d parameters, got %d", len(expected), len(parameters))
+	}
+	for i, parameter := range parameters {
+		items := parameter.GetParameter().GetNonBodyParameter()
+		var format string
+		if q := items.GetQueryParameterSubSchema(); q != nil {
+			format = q.CollectionFormat
+		} else {
+			format = items.GetHeaderParameterSubSchema().CollectionFormat
+		}
+		if format != expected[i] {
+			t.Errorf("parameter %d: expected collectionFormat %s, got %s", i, expected[i], format)
+		}
+	}
+}
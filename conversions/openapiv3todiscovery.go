@@ -0,0 +1,519 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/gnostic/compiler"
+	discovery "github.com/google/gnostic/discovery"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// discoveryConverter holds the state of an OpenAPI v3 to Discovery conversion.
+// Constructs that can't be represented in Discovery are reported as messages.
+type discoveryConverter struct {
+	messageList
+	components *openapi3.Components
+}
+
+// discoveryNameForTitle derives an API name from a title, e.g. "Swagger Petstore" becomes "swaggerpetstore".
+func discoveryNameForTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "api"
+	}
+	return b.String()
+}
+
+// discoveryRefForOpenAPI3Ref converts a schema reference into a Discovery reference,
+// which is just the name of a schema in the same document.
+func (c *discoveryConverter) discoveryRefForOpenAPI3Ref(keys []string, ref string) string {
+	if strings.HasPrefix(ref, "#/components/schemas/") {
+		return strings.TrimPrefix(ref, "#/components/schemas/")
+	}
+	c.warn(compiler.KeysWith(keys, "$ref"), "REFERENCE", "Only references to schemas in components can be converted, keeping "+ref+".")
+	return ref
+}
+
+func discoveryStringForAny(a *openapi3.Any) string {
+	if a == nil {
+		return ""
+	}
+	return strings.TrimSpace(a.Yaml)
+}
+
+func discoveryStringForDefault(d *openapi3.DefaultType) string {
+	if d == nil {
+		return ""
+	}
+	switch v := d.Oneof.(type) {
+	case *openapi3.DefaultType_Number:
+		return strconv.FormatFloat(v.Number, 'f', -1, 64)
+	case *openapi3.DefaultType_Boolean:
+		return strconv.FormatBool(v.Boolean)
+	case *openapi3.DefaultType_String_:
+		return v.String_
+	}
+	return ""
+}
+
+func discoveryStringForNumber(n float64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func (c *discoveryConverter) buildDiscoverySchemaForOpenAPI3SchemaOrReference(keys []string, s *openapi3.SchemaOrReference) *discovery.Schema {
+	if ref := s.GetReference(); ref != nil {
+		return &discovery.Schema{XRef: c.discoveryRefForOpenAPI3Ref(keys, ref.XRef)}
+	}
+	return c.buildDiscoverySchemaForOpenAPI3Schema(keys, s.GetSchema())
+}
+
+func (c *discoveryConverter) buildDiscoverySchemaForOpenAPI3Schema(keys []string, schema *openapi3.Schema) *discovery.Schema {
+	if schema == nil {
+		return &discovery.Schema{Type: "any"}
+	}
+	s := &discovery.Schema{
+		Type:        schema.Type,
+		Description: schema.Description,
+		Default:     discoveryStringForDefault(schema.Default),
+		Format:      schema.Format,
+		Pattern:     schema.Pattern,
+		Minimum:     discoveryStringForNumber(schema.Minimum),
+		Maximum:     discoveryStringForNumber(schema.Maximum),
+		ReadOnly:    schema.ReadOnly,
+	}
+	for _, e := range schema.Enum {
+		s.Enum = append(s.Enum, discoveryStringForAny(e))
+	}
+	if len(schema.AllOf) == 1 {
+		// A single allOf entry is a common way to annotate a reference.
+		if ref := schema.AllOf[0].GetReference(); ref != nil {
+			s.XRef = c.discoveryRefForOpenAPI3Ref(compiler.KeysWith(keys, "allOf", "0"), ref.XRef)
+			return s
+		}
+	}
+	if len(schema.AllOf) > 0 {
+		if merged := c.mergedOpenAPI3SchemaForAllOf(schema); merged != nil {
+			return c.buildDiscoverySchemaForOpenAPI3Schema(keys, merged)
+		}
+	}
+	if len(schema.AllOf) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		c.warn(keys, "COMPOSITESCHEMA", "Schema composition is not supported in Discovery, using a schema of type any.")
+		s.Type = "any"
+	}
+	if s.Type == "" {
+		if schema.Properties != nil {
+			s.Type = "object"
+		} else {
+			s.Type = "any"
+		}
+	}
+	if schema.Items != nil && len(schema.Items.SchemaOrReference) > 0 {
+		s.Items = c.buildDiscoverySchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "items"), schema.Items.SchemaOrReference[0])
+	}
+	if schema.Properties != nil && len(schema.Properties.AdditionalProperties) > 0 {
+		s.Properties = &discovery.Schemas{}
+		for _, pair := range schema.Properties.AdditionalProperties {
+			property := c.buildDiscoverySchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "properties", pair.Name), pair.Value)
+			for _, required := range schema.Required {
+				if required == pair.Name {
+					property.Required = true
+				}
+			}
+			s.Properties.AdditionalProperties = append(s.Properties.AdditionalProperties,
+				&discovery.NamedSchema{Name: pair.Name, Value: property})
+		}
+	}
+	if additionalProperties := schema.AdditionalProperties.GetSchemaOrReference(); additionalProperties != nil {
+		s.AdditionalProperties = c.buildDiscoverySchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "additionalProperties"), additionalProperties)
+	}
+	return s
+}
+
+// mergedOpenAPI3SchemaForAllOf combines the properties of allOf members into a single object schema.
+// Discovery has no schema composition, so members that are references must be resolvable locally.
+func (c *discoveryConverter) mergedOpenAPI3SchemaForAllOf(schema *openapi3.Schema) *openapi3.Schema {
+	merged := &openapi3.Schema{
+		Type:        "object",
+		Description: schema.Description,
+		Properties:  &openapi3.Properties{},
+		Required:    append([]string{}, schema.Required...),
+	}
+	members := append([]*openapi3.SchemaOrReference{}, schema.AllOf...)
+	if schema.Properties != nil {
+		members = append(members, &openapi3.SchemaOrReference{
+			Oneof: &openapi3.SchemaOrReference_Schema{Schema: &openapi3.Schema{Properties: schema.Properties}},
+		})
+	}
+	seen := make(map[string]bool)
+	for len(members) > 0 {
+		member := members[0]
+		members = members[1:]
+		if ref := member.GetReference(); ref != nil {
+			if !strings.HasPrefix(ref.XRef, "#/components/schemas/") {
+				return nil
+			}
+			name := strings.TrimPrefix(ref.XRef, "#/components/schemas/")
+			var resolved *openapi3.SchemaOrReference
+			for _, pair := range c.components.GetSchemas().GetAdditionalProperties() {
+				if pair.Name == name {
+					resolved = pair.Value
+				}
+			}
+			if resolved == nil || seen[name] {
+				return nil
+			}
+			seen[name] = true
+			members = append([]*openapi3.SchemaOrReference{resolved}, members...)
+			continue
+		}
+		m := member.GetSchema()
+		if len(m.OneOf) > 0 || len(m.AnyOf) > 0 {
+			return nil
+		}
+		members = append(append([]*openapi3.SchemaOrReference{}, m.AllOf...), members...)
+		merged.Required = append(merged.Required, m.Required...)
+		if m.Description != "" && merged.Description == "" {
+			merged.Description = m.Description
+		}
+		if m.Properties != nil {
+			merged.Properties.AdditionalProperties = append(merged.Properties.AdditionalProperties, m.Properties.AdditionalProperties...)
+		}
+	}
+	return merged
+}
+
+func (c *discoveryConverter) buildDiscoveryParameterForOpenAPI3Parameter(keys []string, p *openapi3.Parameter) *discovery.Parameter {
+	if p.In != "path" && p.In != "query" {
+		c.warn(keys, "PARAMETERLOCATION", "Only path and query parameters are supported in Discovery, "+p.In+" parameter "+p.Name+" was removed.")
+		return nil
+	}
+	s := c.buildDiscoverySchemaForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "schema"), p.Schema)
+	parameter := &discovery.Parameter{
+		Type:        s.Type,
+		XRef:        s.XRef,
+		Description: p.Description,
+		Default:     s.Default,
+		Required:    p.Required,
+		Format:      s.Format,
+		Pattern:     s.Pattern,
+		Minimum:     s.Minimum,
+		Maximum:     s.Maximum,
+		Enum:        s.Enum,
+		Location:    p.In,
+	}
+	// Discovery describes arrays of simple values as repeated parameters.
+	if s.Type == "array" && s.Items != nil {
+		parameter.Repeated = true
+		parameter.Type = s.Items.Type
+		parameter.Format = s.Items.Format
+		parameter.Enum = s.Items.Enum
+	}
+	return parameter
+}
+
+// resourceNamesForPath returns the literal segments of a path, which are used to nest resources.
+func resourceNamesForPath(path string) []string {
+	names := make([]string, 0)
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && !strings.Contains(segment, "{") {
+			names = append(names, segment)
+		}
+	}
+	return names
+}
+
+func getDiscoveryResourceForNames(d *discovery.Document, names []string) *discovery.Resource {
+	resources := &d.Resources
+	var resource *discovery.Resource
+	for _, name := range names {
+		if *resources == nil {
+			*resources = &discovery.Resources{}
+		}
+		resource = nil
+		for _, pair := range (*resources).AdditionalProperties {
+			if pair.Name == name {
+				resource = pair.Value
+			}
+		}
+		if resource == nil {
+			resource = &discovery.Resource{}
+			(*resources).AdditionalProperties = append((*resources).AdditionalProperties,
+				&discovery.NamedResource{Name: name, Value: resource})
+		}
+		resources = &resource.Resources
+	}
+	return resource
+}
+
+// uniqueDiscoverySchemaName returns a name that isn't used by any schema in a document.
+// Numbers are appended to names that are already used.
+func uniqueDiscoverySchemaName(d *discovery.Document, name string) string {
+	used := make(map[string]bool)
+	for _, pair := range d.Schemas.AdditionalProperties {
+		used[pair.Name] = true
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	return unique
+}
+
+// schemaNameForOpenAPI3SchemaOrReference returns the name of a referenced schema.
+// Inline schemas are added to the document with the specified name,
+// which is made unique if a schema with that name already exists.
+func (c *discoveryConverter) schemaNameForOpenAPI3SchemaOrReference(keys []string, d *discovery.Document, s *openapi3.SchemaOrReference, name string) string {
+	if ref := s.GetReference(); ref != nil {
+		return c.discoveryRefForOpenAPI3Ref(keys, ref.XRef)
+	}
+	name = uniqueDiscoverySchemaName(d, name)
+	schema := c.buildDiscoverySchemaForOpenAPI3Schema(keys, s.GetSchema())
+	schema.Id = name
+	d.Schemas.AdditionalProperties = append(d.Schemas.AdditionalProperties,
+		&discovery.NamedSchema{Name: name, Value: schema})
+	return name
+}
+
+// jsonSchemaForMediaTypes returns the name and schema of the JSON media type in a content map.
+func jsonSchemaForMediaTypes(content *openapi3.MediaTypes) (string, *openapi3.SchemaOrReference) {
+	for _, pair := range content.GetAdditionalProperties() {
+		if pair.Name == defaultMediaType || strings.HasSuffix(pair.Name, "+json") {
+			return pair.Name, pair.Value.Schema
+		}
+	}
+	return "", nil
+}
+
+// successfulResponseForOpenAPI3Responses returns the status code and first 2XX response of an operation.
+func successfulResponseForOpenAPI3Responses(responses *openapi3.Responses) (string, *openapi3.Response) {
+	for _, pair := range responses.GetResponseOrReference() {
+		if strings.HasPrefix(pair.Name, "2") {
+			return pair.Name, pair.Value.GetResponse()
+		}
+	}
+	return "", nil
+}
+
+func methodNameForOperation(operation *openapi3.Operation, httpMethod string) string {
+	if operation.OperationId != "" {
+		return operation.OperationId
+	}
+	return strings.ToLower(httpMethod)
+}
+
+// buildDiscoveryMethodForOpenAPI3Operation builds a method and adds any inline request and
+// response schemas to the document. These are named with schemaPrefix, which must
+// distinguish the method from others that have the same name.
+func (c *discoveryConverter) buildDiscoveryMethodForOpenAPI3Operation(keys []string, d *discovery.Document, methodName string, schemaPrefix string, path string, httpMethod string, pathParameters []*openapi3.ParameterOrReference, operation *openapi3.Operation) *discovery.Method {
+	m := &discovery.Method{
+		Path:        strings.TrimPrefix(path, "/"),
+		FlatPath:    strings.TrimPrefix(path, "/"),
+		HttpMethod:  httpMethod,
+		Description: operation.Description,
+	}
+	if m.Description == "" {
+		m.Description = operation.Summary
+	}
+	m.Id = strings.Join(append(append([]string{d.Name}, resourceNamesForPath(path)...), methodName), ".")
+	parameters := append(append([]*openapi3.ParameterOrReference{}, pathParameters...), operation.Parameters...)
+	for i, p := range parameters {
+		// Path item parameters are in the parent of the operation.
+		parameterKeys := compiler.KeysWith(keys[:len(keys)-1], "parameters", strconv.Itoa(i))
+		if i >= len(pathParameters) {
+			parameterKeys = compiler.KeysWith(keys, "parameters", strconv.Itoa(i-len(pathParameters)))
+		}
+		parameter := p.GetParameter()
+		if parameter == nil {
+			c.warn(parameterKeys, "PARAMETERREFERENCE", "Parameter references are not supported in Discovery, "+p.GetReference().GetXRef()+" was removed.")
+			continue
+		}
+		discoveryParameter := c.buildDiscoveryParameterForOpenAPI3Parameter(parameterKeys, parameter)
+		if discoveryParameter == nil {
+			continue
+		}
+		if m.Parameters == nil {
+			m.Parameters = &discovery.Parameters{}
+		}
+		m.Parameters.AdditionalProperties = append(m.Parameters.AdditionalProperties,
+			&discovery.NamedParameter{Name: parameter.Name, Value: discoveryParameter})
+		if discoveryParameter.Required {
+			m.ParameterOrder = append(m.ParameterOrder, parameter.Name)
+		}
+	}
+	if requestBody := operation.RequestBody; requestBody != nil {
+		if ref := requestBody.GetReference(); ref != nil {
+			c.warn(compiler.KeysWith(keys, "requestBody"), "REQUESTBODYREFERENCE", "Request body references are not supported in Discovery, "+ref.XRef+" was removed.")
+		} else if mediaType, schema := jsonSchemaForMediaTypes(requestBody.GetRequestBody().Content); schema != nil {
+			m.Request = &discovery.Request{
+				XRef:          c.schemaNameForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "requestBody", "content", mediaType, "schema"), d, schema, schemaPrefix+"Request"),
+				ParameterName: "resource",
+			}
+		}
+	}
+	if code, response := successfulResponseForOpenAPI3Responses(operation.Responses); response != nil {
+		if mediaType, schema := jsonSchemaForMediaTypes(response.Content); schema != nil {
+			m.Response = &discovery.Response{
+				XRef: c.schemaNameForOpenAPI3SchemaOrReference(compiler.KeysWith(keys, "responses", code, "content", mediaType, "schema"), d, schema, schemaPrefix+"Response"),
+			}
+		}
+	}
+	for _, requirement := range operation.Security {
+		for _, pair := range requirement.AdditionalProperties {
+			m.Scopes = append(m.Scopes, pair.Value.GetValue()...)
+		}
+	}
+	return m
+}
+
+func (c *discoveryConverter) addDiscoveryMethodsForOpenAPI3PathItem(d *discovery.Document, path string, pathItem *openapi3.PathItem) {
+	operations := []struct {
+		httpMethod string
+		operation  *openapi3.Operation
+	}{
+		{"GET", pathItem.Get},
+		{"PUT", pathItem.Put},
+		{"POST", pathItem.Post},
+		{"DELETE", pathItem.Delete},
+		{"OPTIONS", pathItem.Options},
+		{"HEAD", pathItem.Head},
+		{"PATCH", pathItem.Patch},
+		{"TRACE", pathItem.Trace},
+	}
+	for _, o := range operations {
+		if o.operation == nil {
+			continue
+		}
+		keys := []string{"paths", path, strings.ToLower(o.httpMethod)}
+		methodName := methodNameForOperation(o.operation, o.httpMethod)
+		resourceNames := resourceNamesForPath(path)
+		// Methods belong to the resource named by the path, or to the API if the path has no literal segments.
+		var methods **discovery.Methods
+		if resource := getDiscoveryResourceForNames(d, resourceNames); resource != nil {
+			methods = &resource.Methods
+		} else {
+			methods = &d.Methods
+		}
+		if *methods == nil {
+			*methods = &discovery.Methods{}
+		}
+		for _, pair := range (*methods).AdditionalProperties {
+			if pair.Name == methodName {
+				renamed := strings.ToLower(o.httpMethod) + strings.Title(methodName)
+				c.warn(keys, "METHODNAME", "Method name "+methodName+" is already used by another method of this resource, using "+renamed+".")
+				methodName = renamed
+			}
+		}
+		// Inline schemas are named for their methods, which are only unique within their resources.
+		schemaPrefix := strings.Title(methodName)
+		if o.operation.OperationId == "" {
+			schemaPrefix = ""
+			for _, name := range resourceNames {
+				schemaPrefix += strings.Title(name)
+			}
+			schemaPrefix += strings.Title(methodName)
+		}
+		method := c.buildDiscoveryMethodForOpenAPI3Operation(keys, d, methodName, schemaPrefix, path, o.httpMethod, pathItem.Parameters, o.operation)
+		(*methods).AdditionalProperties = append((*methods).AdditionalProperties,
+			&discovery.NamedMethod{Name: methodName, Value: method})
+	}
+}
+
+func buildDiscoveryAuthForOpenAPI3Components(components *openapi3.Components) *discovery.Auth {
+	scopes := &discovery.Scopes{}
+	for _, pair := range components.GetSecuritySchemes().GetAdditionalProperties() {
+		flows := pair.Value.GetSecurityScheme().GetFlows()
+		for _, flow := range []*openapi3.OauthFlow{flows.GetImplicit(), flows.GetPassword(), flows.GetClientCredentials(), flows.GetAuthorizationCode()} {
+			for _, scope := range flow.GetScopes().GetAdditionalProperties() {
+				scopes.AdditionalProperties = append(scopes.AdditionalProperties,
+					&discovery.NamedScope{Name: scope.Name, Value: &discovery.Scope{Description: scope.Value}})
+			}
+		}
+	}
+	if len(scopes.AdditionalProperties) == 0 {
+		return nil
+	}
+	return &discovery.Auth{Oauth2: &discovery.Oauth2{Scopes: scopes}}
+}
+
+// OpenAPIv3ToDiscovery returns a Discovery representation of an OpenAPI v3 document
+// along with warnings about any parts of the document that could not be converted.
+func OpenAPIv3ToDiscovery(d3 *openapi3.Document) (*discovery.Document, []*plugins.Message, error) {
+	if d3 == nil {
+		return nil, nil, errors.New("no OpenAPI v3 document to convert")
+	}
+	c := &discoveryConverter{components: d3.Components, messageList: messageList{messages: make([]*plugins.Message, 0)}}
+	d := &discovery.Document{
+		Kind:             "discovery#restDescription",
+		DiscoveryVersion: "v1",
+		Protocol:         "rest",
+		Name:             discoveryNameForTitle(d3.GetInfo().GetTitle()),
+		Version:          d3.GetInfo().GetVersion(),
+		Title:            d3.GetInfo().GetTitle(),
+		Description:      d3.GetInfo().GetDescription(),
+	}
+	d.Id = d.Name + ":" + d.Version
+	if docs := d3.ExternalDocs; docs != nil {
+		d.DocumentationLink = docs.Url
+	}
+	if len(d3.Servers) > 0 {
+		if len(d3.Servers) > 1 {
+			c.warn([]string{"servers"}, "MULTIPLESERVERS", fmt.Sprintf("Discovery documents have one root URL, using only the first of %d servers.", len(d3.Servers)))
+		}
+		u, err := url.Parse(urlForServer(d3.Servers[0]))
+		if err != nil {
+			return nil, nil, err
+		}
+		d.ServicePath = strings.TrimPrefix(u.Path, "/")
+		if d.ServicePath != "" && !strings.HasSuffix(d.ServicePath, "/") {
+			d.ServicePath += "/"
+		}
+		d.BasePath = "/" + d.ServicePath
+		// Relative server URLs only describe the service path.
+		if u.Scheme != "" && u.Host != "" {
+			d.RootUrl = u.Scheme + "://" + u.Host + "/"
+			d.BaseUrl = d.RootUrl + d.ServicePath
+		} else {
+			c.warn([]string{"servers", "0", "url"}, "RELATIVESERVERURL", "Server URL "+u.String()+" is relative, so the root URL is unknown.")
+		}
+	}
+	d.Schemas = &discovery.Schemas{}
+	for _, pair := range d3.GetComponents().GetSchemas().GetAdditionalProperties() {
+		schema := c.buildDiscoverySchemaForOpenAPI3SchemaOrReference([]string{"components", "schemas", pair.Name}, pair.Value)
+		schema.Id = pair.Name
+		d.Schemas.AdditionalProperties = append(d.Schemas.AdditionalProperties,
+			&discovery.NamedSchema{Name: pair.Name, Value: schema})
+	}
+	for _, pair := range d3.GetPaths().GetPath() {
+		c.addDiscoveryMethodsForOpenAPI3PathItem(d, pair.Name, pair.Value)
+	}
+	d.Auth = buildDiscoveryAuthForOpenAPI3Components(d3.Components)
+	return d, c.messages, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"strings"
+	"testing"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+func TestOpenAPIv3ToDiscovery(t *testing.T) {
+	d3, err := openapi3.ParseDocument([]byte(`openapi: 3.0.0
+info:
+  title: Inline
+  version: 1.0.0
+servers:
+  - url: /v1
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: A list of pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+  /owners:
+    get:
+      responses:
+        "200":
+          description: A list of owners.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: integer
+components:
+  schemas:
+    OwnersGetResponse:
+      type: string
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d, messages, err := OpenAPIv3ToDiscovery(d3)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	names := make([]string, 0)
+	for _, pair := range d.Schemas.AdditionalProperties {
+		names = append(names, pair.Name)
+	}
+	if got, want := strings.Join(names, ","), "OwnersGetResponse,PetsGetResponse,OwnersGetResponse2"; got != want {
+		t.Errorf("unexpected schemas: got %s, want %s", got, want)
+	}
+	pets := d.Resources.AdditionalProperties[0].Value.Methods.AdditionalProperties[0].Value
+	owners := d.Resources.AdditionalProperties[1].Value.Methods.AdditionalProperties[0].Value
+	if pets.Response.XRef != "PetsGetResponse" || owners.Response.XRef != "OwnersGetResponse2" {
+		t.Errorf("unexpected responses: %s and %s", pets.Response.XRef, owners.Response.XRef)
+	}
+	if d.RootUrl != "" || d.BaseUrl != "" || d.ServicePath != "v1/" || d.BasePath != "/v1/" {
+		t.Errorf("unexpected URLs: rootUrl %q baseUrl %q servicePath %q basePath %q", d.RootUrl, d.BaseUrl, d.ServicePath, d.BasePath)
+	}
+	if len(messages) != 1 || messages[0].Code != "RELATIVESERVERURL" || strings.Join(messages[0].Keys, "/") != "servers/0/url" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diff compares two versions of an API description and classifies
+// the changes between them. Breaking changes are reported as ERROR messages
+// and additive changes are reported as INFO messages.
+package diff
+
+import (
+	"strings"
+
+	"github.com/google/gnostic/compiler"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// Message codes used to classify changes.
+const (
+	AddedOperation        = "ADDEDOPERATION"
+	RemovedOperation      = "REMOVEDOPERATION"
+	AddedParameter        = "ADDEDPARAMETER"
+	RemovedParameter      = "REMOVEDPARAMETER"
+	NewRequiredParameter  = "NEWREQUIREDPARAMETER"
+	OptionalParameter     = "OPTIONALPARAMETER"
+	AddedRequestBody      = "ADDEDREQUESTBODY"
+	RemovedRequestBody    = "REMOVEDREQUESTBODY"
+	RequiredRequestBody   = "REQUIREDREQUESTBODY"
+	AddedResponse         = "ADDEDRESPONSE"
+	RemovedResponse       = "REMOVEDRESPONSE"
+	AddedMediaType        = "ADDEDMEDIATYPE"
+	RemovedMediaType      = "REMOVEDMEDIATYPE"
+	ChangedType           = "CHANGEDTYPE"
+	ChangedReference      = "CHANGEDREFERENCE"
+	NarrowedEnum          = "NARROWEDENUM"
+	WidenedEnum           = "WIDENEDENUM"
+	AddedProperty         = "ADDEDPROPERTY"
+	RemovedProperty       = "REMOVEDPROPERTY"
+	NewRequiredProperty   = "NEWREQUIREDPROPERTY"
+	AddedSchema           = "ADDEDSCHEMA"
+	RemovedSchema         = "REMOVEDSCHEMA"
+	RenamedSchema         = "RENAMEDSCHEMA"
+	AddedSecurityScheme   = "ADDEDSECURITYSCHEME"
+	RemovedSecurityScheme = "REMOVEDSECURITYSCHEME"
+)
+
+// comparison collects the messages produced while comparing two documents.
+type comparison struct {
+	messages []*plugins.Message
+}
+
+func (c *comparison) breaking(keys []string, code string, text string) {
+	c.add(plugins.Message_ERROR, keys, code, text)
+}
+
+func (c *comparison) additive(keys []string, code string, text string) {
+	c.add(plugins.Message_INFO, keys, code, text)
+}
+
+func (c *comparison) add(level plugins.Message_Level, keys []string, code string, text string) {
+	c.messages = append(c.messages,
+		&plugins.Message{
+			Level: level,
+			Code:  code,
+			Text:  text,
+			Keys:  keys})
+}
+
+// HasBreakingChanges returns true if any of the messages describes a breaking change.
+func HasBreakingChanges(messages *plugins.Messages) bool {
+	for _, message := range messages.GetMessages() {
+		if message.Level >= plugins.Message_ERROR {
+			return true
+		}
+	}
+	return false
+}
+
+// direction describes whether a schema is sent by clients, returned to them, or both.
+type direction int
+
+const (
+	input direction = 1 << iota
+	output
+)
+
+// compareEnums reports values that were removed from or added to an enumeration.
+// Narrowing an enumeration breaks clients that send values,
+// and widening one breaks clients that receive values.
+func (c *comparison) compareEnums(keys []string, before, after []string, dir direction) {
+	if len(before) == 0 && len(after) == 0 {
+		return
+	}
+	var narrowed, widened string
+	switch {
+	case len(before) == 0:
+		narrowed = "Values were restricted to " + strings.Join(after, ", ") + "."
+	case len(after) == 0:
+		widened = "Values are no longer restricted."
+	default:
+		if removed := difference(before, after); len(removed) > 0 {
+			narrowed = "Values were removed: " + strings.Join(removed, ", ") + "."
+		}
+		if added := difference(after, before); len(added) > 0 {
+			widened = "Values were added: " + strings.Join(added, ", ") + "."
+		}
+	}
+	if narrowed != "" {
+		if dir&input != 0 {
+			c.breaking(keys, NarrowedEnum, narrowed)
+		} else {
+			c.additive(keys, NarrowedEnum, narrowed)
+		}
+	}
+	if widened != "" {
+		if dir&output != 0 {
+			c.breaking(keys, WidenedEnum, widened)
+		} else {
+			c.additive(keys, WidenedEnum, widened)
+		}
+	}
+}
+
+// difference returns the values in a that are not in b.
+func difference(a, b []string) []string {
+	var result []string
+	for _, x := range a {
+		if !compiler.StringArrayContainsValue(b, x) {
+			result = append(result, x)
+		}
+	}
+	return result
+}
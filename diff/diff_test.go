@@ -0,0 +1,221 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+const beforeV3 = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [available, pending, sold]
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+        "404":
+          description: not found
+  /pets/{id}:
+    delete:
+      operationId: deletePet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "204":
+          description: deleted
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+`
+
+const afterV3 = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 2.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: string
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [available, sold]
+        - name: sort
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+    post:
+      operationId: createPet
+      responses:
+        "201":
+          description: created
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        tag:
+          type: string
+    Problem:
+      type: object
+      properties:
+        message:
+          type: string
+`
+
+func messageCodes(messages *plugins.Messages, level plugins.Message_Level) []string {
+	var codes []string
+	for _, message := range messages.Messages {
+		if message.Level == level {
+			codes = append(codes, message.Code+" "+strings.Join(message.Keys, "/"))
+		}
+	}
+	return codes
+}
+
+func checkCodes(t *testing.T, label string, got, want []string) {
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected %s changes:\n%s\nwanted:\n%s",
+			label, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCompareOpenAPIv3(t *testing.T) {
+	before, err := openapi3.ParseDocument([]byte(beforeV3))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	after, err := openapi3.ParseDocument([]byte(afterV3))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	messages := CompareOpenAPIv3(before, after)
+	if !HasBreakingChanges(messages) {
+		t.Errorf("expected breaking changes")
+	}
+	checkCodes(t, "breaking", messageCodes(messages, plugins.Message_ERROR), []string{
+		"RENAMEDSCHEMA components/schemas/Error",
+		"NEWREQUIREDPARAMETER paths//pets/get/parameters/limit",
+		"CHANGEDTYPE paths//pets/get/parameters/limit/schema",
+		"NARROWEDENUM paths//pets/get/parameters/status/schema/enum",
+		"REMOVEDRESPONSE paths//pets/get/responses/404",
+		"REMOVEDOPERATION paths//pets/{id}/delete",
+	})
+	checkCodes(t, "additive", messageCodes(messages, plugins.Message_INFO), []string{
+		"ADDEDPROPERTY components/schemas/Pet/properties/tag",
+		"ADDEDPARAMETER paths//pets/get/parameters/sort",
+		"ADDEDOPERATION paths//pets/post",
+	})
+}
+
+func TestCompareOpenAPIv3Identical(t *testing.T) {
+	d, err := openapi3.ParseDocument([]byte(beforeV3))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	messages := CompareOpenAPIv3(d, d)
+	if len(messages.Messages) != 0 {
+		t.Errorf("unexpected changes: %+v", messages.Messages)
+	}
+}
+
+func TestCompareOpenAPIv2(t *testing.T) {
+	b, err := ioutil.ReadFile("../examples/v2.0/yaml/petstore.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	before, err := openapi2.ParseDocument(b)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	after, err := openapi2.ParseDocument(b)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// Remove an operation and a definition from the second version.
+	after.Paths.Path[0].Value.Post = nil
+	after.Definitions.AdditionalProperties = after.Definitions.AdditionalProperties[1:]
+	messages, err := CompareOpenAPIv2(before, after)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	checkCodes(t, "breaking", messageCodes(messages, plugins.Message_ERROR), []string{
+		"REMOVEDSCHEMA definitions/Pet",
+		"REMOVEDOPERATION paths//pets/post",
+	})
+}
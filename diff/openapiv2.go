@@ -0,0 +1,59 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/conversions"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// CompareOpenAPIv2 compares two OpenAPI v2 documents and returns messages
+// describing the changes made between them. The documents are converted to
+// OpenAPI v3 for comparison and the keys of the resulting messages are
+// rewritten to refer to the corresponding OpenAPI v2 sections.
+func CompareOpenAPIv2(before, after *openapi2.Document) (*plugins.Messages, error) {
+	// Conversion warnings are ignored because both documents lose the same details.
+	before3, _, err := conversions.OpenAPIv2ToOpenAPIv3WithMessages(before)
+	if err != nil {
+		return nil, err
+	}
+	after3, _, err := conversions.OpenAPIv2ToOpenAPIv3WithMessages(after)
+	if err != nil {
+		return nil, err
+	}
+	messages := CompareOpenAPIv3(before3, after3)
+	for _, message := range messages.Messages {
+		message.Keys = openAPI2KeysForOpenAPI3Keys(message.Keys)
+	}
+	return messages, nil
+}
+
+// openAPI2KeysForOpenAPI3Keys maps the keys of OpenAPI v3 components
+// to the OpenAPI v2 sections that they were converted from.
+func openAPI2KeysForOpenAPI3Keys(keys []string) []string {
+	if len(keys) < 2 || keys[0] != "components" {
+		return keys
+	}
+	switch keys[1] {
+	case "schemas":
+		return compiler.KeysWith([]string{"definitions"}, keys[2:]...)
+	case "securitySchemes":
+		return compiler.KeysWith([]string{"securityDefinitions"}, keys[2:]...)
+	default:
+		return keys
+	}
+}
@@ -0,0 +1,605 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// openAPI3Comparison compares two OpenAPI v3 documents.
+type openAPI3Comparison struct {
+	comparison
+	before *openapi3.Document
+	after  *openapi3.Document
+	// renamed maps the names of renamed schemas to their new names.
+	renamed map[string]string
+	// usage records whether each schema is sent or received by clients.
+	usage map[string]direction
+}
+
+// CompareOpenAPIv3 compares two OpenAPI v3 documents and returns messages
+// describing the changes made between them.
+func CompareOpenAPIv3(before, after *openapi3.Document) *plugins.Messages {
+	c := &openAPI3Comparison{
+		before:  before,
+		after:   after,
+		renamed: make(map[string]string),
+		usage:   make(map[string]direction),
+	}
+	c.recordUsage(before)
+	c.recordUsage(after)
+	c.compareSchemaComponents()
+	c.compareSecuritySchemes()
+	c.comparePaths()
+	return &plugins.Messages{Messages: c.messages}
+}
+
+// operations returns the operations of a path item keyed by lowercase method name.
+func operations(pathItem *openapi3.PathItem) map[string]*openapi3.Operation {
+	result := make(map[string]*openapi3.Operation)
+	for method, operation := range map[string]*openapi3.Operation{
+		"get":     pathItem.GetGet(),
+		"put":     pathItem.GetPut(),
+		"post":    pathItem.GetPost(),
+		"delete":  pathItem.GetDelete(),
+		"options": pathItem.GetOptions(),
+		"head":    pathItem.GetHead(),
+		"patch":   pathItem.GetPatch(),
+		"trace":   pathItem.GetTrace(),
+	} {
+		if operation != nil {
+			result[method] = operation
+		}
+	}
+	return result
+}
+
+// sortedKeys returns the keys of a map in a stable order.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func pathItems(d *openapi3.Document) map[string]*openapi3.PathItem {
+	result := make(map[string]*openapi3.PathItem)
+	for _, pair := range d.GetPaths().GetPath() {
+		result[pair.Name] = pair.Value
+	}
+	return result
+}
+
+func (c *openAPI3Comparison) comparePaths() {
+	beforePaths := pathItems(c.before)
+	afterPaths := pathItems(c.after)
+	names := make(map[string]bool)
+	for name := range beforePaths {
+		names[name] = true
+	}
+	for name := range afterPaths {
+		names[name] = true
+	}
+	for _, path := range sortedKeys(names) {
+		beforeOperations := operations(beforePaths[path])
+		afterOperations := operations(afterPaths[path])
+		methods := make(map[string]bool)
+		for method := range beforeOperations {
+			methods[method] = true
+		}
+		for method := range afterOperations {
+			methods[method] = true
+		}
+		for _, method := range sortedKeys(methods) {
+			keys := []string{"paths", path, method}
+			label := strings.ToUpper(method) + " " + path
+			beforeOperation, afterOperation := beforeOperations[method], afterOperations[method]
+			switch {
+			case afterOperation == nil:
+				c.breaking(keys, RemovedOperation, "Operation "+label+" was removed.")
+			case beforeOperation == nil:
+				c.additive(keys, AddedOperation, "Operation "+label+" was added.")
+			default:
+				c.compareParameters(keys,
+					c.parameters(c.before, beforePaths[path], beforeOperation),
+					c.parameters(c.after, afterPaths[path], afterOperation))
+				c.compareRequestBodies(keys,
+					c.requestBody(c.before, beforeOperation.RequestBody),
+					c.requestBody(c.after, afterOperation.RequestBody))
+				c.compareResponses(keys,
+					c.responses(c.before, beforeOperation.Responses),
+					c.responses(c.after, afterOperation.Responses))
+			}
+		}
+	}
+}
+
+// parameterKey identifies a parameter by its location and name.
+func parameterKey(p *openapi3.Parameter) string {
+	return p.In + ":" + p.Name
+}
+
+// parameters returns the parameters of an operation, including those
+// inherited from its path item, keyed by location and name.
+func (c *openAPI3Comparison) parameters(d *openapi3.Document, pathItem *openapi3.PathItem, operation *openapi3.Operation) map[string]*openapi3.Parameter {
+	result := make(map[string]*openapi3.Parameter)
+	for _, list := range [][]*openapi3.ParameterOrReference{pathItem.GetParameters(), operation.GetParameters()} {
+		for _, p := range list {
+			if parameter := resolveParameter(d, p); parameter != nil {
+				result[parameterKey(parameter)] = parameter
+			}
+		}
+	}
+	return result
+}
+
+func (c *openAPI3Comparison) compareParameters(keys []string, before, after map[string]*openapi3.Parameter) {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		b, a := before[name], after[name]
+		var parameter *openapi3.Parameter
+		if a != nil {
+			parameter = a
+		} else {
+			parameter = b
+		}
+		parameterKeys := compiler.KeysWith(keys, "parameters", parameter.Name)
+		label := parameter.In + " parameter " + parameter.Name
+		switch {
+		case a == nil:
+			c.breaking(parameterKeys, RemovedParameter, "The "+label+" was removed.")
+		case b == nil && a.Required:
+			c.breaking(parameterKeys, NewRequiredParameter, "A required "+label+" was added.")
+		case b == nil:
+			c.additive(parameterKeys, AddedParameter, "An optional "+label+" was added.")
+		default:
+			if a.Required && !b.Required {
+				c.breaking(parameterKeys, NewRequiredParameter, "The "+label+" became required.")
+			} else if !a.Required && b.Required {
+				c.additive(parameterKeys, OptionalParameter, "The "+label+" became optional.")
+			}
+			c.compareSchemas(compiler.KeysWith(parameterKeys, "schema"), b.Schema, a.Schema, input)
+		}
+	}
+}
+
+func (c *openAPI3Comparison) compareRequestBodies(keys []string, before, after *openapi3.RequestBody) {
+	keys = compiler.KeysWith(keys, "requestBody")
+	switch {
+	case before == nil && after == nil:
+		return
+	case after == nil:
+		c.breaking(keys, RemovedRequestBody, "The request body was removed.")
+	case before == nil && after.Required:
+		c.breaking(keys, RequiredRequestBody, "A required request body was added.")
+	case before == nil:
+		c.additive(keys, AddedRequestBody, "An optional request body was added.")
+	default:
+		if after.Required && !before.Required {
+			c.breaking(keys, RequiredRequestBody, "The request body became required.")
+		}
+		c.compareContent(compiler.KeysWith(keys, "content"), before.Content, after.Content, input)
+	}
+}
+
+func (c *openAPI3Comparison) compareResponses(keys []string, before, after map[string]*openapi3.Response) {
+	codes := make(map[string]bool)
+	for code := range before {
+		codes[code] = true
+	}
+	for code := range after {
+		codes[code] = true
+	}
+	for _, code := range sortedKeys(codes) {
+		responseKeys := compiler.KeysWith(keys, "responses", code)
+		b, a := before[code], after[code]
+		switch {
+		case a == nil:
+			c.breaking(responseKeys, RemovedResponse, "The "+code+" response was removed.")
+		case b == nil:
+			c.additive(responseKeys, AddedResponse, "A "+code+" response was added.")
+		default:
+			c.compareContent(compiler.KeysWith(responseKeys, "content"), b.Content, a.Content, output)
+		}
+	}
+}
+
+// compareContent compares the media types of request or response bodies.
+// Clients may depend on any media type they can send or receive, so
+// removing one is breaking in either direction.
+func (c *openAPI3Comparison) compareContent(keys []string, before, after *openapi3.MediaTypes, dir direction) {
+	beforeTypes := make(map[string]*openapi3.MediaType)
+	for _, pair := range before.GetAdditionalProperties() {
+		beforeTypes[pair.Name] = pair.Value
+	}
+	afterTypes := make(map[string]*openapi3.MediaType)
+	for _, pair := range after.GetAdditionalProperties() {
+		afterTypes[pair.Name] = pair.Value
+	}
+	names := make(map[string]bool)
+	for name := range beforeTypes {
+		names[name] = true
+	}
+	for name := range afterTypes {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		mediaTypeKeys := compiler.KeysWith(keys, name)
+		b, a := beforeTypes[name], afterTypes[name]
+		switch {
+		case a == nil:
+			c.breaking(mediaTypeKeys, RemovedMediaType, "The "+name+" media type was removed.")
+		case b == nil:
+			c.additive(mediaTypeKeys, AddedMediaType, "The "+name+" media type was added.")
+		default:
+			c.compareSchemas(compiler.KeysWith(mediaTypeKeys, "schema"), b.Schema, a.Schema, dir)
+		}
+	}
+}
+
+// compareSchemas compares schemas that appear in the same position of both documents.
+func (c *openAPI3Comparison) compareSchemas(keys []string, before, after *openapi3.SchemaOrReference, dir direction) {
+	if before == nil || after == nil {
+		return
+	}
+	beforeRef := before.GetReference().GetXRef()
+	afterRef := after.GetReference().GetXRef()
+	if beforeRef != "" && afterRef != "" {
+		beforeName := strings.TrimPrefix(beforeRef, schemaRefPrefix)
+		afterName := strings.TrimPrefix(afterRef, schemaRefPrefix)
+		if beforeName != afterName && c.renamed[beforeName] != afterName {
+			c.breaking(keys, ChangedReference, "The schema changed from "+beforeName+" to "+afterName+".")
+		}
+		// Referenced schemas are compared with the other components.
+		return
+	}
+	b := resolveSchema(c.before, before)
+	a := resolveSchema(c.after, after)
+	if b == nil || a == nil {
+		return
+	}
+	c.compareSchema(keys, b, a, dir)
+}
+
+func (c *openAPI3Comparison) compareSchema(keys []string, before, after *openapi3.Schema, dir direction) {
+	if before.Type != "" && after.Type != "" && before.Type != after.Type {
+		c.breaking(keys, ChangedType, "The type changed from "+before.Type+" to "+after.Type+".")
+		return
+	}
+	if before.Format != "" && after.Format != "" && before.Format != after.Format {
+		c.breaking(keys, ChangedType, "The format changed from "+before.Format+" to "+after.Format+".")
+	}
+	c.compareEnums(compiler.KeysWith(keys, "enum"), enumValues(before.Enum), enumValues(after.Enum), dir)
+	c.compareProperties(keys, before, after, dir)
+	if len(before.GetItems().GetSchemaOrReference()) > 0 && len(after.GetItems().GetSchemaOrReference()) > 0 {
+		c.compareSchemas(compiler.KeysWith(keys, "items"),
+			before.Items.SchemaOrReference[0], after.Items.SchemaOrReference[0], dir)
+	}
+	c.compareSchemas(compiler.KeysWith(keys, "additionalProperties"),
+		before.GetAdditionalProperties().GetSchemaOrReference(),
+		after.GetAdditionalProperties().GetSchemaOrReference(), dir)
+	c.compareSchemaLists(compiler.KeysWith(keys, "allOf"), before.AllOf, after.AllOf, dir)
+	c.compareSchemaLists(compiler.KeysWith(keys, "oneOf"), before.OneOf, after.OneOf, dir)
+	c.compareSchemaLists(compiler.KeysWith(keys, "anyOf"), before.AnyOf, after.AnyOf, dir)
+}
+
+func (c *openAPI3Comparison) compareSchemaLists(keys []string, before, after []*openapi3.SchemaOrReference, dir direction) {
+	if len(before) != len(after) {
+		c.breaking(keys, ChangedType, "The number of schemas changed from "+
+			strconv.Itoa(len(before))+" to "+strconv.Itoa(len(after))+".")
+		return
+	}
+	for i := range before {
+		c.compareSchemas(compiler.KeysWith(keys, strconv.Itoa(i)), before[i], after[i], dir)
+	}
+}
+
+// compareProperties compares the properties of two object schemas.
+// Removing a property breaks clients that read it, and adding a
+// required property breaks clients that send the object.
+func (c *openAPI3Comparison) compareProperties(keys []string, before, after *openapi3.Schema, dir direction) {
+	beforeProperties := make(map[string]*openapi3.SchemaOrReference)
+	for _, pair := range before.GetProperties().GetAdditionalProperties() {
+		beforeProperties[pair.Name] = pair.Value
+	}
+	afterProperties := make(map[string]*openapi3.SchemaOrReference)
+	for _, pair := range after.GetProperties().GetAdditionalProperties() {
+		afterProperties[pair.Name] = pair.Value
+	}
+	names := make(map[string]bool)
+	for name := range beforeProperties {
+		names[name] = true
+	}
+	for name := range afterProperties {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		propertyKeys := compiler.KeysWith(keys, "properties", name)
+		b, a := beforeProperties[name], afterProperties[name]
+		required := compiler.StringArrayContainsValue(after.Required, name)
+		wasRequired := compiler.StringArrayContainsValue(before.Required, name)
+		switch {
+		case a == nil:
+			if dir&output != 0 {
+				c.breaking(propertyKeys, RemovedProperty, "The "+name+" property was removed.")
+			} else {
+				c.additive(propertyKeys, RemovedProperty, "The "+name+" property was removed.")
+			}
+		case b == nil && required && dir&input != 0:
+			c.breaking(propertyKeys, NewRequiredProperty, "A required "+name+" property was added.")
+		case b == nil:
+			c.additive(propertyKeys, AddedProperty, "The "+name+" property was added.")
+		default:
+			if required && !wasRequired && dir&input != 0 {
+				c.breaking(propertyKeys, NewRequiredProperty, "The "+name+" property became required.")
+			}
+			c.compareSchemas(propertyKeys, b, a, dir)
+		}
+	}
+}
+
+func (c *openAPI3Comparison) compareSchemaComponents() {
+	before := namedSchemas(c.before)
+	after := namedSchemas(c.after)
+	var removed, added []string
+	for name := range before {
+		if after[name] == nil {
+			removed = append(removed, name)
+		}
+	}
+	for name := range after {
+		if before[name] == nil {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+	// A removed schema that is identical to an added one was renamed.
+	for _, name := range removed {
+		for _, newName := range added {
+			if _, taken := c.renamedTo(newName); !taken && proto.Equal(before[name], after[newName]) {
+				c.renamed[name] = newName
+				break
+			}
+		}
+	}
+	for _, name := range removed {
+		keys := []string{"components", "schemas", name}
+		if newName, ok := c.renamed[name]; ok {
+			c.breaking(keys, RenamedSchema, "Schema "+name+" was renamed to "+newName+".")
+		} else {
+			c.breaking(keys, RemovedSchema, "Schema "+name+" was removed.")
+		}
+	}
+	for _, name := range added {
+		if _, ok := c.renamedTo(name); !ok {
+			c.additive([]string{"components", "schemas", name}, AddedSchema, "Schema "+name+" was added.")
+		}
+	}
+	names := make(map[string]bool)
+	for name := range before {
+		if after[name] != nil {
+			names[name] = true
+		}
+	}
+	for _, name := range sortedKeys(names) {
+		dir := c.usage[name]
+		if dir == 0 {
+			// Unused schemas are treated conservatively.
+			dir = input | output
+		}
+		c.compareSchemas([]string{"components", "schemas", name}, before[name], after[name], dir)
+	}
+}
+
+// renamedTo returns the old name of a schema that was renamed to name.
+func (c *openAPI3Comparison) renamedTo(name string) (string, bool) {
+	for oldName, newName := range c.renamed {
+		if newName == name {
+			return oldName, true
+		}
+	}
+	return "", false
+}
+
+func (c *openAPI3Comparison) compareSecuritySchemes() {
+	before := make(map[string]bool)
+	for _, pair := range c.before.GetComponents().GetSecuritySchemes().GetAdditionalProperties() {
+		before[pair.Name] = true
+	}
+	after := make(map[string]bool)
+	for _, pair := range c.after.GetComponents().GetSecuritySchemes().GetAdditionalProperties() {
+		after[pair.Name] = true
+	}
+	for _, name := range sortedKeys(before) {
+		if !after[name] {
+			c.breaking([]string{"components", "securitySchemes", name}, RemovedSecurityScheme,
+				"Security scheme "+name+" was removed.")
+		}
+	}
+	for _, name := range sortedKeys(after) {
+		if !before[name] {
+			c.additive([]string{"components", "securitySchemes", name}, AddedSecurityScheme,
+				"Security scheme "+name+" was added.")
+		}
+	}
+}
+
+// recordUsage records the directions in which each component schema is used
+// by the operations of a document, following references between schemas.
+func (c *openAPI3Comparison) recordUsage(d *openapi3.Document) {
+	for _, pair := range d.GetPaths().GetPath() {
+		for _, operation := range operations(pair.Value) {
+			for _, parameter := range c.parameters(d, pair.Value, operation) {
+				c.recordSchemaUsage(d, parameter.Schema, input)
+			}
+			if requestBody := c.requestBody(d, operation.RequestBody); requestBody != nil {
+				for _, mediaType := range requestBody.GetContent().GetAdditionalProperties() {
+					c.recordSchemaUsage(d, mediaType.Value.GetSchema(), input)
+				}
+			}
+			for _, response := range c.responses(d, operation.Responses) {
+				for _, mediaType := range response.GetContent().GetAdditionalProperties() {
+					c.recordSchemaUsage(d, mediaType.Value.GetSchema(), output)
+				}
+			}
+		}
+	}
+}
+
+func (c *openAPI3Comparison) recordSchemaUsage(d *openapi3.Document, s *openapi3.SchemaOrReference, dir direction) {
+	if s == nil {
+		return
+	}
+	if ref := s.GetReference().GetXRef(); ref != "" {
+		name := strings.TrimPrefix(ref, schemaRefPrefix)
+		if c.usage[name]&dir == dir {
+			return
+		}
+		c.usage[name] |= dir
+		c.recordSchemaUsage(d, namedSchemas(d)[name], dir)
+		return
+	}
+	schema := s.GetSchema()
+	if schema == nil {
+		return
+	}
+	for _, pair := range schema.GetProperties().GetAdditionalProperties() {
+		c.recordSchemaUsage(d, pair.Value, dir)
+	}
+	for _, item := range schema.GetItems().GetSchemaOrReference() {
+		c.recordSchemaUsage(d, item, dir)
+	}
+	c.recordSchemaUsage(d, schema.GetAdditionalProperties().GetSchemaOrReference(), dir)
+	for _, list := range [][]*openapi3.SchemaOrReference{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, item := range list {
+			c.recordSchemaUsage(d, item, dir)
+		}
+	}
+}
+
+func (c *openAPI3Comparison) requestBody(d *openapi3.Document, r *openapi3.RequestBodyOrReference) *openapi3.RequestBody {
+	if requestBody := r.GetRequestBody(); requestBody != nil {
+		return requestBody
+	}
+	ref := r.GetReference().GetXRef()
+	if ref == "" {
+		return nil
+	}
+	name := strings.TrimPrefix(ref, "#/components/requestBodies/")
+	for _, pair := range d.GetComponents().GetRequestBodies().GetAdditionalProperties() {
+		if pair.Name == name {
+			return pair.Value.GetRequestBody()
+		}
+	}
+	return nil
+}
+
+// responses returns the responses of an operation keyed by status code.
+func (c *openAPI3Comparison) responses(d *openapi3.Document, r *openapi3.Responses) map[string]*openapi3.Response {
+	result := make(map[string]*openapi3.Response)
+	if r == nil {
+		return result
+	}
+	if response := resolveResponse(d, r.Default); response != nil {
+		result["default"] = response
+	}
+	for _, pair := range r.ResponseOrReference {
+		if response := resolveResponse(d, pair.Value); response != nil {
+			result[pair.Name] = response
+		}
+	}
+	return result
+}
+
+func resolveResponse(d *openapi3.Document, r *openapi3.ResponseOrReference) *openapi3.Response {
+	if response := r.GetResponse(); response != nil {
+		return response
+	}
+	ref := r.GetReference().GetXRef()
+	if ref == "" {
+		return nil
+	}
+	name := strings.TrimPrefix(ref, "#/components/responses/")
+	for _, pair := range d.GetComponents().GetResponses().GetAdditionalProperties() {
+		if pair.Name == name {
+			return pair.Value.GetResponse()
+		}
+	}
+	return nil
+}
+
+func resolveParameter(d *openapi3.Document, p *openapi3.ParameterOrReference) *openapi3.Parameter {
+	if parameter := p.GetParameter(); parameter != nil {
+		return parameter
+	}
+	ref := p.GetReference().GetXRef()
+	if ref == "" {
+		return nil
+	}
+	name := strings.TrimPrefix(ref, "#/components/parameters/")
+	for _, pair := range d.GetComponents().GetParameters().GetAdditionalProperties() {
+		if pair.Name == name {
+			return pair.Value.GetParameter()
+		}
+	}
+	return nil
+}
+
+func resolveSchema(d *openapi3.Document, s *openapi3.SchemaOrReference) *openapi3.Schema {
+	// Follow chains of references, stopping if one refers back to itself.
+	for i := 0; s != nil && i < 32; i++ {
+		if schema := s.GetSchema(); schema != nil {
+			return schema
+		}
+		s = namedSchemas(d)[strings.TrimPrefix(s.GetReference().GetXRef(), schemaRefPrefix)]
+	}
+	return nil
+}
+
+func namedSchemas(d *openapi3.Document) map[string]*openapi3.SchemaOrReference {
+	result := make(map[string]*openapi3.SchemaOrReference)
+	for _, pair := range d.GetComponents().GetSchemas().GetAdditionalProperties() {
+		result[pair.Name] = pair.Value
+	}
+	return result
+}
+
+// enumValues returns the YAML representations of a list of enumeration values.
+func enumValues(values []*openapi3.Any) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, strings.TrimSpace(value.GetYaml()))
+	}
+	return result
+}
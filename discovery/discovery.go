@@ -1700,21 +1700,39 @@ func NewStringArray(in *yaml.Node, context *compiler.Context) (*StringArray, err
 
 // ResolveReferences resolves references found inside Annotations objects.
 func (m *Annotations) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Annotations objects
+// using the session and base URI of the specified context.
+func (m *Annotations) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside Any objects.
 func (m *Any) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Any objects
+// using the session and base URI of the specified context.
+func (m *Any) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside Auth objects.
 func (m *Auth) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Auth objects
+// using the session and base URI of the specified context.
+func (m *Auth) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Oauth2 != nil {
-		_, err := m.Oauth2.ResolveReferences(root)
+		_, err := m.Oauth2.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1724,39 +1742,45 @@ func (m *Auth) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Document objects.
 func (m *Document) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Document objects
+// using the session and base URI of the specified context.
+func (m *Document) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Icons != nil {
-		_, err := m.Icons.ResolveReferences(root)
+		_, err := m.Icons.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Parameters != nil {
-		_, err := m.Parameters.ResolveReferences(root)
+		_, err := m.Parameters.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Auth != nil {
-		_, err := m.Auth.ResolveReferences(root)
+		_, err := m.Auth.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Schemas != nil {
-		_, err := m.Schemas.ResolveReferences(root)
+		_, err := m.Schemas.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Methods != nil {
-		_, err := m.Methods.ResolveReferences(root)
+		_, err := m.Methods.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Resources != nil {
-		_, err := m.Resources.ResolveReferences(root)
+		_, err := m.Resources.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1766,15 +1790,27 @@ func (m *Document) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Icons objects.
 func (m *Icons) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Icons objects
+// using the session and base URI of the specified context.
+func (m *Icons) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside MediaUpload objects.
 func (m *MediaUpload) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside MediaUpload objects
+// using the session and base URI of the specified context.
+func (m *MediaUpload) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Protocols != nil {
-		_, err := m.Protocols.ResolveReferences(root)
+		_, err := m.Protocols.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1784,27 +1820,33 @@ func (m *MediaUpload) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Method objects.
 func (m *Method) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Method objects
+// using the session and base URI of the specified context.
+func (m *Method) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Parameters != nil {
-		_, err := m.Parameters.ResolveReferences(root)
+		_, err := m.Parameters.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Request != nil {
-		_, err := m.Request.ResolveReferences(root)
+		_, err := m.Request.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Response != nil {
-		_, err := m.Response.ResolveReferences(root)
+		_, err := m.Response.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.MediaUpload != nil {
-		_, err := m.MediaUpload.ResolveReferences(root)
+		_, err := m.MediaUpload.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1814,10 +1856,16 @@ func (m *Method) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Methods objects.
 func (m *Methods) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Methods objects
+// using the session and base URI of the specified context.
+func (m *Methods) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -1828,9 +1876,15 @@ func (m *Methods) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedMethod objects.
 func (m *NamedMethod) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedMethod objects
+// using the session and base URI of the specified context.
+func (m *NamedMethod) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1840,9 +1894,15 @@ func (m *NamedMethod) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedParameter objects.
 func (m *NamedParameter) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedParameter objects
+// using the session and base URI of the specified context.
+func (m *NamedParameter) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1852,9 +1912,15 @@ func (m *NamedParameter) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedResource objects.
 func (m *NamedResource) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedResource objects
+// using the session and base URI of the specified context.
+func (m *NamedResource) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1864,9 +1930,15 @@ func (m *NamedResource) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedSchema objects.
 func (m *NamedSchema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedSchema objects
+// using the session and base URI of the specified context.
+func (m *NamedSchema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1876,9 +1948,15 @@ func (m *NamedSchema) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedScope objects.
 func (m *NamedScope) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedScope objects
+// using the session and base URI of the specified context.
+func (m *NamedScope) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1888,9 +1966,15 @@ func (m *NamedScope) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Oauth2 objects.
 func (m *Oauth2) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Oauth2 objects
+// using the session and base URI of the specified context.
+func (m *Oauth2) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Scopes != nil {
-		_, err := m.Scopes.ResolveReferences(root)
+		_, err := m.Scopes.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1900,41 +1984,47 @@ func (m *Oauth2) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Parameter objects.
 func (m *Parameter) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Parameter objects
+// using the session and base URI of the specified context.
+func (m *Parameter) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewParameter(info, nil)
+			replacement, err := NewParameter(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
 	}
 	if m.Properties != nil {
-		_, err := m.Properties.ResolveReferences(root)
+		_, err := m.Properties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.AdditionalProperties != nil {
-		_, err := m.AdditionalProperties.ResolveReferences(root)
+		_, err := m.AdditionalProperties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Annotations != nil {
-		_, err := m.Annotations.ResolveReferences(root)
+		_, err := m.Annotations.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1944,10 +2034,16 @@ func (m *Parameter) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Parameters objects.
 func (m *Parameters) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Parameters objects
+// using the session and base URI of the specified context.
+func (m *Parameters) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -1958,15 +2054,21 @@ func (m *Parameters) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Protocols objects.
 func (m *Protocols) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Protocols objects
+// using the session and base URI of the specified context.
+func (m *Protocols) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Simple != nil {
-		_, err := m.Simple.ResolveReferences(root)
+		_, err := m.Simple.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Resumable != nil {
-		_, err := m.Resumable.ResolveReferences(root)
+		_, err := m.Resumable.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -1976,17 +2078,23 @@ func (m *Protocols) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Request objects.
 func (m *Request) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Request objects
+// using the session and base URI of the specified context.
+func (m *Request) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewRequest(info, nil)
+			replacement, err := NewRequest(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
@@ -1996,15 +2104,21 @@ func (m *Request) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Resource objects.
 func (m *Resource) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Resource objects
+// using the session and base URI of the specified context.
+func (m *Resource) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Methods != nil {
-		_, err := m.Methods.ResolveReferences(root)
+		_, err := m.Methods.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Resources != nil {
-		_, err := m.Resources.ResolveReferences(root)
+		_, err := m.Resources.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -2014,10 +2128,16 @@ func (m *Resource) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Resources objects.
 func (m *Resources) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Resources objects
+// using the session and base URI of the specified context.
+func (m *Resources) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -2028,9 +2148,15 @@ func (m *Resources) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Response objects.
 func (m *Response) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Response objects
+// using the session and base URI of the specified context.
+func (m *Response) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, _, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
@@ -2041,47 +2167,59 @@ func (m *Response) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Resumable objects.
 func (m *Resumable) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Resumable objects
+// using the session and base URI of the specified context.
+func (m *Resumable) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside Schema objects.
 func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Schema objects
+// using the session and base URI of the specified context.
+func (m *Schema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Properties != nil {
-		_, err := m.Properties.ResolveReferences(root)
+		_, err := m.Properties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.AdditionalProperties != nil {
-		_, err := m.AdditionalProperties.ResolveReferences(root)
+		_, err := m.AdditionalProperties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewSchema(info, nil)
+			replacement, err := NewSchema(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
 	}
 	if m.Annotations != nil {
-		_, err := m.Annotations.ResolveReferences(root)
+		_, err := m.Annotations.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -2091,10 +2229,16 @@ func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Schemas objects.
 func (m *Schemas) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Schemas objects
+// using the session and base URI of the specified context.
+func (m *Schemas) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -2105,16 +2249,28 @@ func (m *Schemas) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Scope objects.
 func (m *Scope) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Scope objects
+// using the session and base URI of the specified context.
+func (m *Scope) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside Scopes objects.
 func (m *Scopes) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Scopes objects
+// using the session and base URI of the specified context.
+func (m *Scopes) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -2125,12 +2281,24 @@ func (m *Scopes) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Simple objects.
 func (m *Simple) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Simple objects
+// using the session and base URI of the specified context.
+func (m *Simple) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside StringArray objects.
 func (m *StringArray) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside StringArray objects
+// using the session and base URI of the specified context.
+func (m *StringArray) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
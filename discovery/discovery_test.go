@@ -17,6 +17,8 @@ package discovery_v1
 import (
 	"io/ioutil"
 	"testing"
+
+	"github.com/google/gnostic/compiler"
 )
 
 func TestParseDocument(t *testing.T) {
@@ -46,3 +48,18 @@ func TestParseDocument(t *testing.T) {
 		t.Errorf("unexpected value for Title: %s (expected %s)", d.Title, title)
 	}
 }
+
+func TestValidateDocument(t *testing.T) {
+	filename := "../examples/discovery/discovery-v1.json"
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unable to read file %s", filename)
+	}
+	info, err := compiler.ReadInfoFromBytes(filename, b)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := ValidateDocument(info, compiler.NewContext("$root", info, nil)); err != nil {
+		t.Errorf("unexpected errors: %+v", err)
+	}
+}
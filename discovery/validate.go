@@ -0,0 +1,141 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery_v1
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// ValidateDocument checks a Discovery document for errors that NewDocument
+// doesn't detect because they don't violate the structure of the Discovery format:
+// parameterOrder entries that don't name parameters of their methods, path
+// parameters that don't appear in their method's path or flatPath, path template
+// variables without matching path parameters, and references to undefined schemas.
+// The document should be the node that was compiled by NewDocument, so that
+// errors are reported with their locations in the source.
+func ValidateDocument(in *yaml.Node, context *compiler.Context) error {
+	if in != nil && in.Kind == yaml.DocumentNode && len(in.Content) > 0 {
+		in = in.Content[0]
+	}
+	v := &validator{schemas: make(map[string]bool)}
+	compiler.ForEachEntry(compiler.MapValueForKey(in, "schemas"), func(name string, _ *yaml.Node) {
+		v.schemas[name] = true
+	})
+	v.validateMethods(compiler.MapValueForKey(in, "methods"), context)
+	v.validateResources(compiler.MapValueForKey(in, "resources"), context)
+	v.validateReferences(in, context)
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		a, b := v.errors[i].(*compiler.Error).Context.Node, v.errors[j].(*compiler.Error).Context.Node
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return compiler.NewErrorGroupOrNil(v.errors)
+}
+
+// validator collects the errors found by ValidateDocument.
+type validator struct {
+	// schemas holds the names of the document's schemas.
+	schemas map[string]bool
+	errors  []error
+}
+
+// pathTemplateVariable matches the variables of path templates, which
+// can use reserved expansion ("{+name}") and can be exploded ("{name*}").
+var pathTemplateVariable = regexp.MustCompile(`\{\+?([^}*]*)\*?\}`)
+
+func (v *validator) addError(context *compiler.Context, message string) {
+	v.errors = append(v.errors, compiler.NewError(context, message))
+}
+
+// validateResources checks the methods of a collection of resources and their nested resources.
+func (v *validator) validateResources(resources *yaml.Node, context *compiler.Context) {
+	resourcesContext := compiler.NewContext("resources", resources, context)
+	compiler.ForEachEntry(resources, func(name string, resource *yaml.Node) {
+		resourceContext := compiler.NewContext(name, resource, resourcesContext)
+		v.validateMethods(compiler.MapValueForKey(resource, "methods"), resourceContext)
+		v.validateResources(compiler.MapValueForKey(resource, "resources"), resourceContext)
+	})
+}
+
+// validateMethods checks the parameterOrder and path parameters of a collection of methods.
+func (v *validator) validateMethods(methods *yaml.Node, context *compiler.Context) {
+	methodsContext := compiler.NewContext("methods", methods, context)
+	compiler.ForEachEntry(methods, func(name string, method *yaml.Node) {
+		methodContext := compiler.NewContext(name, method, methodsContext)
+		parameters := compiler.MapValueForKey(method, "parameters")
+		if order := compiler.MapValueForKey(method, "parameterOrder"); order != nil && order.Kind == yaml.SequenceNode {
+			orderContext := compiler.NewContext("parameterOrder", order, methodContext)
+			for i, item := range order.Content {
+				if !compiler.MapHasKey(parameters, item.Value) {
+					v.addError(compiler.NewContext(strconv.Itoa(i), item, orderContext),
+						"has parameterOrder entry that isn't a parameter: "+item.Value)
+				}
+			}
+		}
+		variables := make(map[string]bool)
+		for _, key := range []string{"path", "flatPath"} {
+			path, _ := compiler.StringForScalarNode(compiler.MapValueForKey(method, key))
+			for _, m := range pathTemplateVariable.FindAllStringSubmatch(path, -1) {
+				variables[m[1]] = true
+			}
+		}
+		parametersContext := compiler.NewContext("parameters", parameters, methodContext)
+		pathParameters := make(map[string]bool)
+		compiler.ForEachEntry(parameters, func(name string, parameter *yaml.Node) {
+			if location, _ := compiler.StringForScalarNode(compiler.MapValueForKey(parameter, "location")); location != "path" {
+				return
+			}
+			pathParameters[name] = true
+			if !variables[name] {
+				v.addError(compiler.NewContext(name, parameter, parametersContext),
+					"has path parameter that isn't in the path or flatPath: "+name)
+			}
+		})
+		// flatPath variables are often named differently from the parameters
+		// that they are derived from, so only the path is checked for them.
+		path, _ := compiler.StringForScalarNode(compiler.MapValueForKey(method, "path"))
+		for _, m := range pathTemplateVariable.FindAllStringSubmatch(path, -1) {
+			if !pathParameters[m[1]] {
+				v.addError(methodContext, "is missing path parameter: "+m[1])
+			}
+		}
+	})
+}
+
+// validateReferences checks that the references in a node name defined schemas.
+func (v *validator) validateReferences(node *yaml.Node, context *compiler.Context) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			v.validateReferences(item, compiler.NewContext(strconv.Itoa(i), item, context))
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if key == "$ref" && value.Kind == yaml.ScalarNode {
+				if !v.schemas[value.Value] {
+					v.addError(compiler.NewContext(key, value, context), "has unresolved reference: "+value.Value)
+				}
+				continue
+			}
+			v.validateReferences(value, compiler.NewContext(key, value, context))
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package examplecheck checks that the examples in OpenAPI v3 descriptions
+// are valid instances of their schemas.
+package examplecheck
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// InvalidExample is the code of messages that report examples that don't match their schemas.
+const InvalidExample = "INVALIDEXAMPLE"
+
+// checker holds the state of a check of a document.
+type checker struct {
+	validator
+	messages []*plugins.Message
+}
+
+// CheckOpenAPIv3 checks the examples of the schemas, parameters, headers,
+// and media types of an OpenAPI v3 document against their schemas and
+// returns an ERROR message for each example that doesn't match.
+// Examples that are specified with externalValue are not checked.
+func CheckOpenAPIv3(document *openapi3.Document) []*plugins.Message {
+	return Check(document.ToRawInfo())
+}
+
+// Check checks the examples in an OpenAPI v3 description.
+func Check(info *yaml.Node) []*plugins.Message {
+	root := info
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	c := &checker{
+		validator: validator{root: root, patterns: make(map[string]*regexp.Regexp)},
+		messages:  make([]*plugins.Message, 0),
+	}
+	if root == nil || root.Kind != yaml.MappingNode {
+		return c.messages
+	}
+	compiler.ForEachEntry(compiler.MapValueForKey(root, "paths"), func(path string, item *yaml.Node) {
+		c.checkPathItem(item, []string{"paths", path})
+	})
+	components := compiler.MapValueForKey(root, "components")
+	compiler.ForEachEntry(compiler.MapValueForKey(components, "schemas"), func(name string, schema *yaml.Node) {
+		c.checkSchema(schema, []string{"components", "schemas", name}, 0)
+	})
+	compiler.ForEachEntry(compiler.MapValueForKey(components, "responses"), func(name string, response *yaml.Node) {
+		c.checkResponse(response, []string{"components", "responses", name})
+	})
+	compiler.ForEachEntry(compiler.MapValueForKey(components, "parameters"), func(name string, parameter *yaml.Node) {
+		c.checkParameter(parameter, []string{"components", "parameters", name})
+	})
+	compiler.ForEachEntry(compiler.MapValueForKey(components, "requestBodies"), func(name string, body *yaml.Node) {
+		c.checkContent(body, []string{"components", "requestBodies", name})
+	})
+	compiler.ForEachEntry(compiler.MapValueForKey(components, "headers"), func(name string, header *yaml.Node) {
+		c.checkParameter(header, []string{"components", "headers", name})
+	})
+	return c.messages
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// checkPathItem checks the parameters, request bodies, and responses of a path item's operations.
+func (c *checker) checkPathItem(item *yaml.Node, keys []string) {
+	if isReference(item) {
+		return
+	}
+	c.checkParameters(compiler.MapValueForKey(item, "parameters"), compiler.KeysWith(keys, "parameters"))
+	for _, method := range methods {
+		operation := compiler.MapValueForKey(item, method)
+		if operation == nil {
+			continue
+		}
+		operationKeys := compiler.KeysWith(keys, method)
+		c.checkParameters(compiler.MapValueForKey(operation, "parameters"), compiler.KeysWith(operationKeys, "parameters"))
+		if body := compiler.MapValueForKey(operation, "requestBody"); body != nil && !isReference(body) {
+			c.checkContent(body, compiler.KeysWith(operationKeys, "requestBody"))
+		}
+		compiler.ForEachEntry(compiler.MapValueForKey(operation, "responses"), func(code string, response *yaml.Node) {
+			c.checkResponse(response, compiler.KeysWith(operationKeys, "responses", code))
+		})
+		compiler.ForEachEntry(compiler.MapValueForKey(operation, "callbacks"), func(name string, callback *yaml.Node) {
+			if isReference(callback) {
+				return
+			}
+			compiler.ForEachEntry(callback, func(expression string, item *yaml.Node) {
+				c.checkPathItem(item, compiler.KeysWith(operationKeys, "callbacks", name, expression))
+			})
+		})
+	}
+}
+
+// checkParameters checks the parameters in a list.
+func (c *checker) checkParameters(list *yaml.Node, keys []string) {
+	for i, parameter := range sequence(list) {
+		c.checkParameter(parameter, compiler.KeysWith(keys, strconv.Itoa(i)))
+	}
+}
+
+// checkParameter checks the examples of a parameter or header.
+func (c *checker) checkParameter(parameter *yaml.Node, keys []string) {
+	if isReference(parameter) {
+		return
+	}
+	schema := compiler.MapValueForKey(parameter, "schema")
+	if schema != nil {
+		c.checkSchema(schema, compiler.KeysWith(keys, "schema"), 0)
+		c.checkExamples(parameter, schema, keys)
+	}
+	c.checkContent(parameter, keys)
+}
+
+// checkResponse checks the headers and content of a response.
+func (c *checker) checkResponse(response *yaml.Node, keys []string) {
+	if isReference(response) {
+		return
+	}
+	compiler.ForEachEntry(compiler.MapValueForKey(response, "headers"), func(name string, header *yaml.Node) {
+		c.checkParameter(header, compiler.KeysWith(keys, "headers", name))
+	})
+	c.checkContent(response, keys)
+}
+
+// checkContent checks the media types in the content of a request body, response, or parameter.
+func (c *checker) checkContent(node *yaml.Node, keys []string) {
+	compiler.ForEachEntry(compiler.MapValueForKey(node, "content"), func(mediaType string, value *yaml.Node) {
+		mediaTypeKeys := compiler.KeysWith(keys, "content", mediaType)
+		if schema := compiler.MapValueForKey(value, "schema"); schema != nil {
+			c.checkSchema(schema, compiler.KeysWith(mediaTypeKeys, "schema"), 0)
+			c.checkExamples(value, schema, mediaTypeKeys)
+		}
+	})
+}
+
+// checkExamples checks the example and examples of a parameter, header, or media type.
+func (c *checker) checkExamples(node, schema *yaml.Node, keys []string) {
+	if example := compiler.MapValueForKey(node, "example"); example != nil {
+		c.checkValue(example, schema, compiler.KeysWith(keys, "example"))
+	}
+	compiler.ForEachEntry(compiler.MapValueForKey(node, "examples"), func(name string, example *yaml.Node) {
+		if isReference(example) {
+			if example = c.resolve(example); example == nil {
+				return
+			}
+		}
+		if value := compiler.MapValueForKey(example, "value"); value != nil {
+			c.checkValue(value, schema, compiler.KeysWith(keys, "examples", name, "value"))
+		}
+	})
+}
+
+// checkSchema checks the example of a schema and of the schemas that it contains.
+// Referenced schemas are checked where they are defined.
+func (c *checker) checkSchema(schema *yaml.Node, keys []string, depth int) {
+	if schema == nil || schema.Kind != yaml.MappingNode || isReference(schema) || depth > maxDepth {
+		return
+	}
+	if example := compiler.MapValueForKey(schema, "example"); example != nil {
+		c.checkValue(example, schema, compiler.KeysWith(keys, "example"))
+	}
+	// Property names that begin with "x-" are not specification extensions.
+	if properties := compiler.MapValueForKey(schema, "properties"); properties != nil && properties.Kind == yaml.MappingNode {
+		for i := 0; i < len(properties.Content)-1; i += 2 {
+			name := properties.Content[i].Value
+			c.checkSchema(properties.Content[i+1], compiler.KeysWith(keys, "properties", name), depth+1)
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		c.checkSchema(compiler.MapValueForKey(schema, key), compiler.KeysWith(keys, key), depth+1)
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		for i, s := range sequence(compiler.MapValueForKey(schema, key)) {
+			c.checkSchema(s, compiler.KeysWith(keys, key, strconv.Itoa(i)), depth+1)
+		}
+	}
+}
+
+// checkValue reports the mismatches between an example and its schema.
+func (c *checker) checkValue(value, schema *yaml.Node, keys []string) {
+	mismatches := c.validate(value, schema, "", 0)
+	if len(mismatches) == 0 {
+		return
+	}
+	texts := make([]string, 0, len(mismatches))
+	for _, m := range mismatches {
+		texts = append(texts, m.String())
+	}
+	c.messages = append(c.messages, &plugins.Message{
+		Level: plugins.Message_ERROR,
+		Code:  InvalidExample,
+		Text:  "Example doesn't match its schema: " + strings.Join(texts, "; ") + ".",
+		Keys:  keys,
+	})
+}
+
+// isReference returns true if a node is a reference object.
+func isReference(node *yaml.Node) bool {
+	return compiler.MapHasKey(node, "$ref")
+}
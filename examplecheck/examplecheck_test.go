@@ -0,0 +1,101 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package examplecheck
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+func TestCheckOpenAPIv3(t *testing.T) {
+	b, err := ioutil.ReadFile("../testdata/examplecheck/openapi-v3.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi3.ParseDocument(b)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []string{
+		`paths./pets.get.parameters.0.example: 1000 is greater than maximum 100.`,
+		`paths./pets.get.parameters.1.examples.pending.value: "pending" is not one of the allowed values.`,
+		`paths./pets.get.responses.200.headers.X-Next.example: "/pets?page=2" is not a valid uri.`,
+		`paths./pets.get.responses.200.content.application/json.examples.pets.value: has 3 items but maxItems is 2; /1/id: expected integer but found string; /2: is missing required property "name".`,
+		`paths./pets.post.requestBody.content.application/json.example: has property "nickname" that is not allowed.`,
+		`components.schemas.Pet.properties.name.example: "" is shorter than minLength 1.`,
+		`components.schemas.Pet.properties.tag.example: "Dog" doesn't match pattern "^[a-z]+$".`,
+		`components.schemas.Pet.properties.birthday.example: "2020-02-30" is not a valid date.`,
+	}
+	messages := CheckOpenAPIv3(document)
+	if len(messages) != len(expected) {
+		t.Fatalf("expected %d messages, got %d: %+v", len(expected), len(messages), messages)
+	}
+	for i, message := range messages {
+		if message.Code != InvalidExample {
+			t.Errorf("unexpected code: %s", message.Code)
+		}
+		text := strings.Join(message.Keys, ".") + ": " + strings.TrimPrefix(message.Text, "Example doesn't match its schema: ")
+		if text != expected[i] {
+			t.Errorf("unexpected message:\n%s\nexpected:\n%s", text, expected[i])
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		schema   string
+		value    string
+		expected string
+	}{
+		{`{type: number, multipleOf: 0.5}`, `1.5`, ``},
+		{`{type: number, multipleOf: 0.5}`, `1.2`, `1.2 is not a multiple of 0.5`},
+		{`{type: integer}`, `2.0`, ``},
+		{`{type: integer, format: int32}`, `3000000000`, `3000000000 is not a valid int32`},
+		{`{type: number, minimum: 0, exclusiveMinimum: true}`, `0`, `0 is not greater than exclusive minimum 0`},
+		{`{type: string}`, `null`, `expected string but found null`},
+		{`{type: string, format: date-time}`, `"2022-01-02T03:04:05Z"`, ``},
+		{`{type: string, format: uuid}`, `"not-a-uuid"`, `"not-a-uuid" is not a valid uuid`},
+		{`{type: array, uniqueItems: true}`, `[{a: 1}, {a: 1.0}]`, `has duplicate items 0 and 1`},
+		{`{enum: [{a: 1}, [1, 2]]}`, `[1, 2]`, ``},
+		{`{oneOf: [{type: integer}, {type: number}]}`, `1`, `matches 2 oneOf schemas`},
+		{`{anyOf: [{type: integer}, {type: boolean}]}`, `"x"`, `doesn't match any anyOf schema`},
+		{`{allOf: [{required: [a]}, {required: [b]}]}`, `{a: 1}`, `is missing required property "b"`},
+		{`{additionalProperties: {type: string}}`, `{a: b, c: 1}`, `/c: expected string but found integer`},
+		{`{not: {type: string}}`, `"x"`, `matches schema that is not allowed`},
+	}
+	for _, test := range tests {
+		var schema, value yaml.Node
+		if err := yaml.Unmarshal([]byte(test.schema), &schema); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if err := yaml.Unmarshal([]byte(test.value), &value); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		v := &validator{root: &schema, patterns: make(map[string]*regexp.Regexp)}
+		texts := make([]string, 0)
+		for _, m := range v.validate(value.Content[0], schema.Content[0], "", 0) {
+			texts = append(texts, m.String())
+		}
+		if text := strings.Join(texts, "; "); text != test.expected {
+			t.Errorf("%s with %s: got %q, expected %q", test.value, test.schema, text, test.expected)
+		}
+	}
+}
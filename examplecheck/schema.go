@@ -0,0 +1,453 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package examplecheck
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// maxDepth limits the nesting of values and schemas that are checked,
+// which prevents recursive schemas from being expanded without end.
+const maxDepth = 64
+
+// A mismatch describes a value in an example that doesn't satisfy its schema.
+type mismatch struct {
+	// pointer is the JSON Pointer of the value in the example.
+	pointer string
+	text    string
+}
+
+func (m *mismatch) String() string {
+	if m.pointer == "" {
+		return m.text
+	}
+	return m.pointer + ": " + m.text
+}
+
+// validator checks values against schemas.
+type validator struct {
+	// root is the document that holds the schemas, which is used to resolve references.
+	root *yaml.Node
+	// patterns caches compiled regular expressions.
+	patterns map[string]*regexp.Regexp
+}
+
+// validate returns the mismatches between a value and a schema.
+func (v *validator) validate(value, schema *yaml.Node, pointer string, depth int) []*mismatch {
+	schema = v.resolve(schema)
+	if schema == nil || schema.Kind != yaml.MappingNode || depth > maxDepth {
+		return nil
+	}
+	value = resolveAlias(value)
+	if kind := kindOf(value); kind == "null" {
+		if nullable, _ := compiler.BoolForScalarNode(compiler.MapValueForKey(schema, "nullable")); nullable {
+			return nil
+		}
+		if t, ok := compiler.StringForScalarNode(compiler.MapValueForKey(schema, "type")); ok && t != "" {
+			return []*mismatch{{pointer: pointer, text: "expected " + t + " but found null"}}
+		}
+	}
+	mismatches := make([]*mismatch, 0)
+	add := func(format string, args ...interface{}) {
+		mismatches = append(mismatches, &mismatch{pointer: pointer, text: fmt.Sprintf(format, args...)})
+	}
+	for _, s := range sequence(compiler.MapValueForKey(schema, "allOf")) {
+		mismatches = append(mismatches, v.validate(value, s, pointer, depth+1)...)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		schemas := sequence(compiler.MapValueForKey(schema, key))
+		matches := 0
+		for _, s := range schemas {
+			if len(v.validate(value, s, pointer, depth+1)) == 0 {
+				matches++
+			}
+		}
+		if len(schemas) > 0 && matches == 0 {
+			add("doesn't match any %s schema", key)
+		} else if key == "oneOf" && matches > 1 {
+			add("matches %d oneOf schemas", matches)
+		}
+	}
+	if not := compiler.MapValueForKey(schema, "not"); not != nil && len(v.validate(value, not, pointer, depth+1)) == 0 {
+		add("matches schema that is not allowed")
+	}
+	if enum := compiler.MapValueForKey(schema, "enum"); enum != nil && enum.Kind == yaml.SequenceNode {
+		found := false
+		for _, item := range enum.Content {
+			if equal(value, item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("%s is not one of the allowed values", describe(value))
+		}
+	}
+	if t, ok := compiler.StringForScalarNode(compiler.MapValueForKey(schema, "type")); ok && t != "" {
+		if kind := kindOf(value); !(kind == t || kind == "integer" && t == "number" || t == "integer" && isIntegral(value)) {
+			add("expected %s but found %s", t, kind)
+			return mismatches
+		}
+	}
+	switch kindOf(value) {
+	case "string":
+		mismatches = append(mismatches, v.validateString(value.Value, schema, pointer)...)
+	case "integer", "number":
+		mismatches = append(mismatches, validateNumber(value, schema, pointer)...)
+	case "array":
+		mismatches = append(mismatches, v.validateArray(value, schema, pointer, depth)...)
+	case "object":
+		mismatches = append(mismatches, v.validateObject(value, schema, pointer, depth)...)
+	}
+	return mismatches
+}
+
+// validateString checks the length, pattern, and format of a string.
+func (v *validator) validateString(s string, schema *yaml.Node, pointer string) []*mismatch {
+	mismatches := make([]*mismatch, 0)
+	add := func(format string, args ...interface{}) {
+		mismatches = append(mismatches, &mismatch{pointer: pointer, text: fmt.Sprintf(format, args...)})
+	}
+	length := int64(utf8.RuneCountInString(s))
+	if min, ok := compiler.IntForScalarNode(compiler.MapValueForKey(schema, "minLength")); ok && length < min {
+		add("%q is shorter than minLength %d", s, min)
+	}
+	if max, ok := compiler.IntForScalarNode(compiler.MapValueForKey(schema, "maxLength")); ok && length > max {
+		add("%q is longer than maxLength %d", s, max)
+	}
+	if pattern, ok := compiler.StringForScalarNode(compiler.MapValueForKey(schema, "pattern")); ok {
+		if r := v.regexp(pattern); r != nil && !r.MatchString(s) {
+			add("%q doesn't match pattern %q", s, pattern)
+		}
+	}
+	if format, ok := compiler.StringForScalarNode(compiler.MapValueForKey(schema, "format")); ok && !validFormat(s, format) {
+		add("%q is not a valid %s", s, format)
+	}
+	return mismatches
+}
+
+// validFormat returns false if a string doesn't have a format.
+// Formats that aren't known are not checked.
+func validFormat(s, format string) bool {
+	var err error
+	switch format {
+	case "date":
+		_, err = time.Parse("2006-01-02", s)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, s)
+	case "byte":
+		_, err = base64.StdEncoding.DecodeString(s)
+	case "email":
+		var address *mail.Address
+		if address, err = mail.ParseAddress(s); err == nil && address.Address != s {
+			return false
+		}
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	case "uri":
+		var u *url.URL
+		if u, err = url.Parse(s); err == nil && !u.IsAbs() {
+			return false
+		}
+	}
+	return err == nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`)
+
+// validateNumber checks the bounds, multipleOf, and format of a number.
+func validateNumber(value, schema *yaml.Node, pointer string) []*mismatch {
+	mismatches := make([]*mismatch, 0)
+	add := func(format string, args ...interface{}) {
+		mismatches = append(mismatches, &mismatch{pointer: pointer, text: fmt.Sprintf(format, args...)})
+	}
+	n, err := strconv.ParseFloat(value.Value, 64)
+	if err != nil {
+		return nil
+	}
+	if min, ok := numberForNode(compiler.MapValueForKey(schema, "minimum")); ok {
+		if exclusive, _ := compiler.BoolForScalarNode(compiler.MapValueForKey(schema, "exclusiveMinimum")); exclusive && n <= min {
+			add("%s is not greater than exclusive minimum %v", value.Value, min)
+		} else if n < min {
+			add("%s is less than minimum %v", value.Value, min)
+		}
+	}
+	if max, ok := numberForNode(compiler.MapValueForKey(schema, "maximum")); ok {
+		if exclusive, _ := compiler.BoolForScalarNode(compiler.MapValueForKey(schema, "exclusiveMaximum")); exclusive && n >= max {
+			add("%s is not less than exclusive maximum %v", value.Value, max)
+		} else if n > max {
+			add("%s is greater than maximum %v", value.Value, max)
+		}
+	}
+	if m, ok := numberForNode(compiler.MapValueForKey(schema, "multipleOf")); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			add("%s is not a multiple of %v", value.Value, m)
+		}
+	}
+	format, _ := compiler.StringForScalarNode(compiler.MapValueForKey(schema, "format"))
+	switch format {
+	case "int32":
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			add("%s is not a valid int32", value.Value)
+		}
+	case "int64":
+		if _, err := strconv.ParseInt(value.Value, 0, 64); err != nil && kindOf(value) == "integer" {
+			add("%s is not a valid int64", value.Value)
+		}
+	case "float":
+		if math.Abs(n) > math.MaxFloat32 {
+			add("%s is not a valid float", value.Value)
+		}
+	}
+	return mismatches
+}
+
+// validateArray checks the length and items of an array.
+func (v *validator) validateArray(value, schema *yaml.Node, pointer string, depth int) []*mismatch {
+	mismatches := make([]*mismatch, 0)
+	add := func(format string, args ...interface{}) {
+		mismatches = append(mismatches, &mismatch{pointer: pointer, text: fmt.Sprintf(format, args...)})
+	}
+	length := int64(len(value.Content))
+	if min, ok := compiler.IntForScalarNode(compiler.MapValueForKey(schema, "minItems")); ok && length < min {
+		add("has %d items but minItems is %d", length, min)
+	}
+	if max, ok := compiler.IntForScalarNode(compiler.MapValueForKey(schema, "maxItems")); ok && length > max {
+		add("has %d items but maxItems is %d", length, max)
+	}
+	if unique, _ := compiler.BoolForScalarNode(compiler.MapValueForKey(schema, "uniqueItems")); unique {
+	outer:
+		for i := range value.Content {
+			for j := 0; j < i; j++ {
+				if equal(value.Content[i], value.Content[j]) {
+					add("has duplicate items %d and %d", j, i)
+					break outer
+				}
+			}
+		}
+	}
+	if items := compiler.MapValueForKey(schema, "items"); items != nil {
+		for i, item := range value.Content {
+			mismatches = append(mismatches, v.validate(item, items, pointer+"/"+strconv.Itoa(i), depth+1)...)
+		}
+	}
+	return mismatches
+}
+
+// validateObject checks the properties of an object.
+func (v *validator) validateObject(value, schema *yaml.Node, pointer string, depth int) []*mismatch {
+	mismatches := make([]*mismatch, 0)
+	add := func(format string, args ...interface{}) {
+		mismatches = append(mismatches, &mismatch{pointer: pointer, text: fmt.Sprintf(format, args...)})
+	}
+	count := int64(len(value.Content) / 2)
+	if min, ok := compiler.IntForScalarNode(compiler.MapValueForKey(schema, "minProperties")); ok && count < min {
+		add("has %d properties but minProperties is %d", count, min)
+	}
+	if max, ok := compiler.IntForScalarNode(compiler.MapValueForKey(schema, "maxProperties")); ok && count > max {
+		add("has %d properties but maxProperties is %d", count, max)
+	}
+	for _, name := range sequence(compiler.MapValueForKey(schema, "required")) {
+		if !compiler.MapHasKey(value, name.Value) {
+			add("is missing required property %q", name.Value)
+		}
+	}
+	properties := compiler.MapValueForKey(schema, "properties")
+	additional := compiler.MapValueForKey(schema, "additionalProperties")
+	for i := 0; i < len(value.Content)-1; i += 2 {
+		name, item := value.Content[i].Value, value.Content[i+1]
+		p := pointer + "/" + compiler.EscapePointerToken(name)
+		if property := compiler.MapValueForKey(properties, name); property != nil {
+			mismatches = append(mismatches, v.validate(item, property, p, depth+1)...)
+		} else if allowed, ok := compiler.BoolForScalarNode(additional); ok && !allowed {
+			add("has property %q that is not allowed", name)
+		} else if additional != nil && additional.Kind == yaml.MappingNode {
+			mismatches = append(mismatches, v.validate(item, additional, p, depth+1)...)
+		}
+	}
+	return mismatches
+}
+
+// resolve returns the schema that a schema refers to.
+// References that can't be resolved return nil.
+func (v *validator) resolve(schema *yaml.Node) *yaml.Node {
+	for i := 0; i < maxDepth && compiler.MapHasKey(schema, "$ref"); i++ {
+		ref, _ := compiler.StringForScalarNode(compiler.MapValueForKey(schema, "$ref"))
+		if !strings.HasPrefix(ref, "#") {
+			return nil
+		}
+		target, err := compiler.NodeForPointer(v.root, compiler.PointerForFragment(ref[1:]))
+		if err != nil {
+			return nil
+		}
+		schema = target
+	}
+	return schema
+}
+
+// regexp returns a compiled regular expression, or nil if the pattern
+// is not supported by the regexp package.
+func (v *validator) regexp(pattern string) *regexp.Regexp {
+	if r, ok := v.patterns[pattern]; ok {
+		return r
+	}
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		r = nil
+	}
+	v.patterns[pattern] = r
+	return r
+}
+
+// kindOf returns the JSON Schema type of a value.
+func kindOf(node *yaml.Node) string {
+	node = resolveAlias(node)
+	if node == nil {
+		return "null"
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
+
+// isIntegral returns true if a value is a number without a fractional part.
+func isIntegral(node *yaml.Node) bool {
+	switch kindOf(node) {
+	case "integer":
+		return true
+	case "number":
+		n, err := strconv.ParseFloat(node.Value, 64)
+		return err == nil && n == math.Trunc(n)
+	}
+	return false
+}
+
+// numberForNode returns the value of a numeric scalar.
+func numberForNode(node *yaml.Node) (float64, bool) {
+	if kind := kindOf(node); node == nil || kind != "integer" && kind != "number" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(node.Value, 64)
+	return n, err == nil
+}
+
+// equal returns true if two values are equal. The order of object properties is ignored.
+func equal(a, b *yaml.Node) bool {
+	a, b = resolveAlias(a), resolveAlias(b)
+	kind := kindOf(a)
+	if kind != kindOf(b) && !(isNumber(kind) && isNumber(kindOf(b))) {
+		return false
+	}
+	switch kind {
+	case "null":
+		return true
+	case "integer", "number":
+		x, _ := numberForNode(a)
+		y, _ := numberForNode(b)
+		return x == y
+	case "boolean":
+		x, _ := compiler.BoolForScalarNode(a)
+		y, _ := compiler.BoolForScalarNode(b)
+		return x == y
+	case "array":
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := range a.Content {
+			if !equal(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	case "object":
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := 0; i < len(a.Content)-1; i += 2 {
+			if other := compiler.MapValueForKey(b, a.Content[i].Value); other == nil || !equal(a.Content[i+1], other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a.Value == b.Value
+}
+
+func isNumber(kind string) bool {
+	return kind == "integer" || kind == "number"
+}
+
+// describe returns a short description of a value for use in messages.
+func describe(node *yaml.Node) string {
+	switch kind := kindOf(node); kind {
+	case "string":
+		return strconv.Quote(node.Value)
+	case "array", "object":
+		return "the " + kind
+	case "null":
+		return "null"
+	}
+	return node.Value
+}
+
+// sequence returns the items of a sequence node.
+func sequence(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// resolveAlias returns the node that an alias refers to.
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,282 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filter extracts subsets of OpenAPI descriptions. Operations are
+// selected by their tags, paths, operationIds, and specification extensions,
+// and the results contain only the components that the selected operations
+// transitively reference.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+	"github.com/google/gnostic/refgraph"
+)
+
+// methods lists the fields of path items that hold operations.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// A Selector matches operations that have any of the specified properties.
+type Selector struct {
+	// Tags matches operations that have any of these tags.
+	Tags []string
+	// Paths matches operations with paths that match any of these patterns.
+	// In patterns, "*" matches any sequence of characters within a path segment,
+	// "?" matches any single character within a segment, and "**" matches any
+	// sequence of characters, including "/". For example, "/admin/**" matches
+	// all paths that begin with "/admin/".
+	Paths []string
+	// OperationIDs matches operations with any of these operationIds.
+	OperationIDs []string
+	// Extensions maps the names of specification extensions to values. It matches
+	// operations that have any of these extensions with the specified value or with
+	// a list of values that contains the specified value.
+	Extensions map[string][]string
+}
+
+// Empty returns true if a selector doesn't specify any properties.
+func (s *Selector) Empty() bool {
+	return s == nil || len(s.Tags) == 0 && len(s.Paths) == 0 && len(s.OperationIDs) == 0 && len(s.Extensions) == 0
+}
+
+// Add adds a property to a selector. Properties are described with strings of the
+// form "tag:NAME", "path:PATTERN", "operation:OPERATIONID", or "x-NAME:VALUE".
+func (s *Selector) Add(property string) error {
+	parts := strings.SplitN(property, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return fmt.Errorf("invalid selector %q", property)
+	}
+	kind, value := parts[0], parts[1]
+	switch {
+	case kind == "tag":
+		s.Tags = append(s.Tags, value)
+	case kind == "path":
+		s.Paths = append(s.Paths, value)
+	case kind == "operation":
+		s.OperationIDs = append(s.OperationIDs, value)
+	case strings.HasPrefix(kind, "x-"):
+		if s.Extensions == nil {
+			s.Extensions = make(map[string][]string)
+		}
+		s.Extensions[kind] = append(s.Extensions[kind], value)
+	default:
+		return fmt.Errorf("invalid selector %q", property)
+	}
+	return nil
+}
+
+// Options specifies the operations to keep. Operations are kept if they are
+// matched by Include, or if Include is empty, and they are not matched by Exclude.
+type Options struct {
+	Include Selector
+	Exclude Selector
+}
+
+// keeps returns true if an operation should be kept.
+func (o *Options) keeps(path string, operation *yaml.Node) bool {
+	if !o.Include.Empty() && !o.Include.matches(path, operation) {
+		return false
+	}
+	return !o.Exclude.matches(path, operation)
+}
+
+// matches returns true if a selector matches an operation.
+func (s *Selector) matches(path string, operation *yaml.Node) bool {
+	for _, pattern := range s.Paths {
+		if regexpForPattern(pattern).MatchString(path) {
+			return true
+		}
+	}
+	if operationID, ok := compiler.StringForScalarNode(compiler.MapValueForKey(operation, "operationId")); ok {
+		if compiler.StringArrayContainsValue(s.OperationIDs, operationID) {
+			return true
+		}
+	}
+	for _, tag := range tagsForOperation(operation) {
+		if compiler.StringArrayContainsValue(s.Tags, tag) {
+			return true
+		}
+	}
+	for name, values := range s.Extensions {
+		extension := compiler.MapValueForKey(operation, name)
+		if extension == nil {
+			continue
+		}
+		switch extension.Kind {
+		case yaml.ScalarNode:
+			if compiler.StringArrayContainsValue(values, extension.Value) {
+				return true
+			}
+		case yaml.SequenceNode:
+			for _, item := range extension.Content {
+				if item.Kind == yaml.ScalarNode && compiler.StringArrayContainsValue(values, item.Value) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// tagsForOperation returns the tags of an operation.
+func tagsForOperation(operation *yaml.Node) []string {
+	tags := compiler.MapValueForKey(operation, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode {
+		return nil
+	}
+	result := make([]string, 0, len(tags.Content))
+	for _, tag := range tags.Content {
+		result = append(result, tag.Value)
+	}
+	return result
+}
+
+// regexpForPattern returns a regular expression that matches the paths matched by a pattern.
+func regexpForPattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Filter returns a copy of an OpenAPI v2 or v3 document that contains only the
+// operations selected by the options and the components that they transitively reference.
+// Paths without selected operations are removed, as are tags that no remaining
+// operation uses. Path items that are references can't be evaluated, so they are
+// kept and the returned messages describe them.
+func Filter(info *yaml.Node, options *Options) (*yaml.Node, []*plugins.Message, error) {
+	info = compiler.CopyNode(info)
+	root := info
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("filtering requires an OpenAPI v2 or v3 document")
+	}
+	messages := make([]*plugins.Message, 0)
+	usedTags := make(map[string]bool)
+	if paths := compiler.MapValueForKey(root, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		content := make([]*yaml.Node, 0)
+		for i := 0; i < len(paths.Content)-1; i += 2 {
+			path, pathItem := paths.Content[i].Value, paths.Content[i+1]
+			if strings.HasPrefix(path, "x-") {
+				content = append(content, paths.Content[i], pathItem)
+				continue
+			}
+			if compiler.MapValueForKey(pathItem, "$ref") != nil {
+				messages = append(messages, &plugins.Message{
+					Level: plugins.Message_WARNING,
+					Code:  "PATHITEMREFERENCE",
+					Text:  "Path item references aren't resolved when filtering, the path item was kept.",
+					Keys:  []string{"paths", path},
+				})
+				content = append(content, paths.Content[i], pathItem)
+				continue
+			}
+			if filterPathItem(path, pathItem, options, usedTags) {
+				content = append(content, paths.Content[i], pathItem)
+			}
+		}
+		paths.Content = content
+	}
+	if tags := compiler.MapValueForKey(root, "tags"); tags != nil && tags.Kind == yaml.SequenceNode {
+		content := make([]*yaml.Node, 0)
+		for _, tag := range tags.Content {
+			if name, ok := compiler.StringForScalarNode(compiler.MapValueForKey(tag, "name")); ok && usedTags[name] {
+				content = append(content, tag)
+			}
+		}
+		tags.Content = content
+	}
+	info, _, err := refgraph.PruneAllComponents(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, messages, nil
+}
+
+// filterPathItem removes the operations that aren't selected from a path item and returns
+// true if any remain. The tags of the remaining operations are added to usedTags.
+func filterPathItem(path string, pathItem *yaml.Node, options *Options, usedTags map[string]bool) bool {
+	if pathItem.Kind != yaml.MappingNode {
+		return false
+	}
+	content := make([]*yaml.Node, 0)
+	count := 0
+	for i := 0; i < len(pathItem.Content)-1; i += 2 {
+		key, value := pathItem.Content[i].Value, pathItem.Content[i+1]
+		if compiler.StringArrayContainsValue(methods, key) {
+			if !options.keeps(path, value) {
+				continue
+			}
+			count++
+			for _, tag := range tagsForOperation(value) {
+				usedTags[tag] = true
+			}
+		}
+		content = append(content, pathItem.Content[i], value)
+	}
+	pathItem.Content = content
+	return count > 0
+}
+
+// FilterOpenAPIv3 returns a copy of an OpenAPI v3 document that contains only the
+// operations selected by the options and the components that they transitively reference.
+func FilterOpenAPIv3(document *openapi3.Document, options *Options) (*openapi3.Document, []*plugins.Message, error) {
+	info, messages, err := Filter(document.ToRawInfo(), options)
+	if err != nil {
+		return nil, nil, err
+	}
+	filtered, err := openapi3.NewDocument(info, compiler.NewContext("$root", info, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return filtered, messages, nil
+}
+
+// FilterOpenAPIv2 returns a copy of an OpenAPI v2 document that contains only the
+// operations selected by the options and the definitions, parameters, and responses
+// that they transitively reference.
+func FilterOpenAPIv2(document *openapi2.Document, options *Options) (*openapi2.Document, []*plugins.Message, error) {
+	info, messages, err := Filter(document.ToRawInfo(), options)
+	if err != nil {
+		return nil, nil, err
+	}
+	filtered, err := openapi2.NewDocument(info, compiler.NewContext("$root", info, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return filtered, messages, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+func readDocument(t *testing.T) *openapi3.Document {
+	bytes, err := ioutil.ReadFile("../testdata/filter/openapi-v3.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return document
+}
+
+func selector(t *testing.T, properties ...string) Selector {
+	var s Selector
+	for _, property := range properties {
+		if err := s.Add(property); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	return s
+}
+
+func keys(node *yaml.Node) []string {
+	result := make([]string, 0)
+	if node == nil {
+		return result
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		result = append(result, node.Content[i].Value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// summary describes the operations, tags, and components of a filtered document.
+func summary(t *testing.T, document *openapi3.Document) map[string][]string {
+	root := document.ToRawInfo()
+	result := map[string][]string{}
+	paths := compiler.MapValueForKey(root, "paths")
+	for _, path := range keys(paths) {
+		for _, method := range keys(compiler.MapValueForKey(paths, path)) {
+			result["operations"] = append(result["operations"], method+" "+path)
+		}
+	}
+	if tags := compiler.MapValueForKey(root, "tags"); tags != nil {
+		for _, tag := range tags.Content {
+			result["tags"] = append(result["tags"], compiler.MapValueForKey(tag, "name").Value)
+		}
+	}
+	components := compiler.MapValueForKey(root, "components")
+	for _, section := range keys(components) {
+		result[section] = keys(compiler.MapValueForKey(components, section))
+	}
+	return result
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		expected map[string][]string
+	}{
+		{
+			name:    "tag",
+			options: Options{Include: selector(t, "tag:stores")},
+			expected: map[string][]string{
+				"operations": {"get /stores/{storeId}"},
+				"tags":       {"stores"},
+				"parameters": {"storeId"},
+				"schemas":    {"Pet", "Store", "Tag"},
+			},
+		},
+		{
+			name:    "path",
+			options: Options{Include: selector(t, "path:/admin/**")},
+			expected: map[string][]string{
+				"operations":      {"get /admin/stores/{storeId}/audit"},
+				"tags":            {"admin"},
+				"parameters":      {"storeId"},
+				"securitySchemes": {"adminKey"},
+			},
+		},
+		{
+			name:    "operation",
+			options: Options{Include: selector(t, "operation:listPets")},
+			expected: map[string][]string{
+				"operations": {"get /pets"},
+				"tags":       {"pets"},
+				"responses":  {"Error"},
+				"schemas":    {"Error", "Pet", "Tag"},
+			},
+		},
+		{
+			name:    "extension",
+			options: Options{Include: selector(t, "x-audience:partner", "x-audience:public")},
+			expected: map[string][]string{
+				"operations":    {"post /pets", "get /stores/{storeId}"},
+				"tags":          {"pets", "stores"},
+				"parameters":    {"storeId"},
+				"requestBodies": {"NewPet"},
+				"schemas":       {"Pet", "Store", "Tag"},
+			},
+		},
+		{
+			name: "exclude",
+			options: Options{
+				Include: selector(t, "path:/*/*"),
+				Exclude: selector(t, "tag:admin", "operation:createPet"),
+			},
+			expected: map[string][]string{
+				"operations": {"get /stores/{storeId}"},
+				"tags":       {"stores"},
+				"parameters": {"storeId"},
+				"schemas":    {"Pet", "Store", "Tag"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := readDocument(t)
+			filtered, _, err := FilterOpenAPIv3(document, &test.options)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if s := summary(t, filtered); !reflect.DeepEqual(s, test.expected) {
+				t.Errorf("unexpected result: %+v", s)
+			}
+			if len(document.Paths.Path) != 3 {
+				t.Errorf("filtering modified the original document")
+			}
+		})
+	}
+}
+
+func TestSelectorAdd(t *testing.T) {
+	for _, property := range []string{"", "tag", "tag:", "name:x", "X-audience:public"} {
+		var s Selector
+		if err := s.Add(property); err == nil {
+			t.Errorf("expected an error for %q", property)
+		}
+	}
+}
+
+func TestRegexpForPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{"/pets", "/pets", true},
+		{"/pets", "/pets/{id}", false},
+		{"/pets/*", "/pets/{id}", true},
+		{"/pets/*", "/pets/{id}/owner", false},
+		{"/pets/**", "/pets/{id}/owner", true},
+		{"/pet?", "/pets", true},
+		{"/pet?", "/pet/", false},
+		{"/v1.0/*", "/v1x0/pets", false},
+	}
+	for _, test := range tests {
+		if regexpForPattern(test.pattern).MatchString(test.path) != test.matches {
+			t.Errorf("pattern %q matching %q: expected %t", test.pattern, test.path, test.matches)
+		}
+	}
+}
+
+func TestFilterKeepsPathItemReferences(t *testing.T) {
+	document, err := openapi3.ParseDocument([]byte(`openapi: 3.0.0
+info:
+  title: References
+  version: 1.0.0
+paths:
+  /a:
+    $ref: 'paths.yaml#/a'
+  /b:
+    get:
+      tags: [x]
+      responses:
+        '200':
+          description: OK
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	filtered, messages, err := FilterOpenAPIv3(document, &Options{Exclude: selector(t, "tag:x")})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	paths := filtered.Paths.Path
+	if len(paths) != 1 || paths[0].Name != "/a" || paths[0].Value.XRef != "paths.yaml#/a" {
+		t.Errorf("unexpected paths: %+v", paths)
+	}
+	if len(messages) != 1 || messages[0].Code != "PATHITEMREFERENCE" || !reflect.DeepEqual(messages[0].Keys, []string{"paths", "/a"}) {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
@@ -342,14 +342,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			if propertyType == "int" {
 				propertyType = "int64"
 			}
-			var displayName = propertyName
-			if displayName == "$ref" {
-				displayName = "_ref"
-			}
-			if displayName == "$schema" {
-				displayName = "_schema"
-			}
-			displayName = camelCaseToSnakeCase(displayName)
+			displayName := propertyModel.ProtoFieldName()
 
 			var line = fmt.Sprintf("%s %s = %d;", propertyType, displayName, fieldNumber)
 			if propertyModel.Repeated {
@@ -357,10 +350,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			}
 			code.Print("// " + line)
 
-			fieldName := strings.Title(snakeCaseToCamelCase(propertyName))
-			if propertyName == "$ref" {
-				fieldName = "XRef"
-			}
+			fieldName := propertyModel.FieldName()
 
 			typeModel, typeFound := domain.TypeModels[propertyType]
 			if typeFound && !typeModel.IsPair {
@@ -600,6 +590,11 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 func (domain *Domain) generateResolveReferencesMethodsForType(code *printer.Code, typeName string) {
 	code.Print("// ResolveReferences resolves references found inside %s objects.", typeName)
 	code.Print("func (m *%s) ResolveReferences(root string) (*yaml.Node, error) {", typeName)
+	code.Print("return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI(\"$root\", nil, nil, root))")
+	code.Print("}\n")
+	code.Print("// ResolveReferencesInContext resolves references found inside %s objects", typeName)
+	code.Print("// using the session and base URI of the specified context.")
+	code.Print("func (m *%s) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {", typeName)
 	code.Print("errors := make([]error, 0)")
 
 	typeModel := domain.TypeModels[typeName]
@@ -613,7 +608,7 @@ func (domain *Domain) generateResolveReferencesMethodsForType(code *printer.Code
 				code.Print("p, ok := m.Oneof.(*%s_%s)", typeName, propertyType)
 				code.Print("if ok {")
 				if propertyType == "JsonReference" { // Special case for OpenAPI
-					code.Print("info, err := p.%s.ResolveReferences(root)", propertyType)
+					code.Print("info, err := p.%s.ResolveReferencesInContext(context)", propertyType)
 					code.Print("if err != nil {")
 					code.Print("  return nil, err")
 					code.Print("} else if info != nil {")
@@ -626,7 +621,7 @@ func (domain *Domain) generateResolveReferencesMethodsForType(code *printer.Code
 					code.Print("  }")
 					code.Print("}")
 				} else {
-					code.Print("_, err := p.%s.ResolveReferences(root)", propertyType)
+					code.Print("_, err := p.%s.ResolveReferencesInContext(context)", propertyType)
 					code.Print("if err != nil {")
 					code.Print("	return nil, err")
 					code.Print("}")
@@ -638,21 +633,15 @@ func (domain *Domain) generateResolveReferencesMethodsForType(code *printer.Code
 	} else {
 		for _, propertyModel := range typeModel.Properties {
 			propertyName := propertyModel.Name
-			var displayName = propertyName
-			if displayName == "$ref" {
-				displayName = "_ref"
-			}
-			if displayName == "$schema" {
-				displayName = "_schema"
-			}
-			displayName = camelCaseToSnakeCase(displayName)
-
-			fieldName := strings.Title(propertyName)
+			fieldName := propertyModel.FieldName()
 			if propertyName == "$ref" {
-				fieldName = "XRef"
 				code.Print("if m.XRef != \"\" {")
 				//code.Print("log.Printf(\"%s reference to resolve %%+v\", m.XRef)", typeName)
-				code.Print("info, err := compiler.ReadInfoForRef(root, m.XRef)")
+				if len(typeModel.Properties) > 1 {
+					code.Print("info, refContext, err := context.ReadInfoForRef(m.XRef)")
+				} else {
+					code.Print("info, _, err := context.ReadInfoForRef(m.XRef)")
+				}
 
 				code.Print("if err != nil {")
 				code.Print("	return nil, err")
@@ -661,10 +650,10 @@ func (domain *Domain) generateResolveReferencesMethodsForType(code *printer.Code
 
 				if len(typeModel.Properties) > 1 {
 					code.Print("if info != nil {")
-					code.Print("  replacement, err := New%s(info, nil)", typeName)
+					code.Print("  replacement, err := New%s(info, refContext)", typeName)
 					code.Print("  if err == nil {")
 					code.Print("    *m = *replacement")
-					code.Print("    return m.ResolveReferences(root)")
+					code.Print("    return m.ResolveReferencesInContext(refContext)")
 					code.Print("  }")
 					code.Print("}")
 				}
@@ -678,7 +667,7 @@ func (domain *Domain) generateResolveReferencesMethodsForType(code *printer.Code
 				typeModel, typeFound := domain.TypeModels[propertyType]
 				if typeFound && !typeModel.IsPair {
 					code.Print("if m.%s != nil {", fieldName)
-					code.Print("    _, err := m.%s.ResolveReferences(root)", fieldName)
+					code.Print("    _, err := m.%s.ResolveReferencesInContext(context)", fieldName)
 					code.Print("    if err != nil {")
 					code.Print("       errors = append(errors, err)")
 					code.Print("    }")
@@ -690,7 +679,7 @@ func (domain *Domain) generateResolveReferencesMethodsForType(code *printer.Code
 				if typeFound {
 					code.Print("for _, item := range m.%s {", fieldName)
 					code.Print("if item != nil {")
-					code.Print("  _, err := item.ResolveReferences(root)")
+					code.Print("  _, err := item.ResolveReferencesInContext(context)")
 					code.Print("  if err != nil {")
 					code.Print("     errors = append(errors, err)")
 					code.Print("  }")
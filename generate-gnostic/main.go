@@ -107,6 +107,11 @@ func generateOpenAPIModel(version string) error {
 		filename = "OpenAPIv3"
 		protoPackageName = "openapi.v3"
 		directoryName = "openapiv3"
+	case "v31":
+		input = "openapi-3.1.json"
+		filename = "OpenAPIv31"
+		protoPackageName = "openapi.v31"
+		directoryName = "openapiv31"
 	case "discovery":
 		input = "discovery.json"
 		filename = "discovery"
@@ -148,7 +153,7 @@ func generateOpenAPIModel(version string) error {
 			"PathItem":      "Path",
 			"ResponseValue": "ResponseCode",
 		}
-	case "v3":
+	case "v3", "v31":
 		cc.TypeNameOverrides = map[string]string{
 			"SpecificationExtension": "Any",
 		}
@@ -228,6 +233,10 @@ Options:
     Generate Protocol Buffer representation and support code for OpenAPI v3
     Files are read from and written to appropriate locations in the gnostic
     project directory.
+  --v31
+    Generate Protocol Buffer representation and support code for OpenAPI 3.1.
+    Files are read from and written to appropriate locations in the gnostic
+    project directory.
   --extension EXTENSION_SCHEMA [EXTENSIONOPTIONS]
     Generate a gnostic extension that reads a set of OpenAPI extensions.
     EXTENSION_SCHEMA is the json schema for the OpenAPI extensions to be
@@ -249,6 +258,8 @@ func main() {
 			openapiVersion = "v2"
 		} else if arg == "--v3" {
 			openapiVersion = "v3"
+		} else if arg == "--v31" {
+			openapiVersion = "v31"
 		} else if arg == "--discovery" {
 			openapiVersion = "discovery"
 		} else if arg == "--extension" {
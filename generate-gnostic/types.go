@@ -77,14 +77,25 @@ func NewTypePropertyWithNameTypeAndPattern(name string, typeName string, pattern
 }
 
 // FieldName returns the message field name to use for a property.
+// Keywords like "$ref" and "$defs" map to names with an "X" prefix,
+// which matches the names that protoc-gen-go generates for them.
 func (typeProperty *TypeProperty) FieldName() string {
 	propertyName := typeProperty.Name
-	if propertyName == "$ref" {
-		return "XRef"
+	if strings.HasPrefix(propertyName, "$") {
+		return "X" + strings.Title(snakeCaseToCamelCase(propertyName[1:]))
 	}
 	return strings.Title(snakeCaseToCamelCase(propertyName))
 }
 
+// ProtoFieldName returns the field name to use for a property in a .proto file.
+func (typeProperty *TypeProperty) ProtoFieldName() string {
+	propertyName := typeProperty.Name
+	if strings.HasPrefix(propertyName, "$") {
+		propertyName = "_" + propertyName[1:]
+	}
+	return camelCaseToSnakeCase(propertyName)
+}
+
 // TypeModel models types.
 type TypeModel struct {
 	Name          string          // type name
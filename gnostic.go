@@ -32,8 +32,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/google/gnostic/lib"
 )
@@ -41,13 +44,25 @@ import (
 func main() {
 	// To simplify testing, Gnostic is implemented in an embeddable library.
 	g := lib.NewGnostic(os.Args)
-	err := g.Main()
+	// Stop running plugins and skip remaining steps when gnostic is interrupted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// Restore the default behavior so that a second interrupt exits immediately.
+		<-ctx.Done()
+		stop()
+	}()
+	err := g.MainContext(ctx)
 	if err != nil {
 		// only print UsageErrors; other errors are written to the specified error output
 		if _, ok := err.(*lib.UsageError); ok {
 			fmt.Fprintf(os.Stdout, "%s\n", err.Error())
 			fmt.Fprintf(os.Stdout, "%s\n", g.Usage())
 		}
+		// messages that cause failures have been reported, so use a distinct status
+		if _, ok := err.(*lib.MessagesError); ok {
+			os.Exit(1)
+		}
 		os.Exit(-1)
 	}
 }
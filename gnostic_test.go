@@ -15,14 +15,25 @@
 package main
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+
+	"github.com/google/gnostic/compiler"
 	"github.com/google/gnostic/lib"
+	plugins "github.com/google/gnostic/plugins"
 )
 
 func isURL(path string) bool {
@@ -109,6 +120,13 @@ func TestSeparateJSON(t *testing.T) {
 		"testdata/v2.0/yaml/petstore-separate/spec/swagger.text") // yaml and json results should be identical
 }
 
+func TestNestedYAML(t *testing.T) {
+	// References are resolved relative to the files that contain them.
+	testNormal(t,
+		"examples/v2.0/yaml/petstore-nested/spec/swagger.yaml",
+		"testdata/v2.0/yaml/petstore-nested/spec/swagger.text")
+}
+
 func TestRemotePetstoreJSON(t *testing.T) {
 	testNormal(t,
 		"https://raw.githubusercontent.com/google/gnostic/master/examples/v2.0/json/petstore.json",
@@ -151,6 +169,37 @@ func TestErrorMissingVersion(t *testing.T) {
 		"testdata/errors/petstore-missingversion.errors")
 }
 
+func TestErrorSemantic(t *testing.T) {
+	testErrors(t,
+		"examples/errors/petstore-semantic.yaml",
+		"testdata/errors/petstore-semantic.errors")
+}
+
+func TestErrorSemanticV2(t *testing.T) {
+	testErrors(t,
+		"examples/errors/swagger-semantic.yaml",
+		"testdata/errors/swagger-semantic.errors")
+}
+
+func TestErrorSemanticDiscovery(t *testing.T) {
+	testErrors(t,
+		"examples/errors/discovery-semantic.json",
+		"testdata/errors/discovery-semantic.errors")
+}
+
+func TestNoValidation(t *testing.T) {
+	for _, inputFile := range []string{
+		"examples/errors/petstore-semantic.yaml",
+		"examples/errors/swagger-semantic.yaml",
+		"examples/errors/discovery-semantic.json",
+	} {
+		args := []string{"gnostic", inputFile, "--no-validation", "--text-out=!", "--errors-out=!"}
+		if err := lib.NewGnostic(args).Main(); err != nil {
+			t.Errorf("Unexpected error for command %v: %+v", strings.Join(args, " "), err)
+		}
+	}
+}
+
 func TestJSONOutput(t *testing.T) {
 	inputFile := "testdata/library-example-with-ext.json"
 
@@ -294,3 +343,636 @@ func TestDiscoveryJSON(t *testing.T) {
 		"examples/discovery/discovery-v1.json",
 		"testdata/discovery/discovery-v1.text")
 }
+
+// OpenAPI 3.1 tests
+
+func TestPetstoreYAML_31(t *testing.T) {
+	testNormal(t,
+		"examples/v3.1/yaml/petstore.yaml",
+		"testdata/v3.1/petstore.text")
+}
+
+// Conversion tests
+
+func testConversion(t *testing.T, inputFile string, format string, referenceFile string) {
+	outputFile := strings.Replace(filepath.Base(inputFile), filepath.Ext(inputFile), ".yaml", 1)
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		inputFile,
+		"--convert-to=" + format,
+		"--yaml-out=" + outputFile}
+	g := lib.NewGnostic(args)
+	err := g.Main()
+	if err != nil {
+		t.Logf("Conversion failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	err = exec.Command("diff", outputFile, referenceFile).Run()
+	if err != nil {
+		t.Logf("Diff failed (%s vs %s): %+v", outputFile, referenceFile, err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove(outputFile)
+	}
+}
+
+func TestConvertPetstoreExpandedToOpenAPI3(t *testing.T) {
+	testConversion(t,
+		"examples/v2.0/yaml/petstore-expanded.yaml",
+		"openapi3",
+		"testdata/v3.0/yaml/converted-petstore-expanded.yaml")
+}
+
+func TestConvertUberToOpenAPI3(t *testing.T) {
+	testConversion(t,
+		"examples/v2.0/yaml/uber.yaml",
+		"openapi3",
+		"testdata/v3.0/yaml/converted-uber.yaml")
+}
+
+func TestConvertPetstoreToOpenAPI2(t *testing.T) {
+	testConversion(t,
+		"examples/v3.0/yaml/petstore.yaml",
+		"openapi2",
+		"testdata/v2.0/yaml/converted-petstore.yaml")
+}
+
+func TestDiscoveryOutput(t *testing.T) {
+	inputFile := "examples/v3.0/yaml/petstore.yaml"
+	referenceFile := "testdata/discovery/petstore.discovery.json"
+	outputFile := "petstore.discovery.json"
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		inputFile,
+		"--discovery-out=" + outputFile}
+	g := lib.NewGnostic(args)
+	err := g.Main()
+	if err != nil {
+		t.Logf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	// Verify that the output is a valid Discovery document.
+	args = []string{
+		"gnostic",
+		outputFile,
+		"--text-out=!"}
+	g = lib.NewGnostic(args)
+	err = g.Main()
+	if err != nil {
+		t.Logf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	err = exec.Command("diff", outputFile, referenceFile).Run()
+	if err != nil {
+		t.Logf("Diff failed (%s vs %s): %+v", outputFile, referenceFile, err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove(outputFile)
+	}
+}
+
+// Bundling tests
+
+func testBundle(t *testing.T, inputFile string, outputFile string, referenceFile string) {
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		inputFile,
+		"--bundle-out=" + outputFile}
+	g := lib.NewGnostic(args)
+	err := g.Main()
+	if err != nil {
+		t.Logf("Bundling failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	// Verify that the bundled output can be compiled on its own.
+	args = []string{
+		"gnostic",
+		outputFile,
+		"--text-out=!"}
+	g = lib.NewGnostic(args)
+	err = g.Main()
+	if err != nil {
+		t.Logf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	err = exec.Command("diff", outputFile, referenceFile).Run()
+	if err != nil {
+		t.Logf("Diff failed (%s vs %s): %+v", outputFile, referenceFile, err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove(outputFile)
+	}
+}
+
+func TestBundlePetstoreSeparate(t *testing.T) {
+	testBundle(t,
+		"examples/v2.0/yaml/petstore-separate/spec/swagger.yaml",
+		"petstore-separate.json",
+		"testdata/bundle/petstore-separate.json")
+}
+
+func TestBundleOpenAPI3(t *testing.T) {
+	testBundle(t,
+		"testdata/bundle/v3.0/openapi.yaml",
+		"openapi-bundled.yaml",
+		"testdata/bundle/openapi-bundled.yaml")
+}
+
+func testPrune(t *testing.T, inputFile string, outputFile string, referenceFile string) {
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		inputFile,
+		"--no-validation",
+		"--prune-out=" + outputFile}
+	g := lib.NewGnostic(args)
+	err := g.Main()
+	if err != nil {
+		t.Logf("Pruning failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	err = exec.Command("diff", outputFile, referenceFile).Run()
+	if err != nil {
+		t.Logf("Diff failed (%s vs %s): %+v", outputFile, referenceFile, err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove(outputFile)
+	}
+}
+
+func TestPruneOpenAPI3(t *testing.T) {
+	testPrune(t,
+		"testdata/refgraph/openapi-v3.yaml",
+		"openapi-v3-pruned.yaml",
+		"testdata/refgraph/openapi-v3-pruned.yaml")
+}
+
+func TestPruneOpenAPI2(t *testing.T) {
+	testPrune(t,
+		"testdata/refgraph/swagger-v2.yaml",
+		"swagger-v2-pruned.json",
+		"testdata/refgraph/swagger-v2-pruned.json")
+}
+
+func TestOverlay(t *testing.T) {
+	outputFile := "petstore-overlay.yaml"
+	referenceFile := "testdata/overlay/petstore.yaml"
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--overlay=testdata/overlay/petstore-overlay.yaml",
+		"--messages-out=!",
+		"--yaml-out=" + outputFile}
+	g := lib.NewGnostic(args)
+	err := g.Main()
+	if err != nil {
+		t.Logf("Applying overlay failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	err = exec.Command("diff", outputFile, referenceFile).Run()
+	if err != nil {
+		t.Logf("Diff failed (%s vs %s): %+v", outputFile, referenceFile, err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove(outputFile)
+	}
+}
+
+func TestMessagesFormat(t *testing.T) {
+	outputFile := "petstore-messages.github"
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--overlay=testdata/overlay/petstore-overlay.yaml",
+		"--messages-out=" + outputFile,
+		"--messages-format=github"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Unexpected error for command %v: %+v", strings.Join(args, " "), err)
+	}
+	b, err := ioutil.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := "::warning file=examples/v3.0/yaml/petstore.yaml,title=UNMATCHEDTARGET::" +
+		"Target $.paths['/stores'] of action 5 of overlay \"Petstore vendor changes\" doesn't select any nodes.\n"
+	if string(b) != expected {
+		t.Errorf("Unexpected messages: %s", string(b))
+	} else {
+		os.Remove(outputFile)
+	}
+	args = []string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--messages-out=!", "--messages-format=csv"}
+	if err := lib.NewGnostic(args).Main(); err == nil || !strings.Contains(err.Error(), "unsupported messages format: csv") {
+		t.Errorf("Expected error for unsupported format, got %+v", err)
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--overlay=testdata/overlay/petstore-overlay.yaml",
+		"--messages-out=!",
+		"--fail-on=warning"}
+	g := lib.NewGnostic(args)
+	err := g.Main()
+	messagesError, ok := err.(*lib.MessagesError)
+	if !ok {
+		t.Fatalf("Expected MessagesError for command %v, got %+v", strings.Join(args, " "), err)
+	}
+	if messagesError.Error() != "1 message at or above level WARNING" {
+		t.Errorf("Unexpected error: %s", messagesError.Error())
+	}
+	summary := g.MessagesSummary()
+	if summary.Levels[plugins.Message_WARNING] != 1 || summary.Sources["gnostic"][plugins.Message_WARNING] != 1 {
+		t.Errorf("Unexpected summary: %s", summary)
+	}
+	if s := summary.String(); s != "Messages: 1 WARNING\n  gnostic: 1 WARNING\n" {
+		t.Errorf("Unexpected summary: %s", s)
+	}
+	args[len(args)-1] = "--fail-on=error"
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Errorf("Unexpected error for command %v: %+v", strings.Join(args, " "), err)
+	}
+	args[len(args)-1] = "--fail-on=info"
+	if _, ok := lib.NewGnostic(args).Main().(*lib.UsageError); !ok {
+		t.Errorf("Expected usage error for command %v", strings.Join(args, " "))
+	}
+}
+
+func TestFilterOpenAPI3(t *testing.T) {
+	outputFile := "openapi-v3-filtered.yaml"
+	referenceFile := "testdata/filter/openapi-v3-filtered.yaml"
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		"testdata/filter/openapi-v3.yaml",
+		"--include=tag:pets",
+		"--include=path:/admin/**",
+		"--exclude=x-audience:internal",
+		"--yaml-out=" + outputFile}
+	g := lib.NewGnostic(args)
+	err := g.Main()
+	if err != nil {
+		t.Logf("Filtering failed for command %v: %+v", strings.Join(args, " "), err)
+		t.FailNow()
+	}
+	err = exec.Command("diff", outputFile, referenceFile).Run()
+	if err != nil {
+		t.Logf("Diff failed (%s vs %s): %+v", outputFile, referenceFile, err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove(outputFile)
+	}
+}
+
+// Loader tests
+
+func TestHTTPHeaderOption(t *testing.T) {
+	files := http.FileServer(http.Dir("examples/v2.0/yaml/petstore-separate"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	source := server.URL + "/spec/swagger.yaml"
+	args := []string{"gnostic", source, "--resolve-refs", "--text-out=!", "--errors-out=!"}
+	if err := lib.NewGnostic(args).Main(); err == nil {
+		t.Errorf("Expected unauthorized request to fail: %v", strings.Join(args, " "))
+	}
+	args = []string{"gnostic", source, "--resolve-refs", "--http-header=Authorization: Bearer secret", "--http-timeout=10s", "--text-out=remote.text"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	args = []string{"gnostic", "examples/v2.0/yaml/petstore-separate/spec/swagger.yaml", "--resolve-refs", "--text-out=local.text"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	err := exec.Command("diff", "remote.text", "local.text").Run()
+	if err != nil {
+		t.Logf("Diff failed (remote.text vs local.text): %+v", err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove("remote.text")
+		os.Remove("local.text")
+	}
+}
+
+func TestLibraryLoader(t *testing.T) {
+	loader := &compiler.MapLoader{Files: map[string][]byte{
+		"memory/openapi.yaml": []byte(`openapi: 3.0.0
+info:
+  title: In Memory
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      $ref: 'Pet.yaml'
+`),
+		"memory/Pet.yaml": []byte("type: object\n"),
+	}}
+	args := []string{"gnostic", "memory/openapi.yaml", "--resolve-refs", "--text-out=!"}
+	g := lib.NewGnostic(args)
+	g.SetLoader(loader)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	// Without the loader, the source can't be found.
+	args = []string{"gnostic", "memory/openapi.yaml", "--text-out=!", "--errors-out=!"}
+	if err := lib.NewGnostic(args).Main(); err == nil {
+		t.Errorf("Expected compile to fail without a loader: %v", strings.Join(args, " "))
+	}
+}
+
+func TestConcurrentCompilations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnostic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// Entries in the default session's cache are not cleared by compilations.
+	if _, err := compiler.ReadInfoFromBytes("cached.yaml", []byte("a: b")); err != nil {
+		t.Fatal(err)
+	}
+	defer compiler.ClearCaches()
+	titles := []string{"First", "Second", "Third", "Fourth"}
+	var wg sync.WaitGroup
+	errs := make([]error, len(titles))
+	for i, title := range titles {
+		wg.Add(1)
+		go func(i int, title string) {
+			defer wg.Done()
+			loader := &compiler.MapLoader{Files: map[string][]byte{
+				"memory/openapi.yaml": []byte("openapi: 3.0.0\ninfo:\n  title: " + title + "\n  version: 1.0.0\npaths: {}\n" +
+					"components:\n  schemas:\n    Pet:\n      $ref: 'Pet.yaml'\n"),
+				"memory/Pet.yaml": []byte("description: " + title + "\n"),
+			}}
+			g := lib.NewGnostic([]string{"gnostic", "memory/openapi.yaml", "--bundle-out=" + filepath.Join(dir, title+".yaml")})
+			g.SetLoader(loader)
+			errs[i] = g.Main()
+		}(i, title)
+	}
+	wg.Wait()
+	for i, title := range titles {
+		if errs[i] != nil {
+			t.Fatalf("Compile failed for %s: %+v", title, errs[i])
+		}
+		bytes, err := ioutil.ReadFile(filepath.Join(dir, title+".yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(string(bytes), title) != 2 {
+			t.Errorf("Unexpected output for %s:\n%s", title, string(bytes))
+		}
+	}
+	if _, ok := compiler.GetInfoCache()["cached.yaml"]; !ok {
+		t.Errorf("Default session cache was cleared")
+	}
+}
+
+// writeScriptPlugins writes shell scripts that act as plugins into a
+// temporary directory and adds that directory to the PATH.
+func writeScriptPlugins(t *testing.T, scripts map[string]string) (dir string, cleanup func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script plugins require a POSIX shell")
+	}
+	dir, err := ioutil.TempDir("", "gnostic-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, script := range scripts {
+		err = ioutil.WriteFile(filepath.Join(dir, "gnostic-"+name), []byte("#!/bin/sh\n"+script), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	return dir, func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestConcurrentPlugins(t *testing.T) {
+	dir, cleanup := writeScriptPlugins(t, map[string]string{
+		"first":  "cat > /dev/null\nsleep 0.5\ncat \"$(dirname \"$0\")/first.pb\"\n",
+		"second": "cat > /dev/null\ncat \"$(dirname \"$0\")/second.pb\"\n",
+	})
+	defer cleanup()
+	for _, code := range []string{"first", "second"} {
+		response := &plugins.Response{Messages: []*plugins.Message{
+			{Level: plugins.Message_INFO, Code: strings.ToUpper(code), Text: "Called " + code}}}
+		bytes, err := proto.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, code+".pb"), bytes, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	messagesPath := filepath.Join(dir, "messages.txt")
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--first",
+		"--second",
+		"--plugin-workers=2",
+		"--messages-format=text",
+		"--messages-out=" + messagesPath}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Failed to run %v: %+v", strings.Join(args, " "), err)
+	}
+	messages, err := ioutil.ReadFile(messagesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second plugin finishes first, but messages are in invocation order.
+	first, second := strings.Index(string(messages), "FIRST"), strings.Index(string(messages), "SECOND")
+	if first < 0 || second < 0 || first > second {
+		t.Errorf("Unexpected messages: %s", messages)
+	}
+	for _, arg := range []string{"--plugin-workers=0", "--plugin-timeout=never"} {
+		args := []string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--first", arg}
+		if _, ok := lib.NewGnostic(args).Main().(*lib.UsageError); !ok {
+			t.Errorf("Expected usage error for command %v", strings.Join(args, " "))
+		}
+	}
+}
+
+func TestPluginOutputOrder(t *testing.T) {
+	dir, cleanup := writeScriptPlugins(t, map[string]string{
+		"first":  "cat > /dev/null\nsleep 0.5\ncat \"$(dirname \"$0\")/first.pb\"\n",
+		"second": "cat > /dev/null\ncat \"$(dirname \"$0\")/second.pb\"\n",
+	})
+	defer cleanup()
+	for _, name := range []string{"first", "second"} {
+		response := &plugins.Response{Files: []*plugins.File{{Name: name + ".txt", Data: []byte(name)}}}
+		bytes, err := proto.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, name+".pb"), bytes, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--first-out=-",
+		"--second-out=-",
+		"--plugin-workers=2"}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = lib.NewGnostic(args).Main()
+	os.Stdout = stdout
+	w.Close()
+	output, _ := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to run %v: %+v", strings.Join(args, " "), err)
+	}
+	// The second plugin finishes first, but outputs are written in invocation order.
+	first, second := strings.Index(string(output), "first.txt"), strings.Index(string(output), "second.txt")
+	if first < 0 || second < 0 || first > second {
+		t.Errorf("Unexpected output: %s", output)
+	}
+}
+
+func TestPluginTimeout(t *testing.T) {
+	_, cleanup := writeScriptPlugins(t, map[string]string{
+		"sleep": "exec sleep 10\n",
+	})
+	defer cleanup()
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--sleep",
+		"--plugin-timeout=100ms",
+		"--errors-out=!"}
+	start := time.Now()
+	err := lib.NewGnostic(args).Main()
+	if err == nil || !strings.Contains(err.Error(), "gnostic-sleep timed out after 100ms") {
+		t.Errorf("Expected timeout for command %v, got %+v", strings.Join(args, " "), err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Plugin wasn't killed after its timeout (ran for %s)", elapsed)
+	}
+}
+
+func TestPluginTimeoutWithChildProcess(t *testing.T) {
+	// The shell waits for a child that inherits its stdout, so the
+	// plugin's output isn't closed until the child is also killed.
+	_, cleanup := writeScriptPlugins(t, map[string]string{
+		"sleep": "sleep 10\n",
+	})
+	defer cleanup()
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--sleep",
+		"--plugin-timeout=100ms",
+		"--errors-out=!"}
+	start := time.Now()
+	err := lib.NewGnostic(args).Main()
+	if err == nil || !strings.Contains(err.Error(), "gnostic-sleep timed out after 100ms") {
+		t.Errorf("Expected timeout for command %v, got %+v", strings.Join(args, " "), err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Plugin's child process wasn't killed after its timeout (ran for %s)", elapsed)
+	}
+}
+
+func TestPluginCancellation(t *testing.T) {
+	_, cleanup := writeScriptPlugins(t, map[string]string{
+		"sleep": "exec sleep 10\n",
+	})
+	defer cleanup()
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--sleep",
+		"--sleep",
+		"--plugin-workers=1",
+		"--errors-out=!"}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := lib.NewGnostic(args).MainContext(ctx)
+	if err == nil || strings.Count(err.Error(), "gnostic-sleep was canceled") != 2 {
+		t.Errorf("Expected cancellation for command %v, got %+v", strings.Join(args, " "), err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Plugins weren't stopped after cancellation (ran for %s)", elapsed)
+	}
+}
+
+func TestFailOnWithPluginErrors(t *testing.T) {
+	_, cleanup := writeScriptPlugins(t, map[string]string{
+		"fail": "cat > /dev/null\nexit 1\n",
+	})
+	defer cleanup()
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--overlay=testdata/overlay/petstore-overlay.yaml",
+		"--fail",
+		"--messages-out=!",
+		"--errors-out=!",
+		"--fail-on=warning"}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	err = lib.NewGnostic(args).Main()
+	os.Stderr = stderr
+	w.Close()
+	output, _ := ioutil.ReadAll(r)
+	if _, ok := err.(*lib.MessagesError); ok || err == nil {
+		t.Errorf("Expected plugin error for command %v, got %+v", strings.Join(args, " "), err)
+	}
+	if !strings.Contains(string(output), "Messages: 1 WARNING\n") {
+		t.Errorf("Expected messages summary, got %s", output)
+	}
+}
+
+func TestCanceledBeforeActions(t *testing.T) {
+	outputFile := "petstore-canceled.yaml"
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--yaml-out=" + outputFile,
+		"--errors-out=!"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := lib.NewGnostic(args).MainContext(ctx)
+	if err == nil || !strings.Contains(err.Error(), "gnostic was canceled") {
+		t.Errorf("Expected cancellation for command %v, got %+v", strings.Join(args, " "), err)
+	}
+	if _, err := os.Stat(outputFile); err == nil {
+		os.Remove(outputFile)
+		t.Errorf("Output was written after cancellation")
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonpath
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// A logicalExpression is the condition of a filter selector.
+type logicalExpression interface {
+	test(root, current *yaml.Node) bool
+}
+
+// orExpression is true if any of its operands is true.
+type orExpression []logicalExpression
+
+func (e orExpression) test(root, current *yaml.Node) bool {
+	for _, operand := range e {
+		if operand.test(root, current) {
+			return true
+		}
+	}
+	return false
+}
+
+// andExpression is true if all of its operands are true.
+type andExpression []logicalExpression
+
+func (e andExpression) test(root, current *yaml.Node) bool {
+	for _, operand := range e {
+		if !operand.test(root, current) {
+			return false
+		}
+	}
+	return true
+}
+
+// notExpression is true if its operand is false.
+type notExpression struct {
+	operand logicalExpression
+}
+
+func (e notExpression) test(root, current *yaml.Node) bool {
+	return !e.operand.test(root, current)
+}
+
+// existenceExpression is true if a query selects any nodes.
+type existenceExpression struct {
+	path *Path
+}
+
+func (e existenceExpression) test(root, current *yaml.Node) bool {
+	return len(e.path.evaluate(root, current)) > 0
+}
+
+// comparisonExpression compares two values.
+type comparisonExpression struct {
+	left     comparable
+	operator string
+	right    comparable
+}
+
+func (e comparisonExpression) test(root, current *yaml.Node) bool {
+	left := e.left.value(root, current)
+	right := e.right.value(root, current)
+	switch e.operator {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "<":
+		return less(left, right)
+	case "<=":
+		return less(left, right) || equal(left, right)
+	case ">":
+		return less(right, left)
+	case ">=":
+		return less(right, left) || equal(left, right)
+	}
+	return false
+}
+
+// A comparable is an operand of a comparison. Its value is nil if it has none,
+// such as when a query selects no nodes.
+type comparable interface {
+	value(root, current *yaml.Node) *yaml.Node
+}
+
+// literal is a string, number, boolean, or null value.
+type literal struct {
+	node *yaml.Node
+}
+
+func (l literal) value(root, current *yaml.Node) *yaml.Node {
+	return l.node
+}
+
+// singularQuery is the value of the node selected by a query that selects at most one node.
+type singularQuery struct {
+	path *Path
+}
+
+func (q singularQuery) value(root, current *yaml.Node) *yaml.Node {
+	results := q.path.evaluate(root, current)
+	if len(results) != 1 {
+		return nil
+	}
+	return results[0].Node
+}
+
+// Types of JSON values.
+const (
+	nullType = iota
+	booleanType
+	numberType
+	stringType
+	arrayType
+	objectType
+)
+
+// typeOf returns the JSON type of a node. Scalars with tags
+// other than those of the YAML core schema are strings.
+func typeOf(node *yaml.Node) int {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return objectType
+	case yaml.SequenceNode:
+		return arrayType
+	}
+	switch node.ShortTag() {
+	case "!!null":
+		return nullType
+	case "!!bool":
+		return booleanType
+	case "!!int", "!!float":
+		return numberType
+	}
+	return stringType
+}
+
+func numberValue(node *yaml.Node) float64 {
+	var f float64
+	node.Decode(&f)
+	return f
+}
+
+func booleanValue(node *yaml.Node) bool {
+	var b bool
+	node.Decode(&b)
+	return b
+}
+
+// equal returns true if two values are equal. Missing values are equal only to each other.
+func equal(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	a, b = resolve(a), resolve(b)
+	t := typeOf(a)
+	if t != typeOf(b) {
+		return false
+	}
+	switch t {
+	case nullType:
+		return true
+	case booleanType:
+		return booleanValue(a) == booleanValue(b)
+	case numberType:
+		return numberValue(a) == numberValue(b)
+	case stringType:
+		return a.Value == b.Value
+	case arrayType:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := range a.Content {
+			if !equal(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := 0; i < len(a.Content)-1; i += 2 {
+			if !equal(a.Content[i+1], memberValue(b, a.Content[i].Value)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// less returns true if two values are numbers or strings and the first is less than the second.
+func less(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	a, b = resolve(a), resolve(b)
+	switch t := typeOf(a); {
+	case t != typeOf(b):
+		return false
+	case t == numberType:
+		return numberValue(a) < numberValue(b)
+	case t == stringType:
+		return a.Value < b.Value
+	}
+	return false
+}
+
+// memberValue returns the value of an object's member with the specified name or nil if there is none.
+func memberValue(node *yaml.Node, name string) *yaml.Node {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonpath
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Types of the parameters and results of functions.
+const (
+	valueType = iota
+	logicalType
+	nodesType
+)
+
+// A function is a function extension that can be called in filter expressions.
+type function struct {
+	parameters []int
+	result     int
+	call       func(arguments []interface{}) interface{}
+}
+
+// functions holds the function extensions defined by RFC 9535.
+var functions = map[string]*function{
+	"length": {
+		parameters: []int{valueType},
+		result:     valueType,
+		call: func(arguments []interface{}) interface{} {
+			node, _ := arguments[0].(*yaml.Node)
+			if node == nil {
+				return (*yaml.Node)(nil)
+			}
+			switch typeOf(node) {
+			case stringType:
+				return numberNode(utf8.RuneCountInString(node.Value))
+			case arrayType:
+				return numberNode(len(node.Content))
+			case objectType:
+				return numberNode(len(node.Content) / 2)
+			}
+			return (*yaml.Node)(nil)
+		},
+	},
+	"count": {
+		parameters: []int{nodesType},
+		result:     valueType,
+		call: func(arguments []interface{}) interface{} {
+			return numberNode(len(arguments[0].([]*Result)))
+		},
+	},
+	"match": {
+		parameters: []int{valueType, valueType},
+		result:     logicalType,
+		call: func(arguments []interface{}) interface{} {
+			return matches(arguments, true)
+		},
+	},
+	"search": {
+		parameters: []int{valueType, valueType},
+		result:     logicalType,
+		call: func(arguments []interface{}) interface{} {
+			return matches(arguments, false)
+		},
+	},
+	"value": {
+		parameters: []int{nodesType},
+		result:     valueType,
+		call: func(arguments []interface{}) interface{} {
+			if results := arguments[0].([]*Result); len(results) == 1 {
+				return results[0].Node
+			}
+			return (*yaml.Node)(nil)
+		},
+	},
+}
+
+// functionExpression is a call of a function extension.
+type functionExpression struct {
+	name      string
+	function  *function
+	arguments []interface{}
+}
+
+// call evaluates the arguments of a function expression and calls its function.
+func (e functionExpression) call(root, current *yaml.Node) interface{} {
+	arguments := make([]interface{}, len(e.arguments))
+	for i, argument := range e.arguments {
+		switch e.function.parameters[i] {
+		case valueType:
+			arguments[i] = argument.(comparable).value(root, current)
+		case logicalType:
+			arguments[i] = argument.(logicalExpression).test(root, current)
+		case nodesType:
+			arguments[i] = argument.(nodeList).nodes(root, current)
+		}
+	}
+	return e.function.call(arguments)
+}
+
+// value returns the result of a function with a ValueType result.
+func (e functionExpression) value(root, current *yaml.Node) *yaml.Node {
+	return e.call(root, current).(*yaml.Node)
+}
+
+// test returns the result of a function with a LogicalType result or, for
+// functions with NodesType results, whether any nodes were returned.
+func (e functionExpression) test(root, current *yaml.Node) bool {
+	switch result := e.call(root, current).(type) {
+	case bool:
+		return result
+	case []*Result:
+		return len(result) > 0
+	}
+	return false
+}
+
+// nodes returns the result of a function with a NodesType result.
+func (e functionExpression) nodes(root, current *yaml.Node) []*Result {
+	return e.call(root, current).([]*Result)
+}
+
+// A nodeList is an argument of a function parameter with type NodesType.
+type nodeList interface {
+	nodes(root, current *yaml.Node) []*Result
+}
+
+// queryArgument is a query that is the argument of a NodesType parameter.
+type queryArgument struct {
+	path *Path
+}
+
+func (a queryArgument) nodes(root, current *yaml.Node) []*Result {
+	return a.path.evaluate(root, current)
+}
+
+func numberNode(n int) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(n)}
+}
+
+var (
+	regexpCache      = make(map[string]*regexp.Regexp)
+	regexpCacheMutex sync.Mutex
+)
+
+// matches implements match, which is true if a string matches an I-Regexp (RFC 9485),
+// and search, which is true if a string contains a match. It is false if either
+// argument isn't a string or if the regular expression is invalid.
+func matches(arguments []interface{}, full bool) bool {
+	s, _ := arguments[0].(*yaml.Node)
+	pattern, _ := arguments[1].(*yaml.Node)
+	if s == nil || pattern == nil || typeOf(s) != stringType || typeOf(pattern) != stringType {
+		return false
+	}
+	expression := translateRegexp(pattern.Value)
+	if full {
+		expression = `^(?:` + expression + `)$`
+	}
+	regexpCacheMutex.Lock()
+	r, ok := regexpCache[expression]
+	if !ok {
+		r, _ = regexp.Compile(expression)
+		regexpCache[expression] = r
+	}
+	regexpCacheMutex.Unlock()
+	return r != nil && r.MatchString(s.Value)
+}
+
+// translateRegexp converts an I-Regexp to the syntax of the regexp package, in which
+// "." matches carriage returns. I-Regexps are otherwise a subset of that syntax.
+func translateRegexp(pattern string) string {
+	var b strings.Builder
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteByte(c)
+			i++
+			b.WriteByte(pattern[i])
+		case c == '[':
+			inClass = true
+			b.WriteByte(c)
+		case c == ']':
+			inClass = false
+			b.WriteByte(c)
+		case c == '.' && !inClass:
+			b.WriteString(`[^\n\r]`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
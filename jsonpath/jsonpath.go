@@ -0,0 +1,345 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonpath evaluates JSONPath queries (RFC 9535) against the yaml.Node
+// trees that gnostic reads from JSON and YAML files, so that parts of API
+// descriptions can be selected, inspected, and modified.
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// A Path is a parsed JSONPath query.
+type Path struct {
+	text string
+	// relative is true for queries that begin with "@" and that are
+	// evaluated against the current node of a filter.
+	relative bool
+	segments []*segment
+}
+
+// A segment selects children or descendants of the nodes selected by the
+// preceding segments.
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+// A Result is a node selected by a query.
+type Result struct {
+	// Node is the selected node.
+	Node *yaml.Node
+	// Parent is the mapping or sequence node that contains the selected
+	// node, or nil if the selected node is the root of the queried document.
+	Parent *yaml.Node
+	// Keys is the location of the selected node as a list of member names
+	// and array indices, like the Keys of a plugins.Message.
+	Keys []string
+	// Line and Column are the position of the selected node in the source
+	// of the queried document, or of its name if it is a member of an object.
+	// They are zero for documents that weren't read from a source, such as
+	// those produced by ToRawInfo.
+	Line   int
+	Column int
+
+	// path is the normalized path of the selected node.
+	path string
+}
+
+// NormalizedPath returns the normalized path (RFC 9535, section 2.7) that
+// identifies a selected node, such as "$['paths']['/pets']['get']".
+func (r *Result) NormalizedPath() string {
+	return r.path
+}
+
+// String returns the text of a query.
+func (p *Path) String() string {
+	return p.text
+}
+
+// Query parses a JSONPath query and evaluates it against a node.
+func Query(text string, node *yaml.Node) ([]*Result, error) {
+	path, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return path.Query(node), nil
+}
+
+// QueryDocument parses a JSONPath query and evaluates it against a compiled
+// document, such as an *openapi_v3.Document, or any other generated type.
+func QueryDocument(text string, document interface{ ToRawInfo() *yaml.Node }) ([]*Result, error) {
+	return Query(text, document.ToRawInfo())
+}
+
+// Query returns the nodes selected by a query in document order. If the
+// node is a yaml.DocumentNode, the query is evaluated against its content.
+func (p *Path) Query(node *yaml.Node) []*Result {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	return p.evaluate(node, node)
+}
+
+// evaluate applies a query to the root of a document or, for relative
+// queries, to the current node of a filter.
+func (p *Path) evaluate(root, current *yaml.Node) []*Result {
+	start := root
+	if p.relative {
+		start = current
+	}
+	if start == nil {
+		return nil
+	}
+	results := []*Result{{Node: resolve(start), Keys: []string{}, Line: start.Line, Column: start.Column, path: "$"}}
+	for _, s := range p.segments {
+		next := make([]*Result, 0)
+		for _, r := range results {
+			if s.descendant {
+				forEachDescendant(r, func(d *Result) {
+					next = s.apply(root, d, next)
+				})
+			} else {
+				next = s.apply(root, r, next)
+			}
+		}
+		results = next
+	}
+	return results
+}
+
+// singular returns true if a query selects at most one node.
+func (p *Path) singular() bool {
+	for _, s := range p.segments {
+		if s.descendant || len(s.selectors) != 1 {
+			return false
+		}
+		switch s.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// apply appends the results of applying a segment's selectors to a node.
+func (s *segment) apply(root *yaml.Node, r *Result, results []*Result) []*Result {
+	for _, sel := range s.selectors {
+		results = sel.apply(root, r, results)
+	}
+	return results
+}
+
+// A selector selects children of a node.
+type selector interface {
+	apply(root *yaml.Node, r *Result, results []*Result) []*Result
+}
+
+// nameSelector selects the member of an object with the specified name.
+type nameSelector string
+
+func (s nameSelector) apply(root *yaml.Node, r *Result, results []*Result) []*Result {
+	if r.Node.Kind != yaml.MappingNode {
+		return results
+	}
+	for i := 0; i < len(r.Node.Content)-1; i += 2 {
+		if r.Node.Content[i].Value == string(s) {
+			return append(results, member(r, r.Node.Content[i], r.Node.Content[i+1]))
+		}
+	}
+	return results
+}
+
+// wildcardSelector selects all members of an object or elements of an array.
+type wildcardSelector struct{}
+
+func (wildcardSelector) apply(root *yaml.Node, r *Result, results []*Result) []*Result {
+	forEachChild(r, func(c *Result) {
+		results = append(results, c)
+	})
+	return results
+}
+
+// indexSelector selects the element of an array at the specified index.
+// Negative indices count back from the end of the array.
+type indexSelector int
+
+func (s indexSelector) apply(root *yaml.Node, r *Result, results []*Result) []*Result {
+	if r.Node.Kind != yaml.SequenceNode {
+		return results
+	}
+	i := int(s)
+	if i < 0 {
+		i += len(r.Node.Content)
+	}
+	if i < 0 || i >= len(r.Node.Content) {
+		return results
+	}
+	return append(results, element(r, i))
+}
+
+// sliceSelector selects a range of the elements of an array.
+type sliceSelector struct {
+	start *int
+	end   *int
+	step  int
+}
+
+func (s sliceSelector) apply(root *yaml.Node, r *Result, results []*Result) []*Result {
+	if r.Node.Kind != yaml.SequenceNode || s.step == 0 {
+		return results
+	}
+	n := len(r.Node.Content)
+	normalize := func(i *int, defaultValue int) int {
+		if i == nil {
+			return defaultValue
+		}
+		if *i < 0 {
+			return n + *i
+		}
+		return *i
+	}
+	bound := func(i, lower, upper int) int {
+		if i < lower {
+			return lower
+		}
+		if i > upper {
+			return upper
+		}
+		return i
+	}
+	if s.step > 0 {
+		lower := bound(normalize(s.start, 0), 0, n)
+		upper := bound(normalize(s.end, n), 0, n)
+		for i := lower; i < upper; i += s.step {
+			results = append(results, element(r, i))
+		}
+	} else {
+		upper := bound(normalize(s.start, n-1), -1, n-1)
+		lower := bound(normalize(s.end, -n-1), -1, n-1)
+		for i := upper; lower < i; i += s.step {
+			results = append(results, element(r, i))
+		}
+	}
+	return results
+}
+
+// filterSelector selects the members of an object or elements of an array
+// for which a logical expression is true.
+type filterSelector struct {
+	expression logicalExpression
+}
+
+func (s filterSelector) apply(root *yaml.Node, r *Result, results []*Result) []*Result {
+	forEachChild(r, func(c *Result) {
+		if s.expression.test(root, c.Node) {
+			results = append(results, c)
+		}
+	})
+	return results
+}
+
+// member returns a result for a member of a selected object.
+func member(parent *Result, key, value *yaml.Node) *Result {
+	return &Result{
+		Node:   resolve(value),
+		Parent: parent.Node,
+		Keys:   compiler.KeysWith(parent.Keys, key.Value),
+		Line:   key.Line,
+		Column: key.Column,
+		path:   parent.path + "[" + quote(key.Value) + "]",
+	}
+}
+
+// element returns a result for an element of a selected array.
+func element(parent *Result, i int) *Result {
+	node := parent.Node.Content[i]
+	return &Result{
+		Node:   resolve(node),
+		Parent: parent.Node,
+		Keys:   compiler.KeysWith(parent.Keys, strconv.Itoa(i)),
+		Line:   node.Line,
+		Column: node.Column,
+		path:   parent.path + "[" + strconv.Itoa(i) + "]",
+	}
+}
+
+// forEachChild calls a function for each member of an object or element of an array.
+func forEachChild(r *Result, f func(*Result)) {
+	switch r.Node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(r.Node.Content)-1; i += 2 {
+			f(member(r, r.Node.Content[i], r.Node.Content[i+1]))
+		}
+	case yaml.SequenceNode:
+		for i := range r.Node.Content {
+			f(element(r, i))
+		}
+	}
+}
+
+// quote returns a member name as a string literal in the form used by normalized paths.
+func quote(name string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range name {
+		switch r {
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\'', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// forEachDescendant calls a function for a node and each of its descendants in document order.
+func forEachDescendant(r *Result, f func(*Result)) {
+	f(r)
+	forEachChild(r, func(c *Result) {
+		forEachDescendant(c, f)
+	})
+}
+
+// resolve returns the node that an alias refers to or the node itself if it isn't an alias.
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
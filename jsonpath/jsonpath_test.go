@@ -0,0 +1,233 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonpath
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// store is the example document of RFC 9535.
+const store = `{ "store": {
+    "book": [
+      { "category": "reference",
+        "author": "Nigel Rees",
+        "title": "Sayings of the Century",
+        "price": 8.95
+      },
+      { "category": "fiction",
+        "author": "Evelyn Waugh",
+        "title": "Sword of Honour",
+        "price": 12.99
+      },
+      { "category": "fiction",
+        "author": "Herman Melville",
+        "title": "Moby Dick",
+        "isbn": "0-553-21311-3",
+        "price": 8.99
+      },
+      { "category": "fiction",
+        "author": "J. R. R. Tolkien",
+        "title": "The Lord of the Rings",
+        "isbn": "0-395-19395-8",
+        "price": 22.99
+      }
+    ],
+    "bicycle": {
+      "color": "red",
+      "price": 399
+    }
+  }
+}`
+
+// values is a document for testing selectors and comparisons.
+const values = `
+o: {"j j": {"k.k": 3}, "'": 1, "a": 5, "b": 4, "c": 3}
+a: [0, 1, 2, 3, 4, 5, 6, 7, 8, 9]
+m: [{a: 3}, {a: "3"}, {a: 3.0}, {a: [3]}, {a: {b: 3}}, {a: null}, {a: true}, {b: 1}]
+`
+
+func parse(t *testing.T, text string) *yaml.Node {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &node); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return &node
+}
+
+// locations describes the results of a query by their keys.
+func locations(results []*Result) string {
+	paths := make([]string, 0, len(results))
+	for _, r := range results {
+		paths = append(paths, "/"+strings.Join(r.Keys, "/"))
+	}
+	return strings.Join(paths, " ")
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		document string
+		query    string
+		expected string
+	}{
+		{store, `$.store.book[*].author`, "/store/book/0/author /store/book/1/author /store/book/2/author /store/book/3/author"},
+		{store, `$..author`, "/store/book/0/author /store/book/1/author /store/book/2/author /store/book/3/author"},
+		{store, `$.store.*`, "/store/book /store/bicycle"},
+		{store, `$.store..price`, "/store/book/0/price /store/book/1/price /store/book/2/price /store/book/3/price /store/bicycle/price"},
+		{store, `$..book[2]`, "/store/book/2"},
+		{store, `$..book[-1]`, "/store/book/3"},
+		{store, `$..book[0,1]`, "/store/book/0 /store/book/1"},
+		{store, `$..book[:2]`, "/store/book/0 /store/book/1"},
+		{store, `$..book[?@.isbn]`, "/store/book/2 /store/book/3"},
+		{store, `$..book[?@.price<10]`, "/store/book/0 /store/book/2"},
+		{store, `$..book[?(@.price < 10 && @.category == 'fiction')].title`, "/store/book/2/title"},
+		{store, `$..book[?!@.isbn || @.price > 20]`, "/store/book/0 /store/book/1 /store/book/3"},
+		{store, `$..book[?@.price > $.store.bicycle.price]`, ""},
+		{store, `$`, "/"},
+		{values, `$.o['j j']['k.k']`, "/o/j j/k.k"},
+		{values, `$.o["j j"]["k.k"]`, "/o/j j/k.k"},
+		{values, `$["'"]`, ""},
+		{values, `$.o['\'']`, "/o/'"},
+		{values, `$.o[ 'a' , 'c' ]`, "/o/a /o/c"},
+		{values, `$.a[1:3]`, "/a/1 /a/2"},
+		{values, `$.a[5:]`, "/a/5 /a/6 /a/7 /a/8 /a/9"},
+		{values, `$.a[1:5:2]`, "/a/1 /a/3"},
+		{values, `$.a[5:1:-2]`, "/a/5 /a/3"},
+		{values, `$.a[::-4]`, "/a/9 /a/5 /a/1"},
+		{values, `$.a[-2:]`, "/a/8 /a/9"},
+		{values, `$.a[::0]`, ""},
+		{values, `$.a[10]`, ""},
+		{values, `$.a[?@ >= 8]`, "/a/8 /a/9"},
+		{values, `$.o[?@ < 4]`, "/o/' /o/c"},
+		{values, `$.m[?@.a == 3]`, "/m/0 /m/2"},
+		{values, `$.m[?@.a == '3']`, "/m/1"},
+		{values, `$.m[?@.a == $.m[3].a]`, "/m/3"},
+		{values, `$.m[?@.a == $.m[4].a]`, "/m/4"},
+		{values, `$.m[?@.a == null]`, "/m/5"},
+		{values, `$.m[?@.a == true]`, "/m/6"},
+		{values, `$.m[?@.a == @.c]`, "/m/7"},
+		{values, `$.m[?@.a != 3]`, "/m/1 /m/3 /m/4 /m/5 /m/6 /m/7"},
+		{values, `$.m[?@.a <= 3]`, "/m/0 /m/2"},
+		{values, `$.m[?@.a]`, "/m/0 /m/1 /m/2 /m/3 /m/4 /m/5 /m/6"},
+		{values, `$.m[?!(@.a || @.b)]`, ""},
+		{values, `$..[?@.b == 3]`, "/m/4/a"},
+		{store, `$..book[?length(@.title) > 15].title`, "/store/book/0/title /store/book/3/title"},
+		{store, `$..book[?match(@.author, 'H.*')]`, "/store/book/2"},
+		{store, `$..book[?search(@.author, 'R\\.')]`, "/store/book/3"},
+		{store, `$..book[?!search(@.title, '[Tt]he')]`, "/store/book/1 /store/book/2"},
+		{store, `$.store[?count(@.*) == 2]`, "/store/bicycle"},
+		{store, `$.store[?length(@) == 4]`, "/store/book"},
+		{store, `$..book[?value(@..isbn) == '0-553-21311-3'].title`, "/store/book/2/title"},
+		{store, `$..book[?match(@.price, '8.*')]`, ""},
+		{store, `$..book[?match(@.title, '(')]`, ""},
+		{values, `$.m[?length(@.a) == 1]`, "/m/1 /m/3 /m/4"},
+		{values, `$.m.*.a`, "/m/0/a /m/1/a /m/2/a /m/3/a /m/4/a /m/5/a /m/6/a"},
+	}
+	for _, test := range tests {
+		path, err := Parse(test.query)
+		if err != nil {
+			t.Errorf("%+v", err)
+			continue
+		}
+		results := path.Query(parse(t, test.document))
+		if s := locations(results); s != test.expected {
+			t.Errorf("%s: unexpected results %q, expected %q", test.query, s, test.expected)
+		}
+	}
+}
+
+func TestResultLocations(t *testing.T) {
+	document := parse(t, "paths:\n  /pets:\n    get:\n      tags: [pets, \"a'b\\nc\"]\n")
+	results, err := Query(`$..tags[*]`, document)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []struct {
+		path         string
+		line, column int
+	}{
+		{`$['paths']['/pets']['get']['tags'][0]`, 4, 14},
+		{`$['paths']['/pets']['get']['tags'][1]`, 4, 20},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("unexpected results: %s", locations(results))
+	}
+	for i, e := range expected {
+		r := results[i]
+		if r.NormalizedPath() != e.path || r.Line != e.line || r.Column != e.column {
+			t.Errorf("unexpected result %s at %d:%d", r.NormalizedPath(), r.Line, r.Column)
+		}
+	}
+	results, err = Query(`$.paths.*`, document)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(results) != 1 || results[0].Line != 2 || results[0].Column != 3 {
+		t.Errorf("unexpected results: %s", locations(results))
+	}
+	if s := quote("a'b\\c\n\x01"); s != `'a\'b\\c\n\u0001'` {
+		t.Errorf("unexpected quoted name %s", s)
+	}
+}
+
+func TestQueryAliases(t *testing.T) {
+	document := parse(t, "a: &x {b: 1}\nc: *x\n")
+	results, err := Query(`$.c.b`, document)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(results) != 1 || results[0].Node.Value != "1" {
+		t.Errorf("unexpected results: %s", locations(results))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{
+		``, `a`, `$.`, `$a`, `$[`, `$[]`, `$[1`, `$.[1]`, `$..`, `$[01]`, `$[-0]`,
+		`$[9007199254740992]`, `$['a`, `$['\"']`, `$["\ud800"]`, "$['\x01']",
+		`$[?@.a ==]`, `$[?@.* == 1]`, `$[?@..a == 1]`, `$[?1]`, `$[?!@.a == 1]`,
+		`$[?(@.a]`, `$[?@.a = 1]`, `$[?@.a == [3]]`,
+		`$[?length(@)]`, `$[?value(1) == 1]`, `$[?length(@.*) == 1]`, `$[?match(@.a)]`,
+		`$[?match(@.a, 'x', 'y')]`, `$[?match(@.a, 'x') == true]`, `$[?unknown(@)]`,
+		`$[?length (@) == 1]`, `$[?count(1) == 1]`, `$ .a b`,
+	} {
+		if _, err := Parse(query); err == nil {
+			t.Errorf("expected an error for %q", query)
+		}
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	tests := map[string]string{
+		`$['A\n\\']`:        "A\n\\",
+		`$["\uD83D\uDE00"]`: "\U0001F600",
+		`$['"']`:            `"`,
+		`$["'"]`:            `'`,
+		`$["\/\b\f\r\t"]`:   "/\b\f\r\t",
+		`$['é']`:            "é",
+	}
+	for query, expected := range tests {
+		path, err := Parse(query)
+		if err != nil {
+			t.Errorf("%+v", err)
+			continue
+		}
+		if name := string(path.segments[0].selectors[0].(nameSelector)); name != expected {
+			t.Errorf("%s: unexpected name %q", query, name)
+		}
+	}
+}
@@ -0,0 +1,594 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonpath
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Integers in queries must be within the range of exactly representable I-JSON numbers.
+const maxInt = 1<<53 - 1
+
+// parser holds the state of the parsing of a query.
+type parser struct {
+	text string
+	pos  int
+}
+
+// Parse parses a JSONPath query.
+func Parse(text string) (*Path, error) {
+	p := &parser{text: text}
+	path, err := p.parseQuery(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.text) {
+		return nil, p.errorf("unexpected %q", p.text[p.pos:])
+	}
+	return path, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid JSONPath %q at offset %d: %s", p.text, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.text) {
+		return p.text[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(p.text[p.pos:], s)
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.text) && strings.IndexByte(" \t\n\r", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// parseQuery parses a query that begins with "$" or, if relative queries
+// are allowed, with "@".
+func (p *parser) parseQuery(allowRelative bool) (*Path, error) {
+	start := p.pos
+	path := &Path{}
+	switch {
+	case p.peek() == '$':
+	case p.peek() == '@' && allowRelative:
+		path.relative = true
+	default:
+		return nil, p.errorf("queries must begin with $")
+	}
+	p.pos++
+	for {
+		save := p.pos
+		p.skipSpace()
+		s := &segment{}
+		var err error
+		switch {
+		case p.hasPrefix(".."):
+			p.pos += 2
+			s.descendant = true
+			s.selectors, err = p.parseSegmentSelectors()
+		case p.peek() == '.':
+			p.pos++
+			if p.peek() == '[' {
+				return nil, p.errorf("unexpected [")
+			}
+			s.selectors, err = p.parseSegmentSelectors()
+		case p.peek() == '[':
+			s.selectors, err = p.parseSegmentSelectors()
+		default:
+			p.pos = save
+			path.text = p.text[start:p.pos]
+			return path, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		path.segments = append(path.segments, s)
+	}
+}
+
+// parseSegmentSelectors parses the selectors of a segment, which may be
+// a wildcard, a member name, or a bracketed selection.
+func (p *parser) parseSegmentSelectors() ([]selector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return []selector{wildcardSelector{}}, nil
+	case c == '[':
+		return p.parseBracketedSelection()
+	default:
+		name := p.parseMemberName()
+		if name == "" {
+			return nil, p.errorf("expected a member name")
+		}
+		return []selector{nameSelector(name)}, nil
+	}
+}
+
+// parseMemberName parses the name in a shorthand such as ".name",
+// returning the empty string if there isn't one.
+func (p *parser) parseMemberName() string {
+	start := p.pos
+	for p.pos < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		if !(r == '_' || r >= 0x80 || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' ||
+			p.pos > start && '0' <= r && r <= '9') {
+			break
+		}
+		p.pos += size
+	}
+	return p.text[start:p.pos]
+}
+
+// parseBracketedSelection parses a comma-separated list of selectors in brackets.
+func (p *parser) parseBracketedSelection() ([]selector, error) {
+	p.pos++
+	selectors := make([]selector, 0)
+	for {
+		p.skipSpace()
+		s, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return selectors, nil
+		default:
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+// parseSelector parses a name, wildcard, index, slice, or filter selector.
+func (p *parser) parseSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector(name), nil
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		expression, err := p.parseLogicalOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expression: expression}, nil
+	}
+	start, err := p.parseOptionalInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() != ':' {
+		if start == nil {
+			return nil, p.errorf("expected a selector")
+		}
+		return indexSelector(*start), nil
+	}
+	p.pos++
+	p.skipSpace()
+	s := sliceSelector{start: start, step: 1}
+	if s.end, err = p.parseOptionalInt(); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() == ':' {
+		p.pos++
+		p.skipSpace()
+		step, err := p.parseOptionalInt()
+		if err != nil {
+			return nil, err
+		}
+		if step != nil {
+			s.step = *step
+		}
+	}
+	return s, nil
+}
+
+var intPattern = regexp.MustCompile(`^(0|-?[1-9][0-9]*)`)
+
+// parseOptionalInt parses an integer, returning nil if there isn't one.
+func (p *parser) parseOptionalInt() (*int, error) {
+	if p.hasPrefix("-0") {
+		return nil, p.errorf("invalid integer")
+	}
+	m := intPattern.FindString(p.text[p.pos:])
+	if m == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(m, 10, 64)
+	if err != nil || i > maxInt || i < -maxInt {
+		return nil, p.errorf("integer %s is out of range", m)
+	}
+	p.pos += len(m)
+	if c := p.peek(); '0' <= c && c <= '9' {
+		return nil, p.errorf("invalid integer")
+	}
+	n := int(i)
+	return &n, nil
+}
+
+// parseString parses a single- or double-quoted string literal.
+func (p *parser) parseString() (string, error) {
+	quote := p.text[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		switch {
+		case r == rune(quote):
+			p.pos++
+			return b.String(), nil
+		case r < 0x20:
+			return "", p.errorf("control characters must be escaped")
+		case r != '\\':
+			b.WriteRune(r)
+			p.pos += size
+			continue
+		}
+		p.pos++
+		escaped := p.peek()
+		p.pos++
+		switch escaped {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '/', '\\', quote:
+			b.WriteByte(escaped)
+		case 'u':
+			r, err := p.parseUnicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		default:
+			p.pos--
+			return "", p.errorf("invalid escape sequence")
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseUnicodeEscape parses the hexadecimal digits of a \u escape sequence,
+// including the second half of a surrogate pair.
+func (p *parser) parseUnicodeEscape() (rune, error) {
+	hex := func() (rune, error) {
+		if p.pos+4 > len(p.text) {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		v, err := strconv.ParseUint(p.text[p.pos:p.pos+4], 16, 16)
+		if err != nil {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		p.pos += 4
+		return rune(v), nil
+	}
+	r, err := hex()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case 0xD800 <= r && r < 0xDC00:
+		if !p.hasPrefix(`\u`) {
+			return 0, p.errorf("unpaired surrogate")
+		}
+		p.pos += 2
+		low, err := hex()
+		if err != nil {
+			return 0, err
+		}
+		if low < 0xDC00 || low > 0xDFFF {
+			return 0, p.errorf("unpaired surrogate")
+		}
+		return utf16.DecodeRune(r, low), nil
+	case 0xDC00 <= r && r <= 0xDFFF:
+		return 0, p.errorf("unpaired surrogate")
+	}
+	return r, nil
+}
+
+// parseLogicalOr parses expressions joined by "||".
+func (p *parser) parseLogicalOr() (logicalExpression, error) {
+	operands := orExpression{}
+	for {
+		operand, err := p.parseLogicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		p.skipSpace()
+		if !p.hasPrefix("||") {
+			break
+		}
+		p.pos += 2
+		p.skipSpace()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+// parseLogicalAnd parses expressions joined by "&&".
+func (p *parser) parseLogicalAnd() (logicalExpression, error) {
+	operands := andExpression{}
+	for {
+		operand, err := p.parseBasicExpression()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		p.skipSpace()
+		if !p.hasPrefix("&&") {
+			break
+		}
+		p.pos += 2
+		p.skipSpace()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+// parseBasicExpression parses a parenthesized expression, a comparison, or a test expression,
+// any of which but comparisons may be negated with "!".
+func (p *parser) parseBasicExpression() (logicalExpression, error) {
+	if p.peek() == '!' {
+		p.pos++
+		p.skipSpace()
+		var operand logicalExpression
+		var err error
+		if p.peek() == '(' {
+			operand, err = p.parseParenthesizedExpression()
+		} else {
+			operand, err = p.parseTestExpression()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{operand: operand}, nil
+	}
+	if p.peek() == '(' {
+		return p.parseParenthesizedExpression()
+	}
+	if p.peek() == '@' || p.peek() == '$' || p.atFunction() {
+		save := p.pos
+		operand, err := p.parseTestExpression()
+		p.skipSpace()
+		if p.parseComparisonOperator() == "" {
+			return operand, err
+		}
+		p.pos = save
+	}
+	return p.parseComparison()
+}
+
+// parseParenthesizedExpression parses a logical expression in parentheses.
+func (p *parser) parseParenthesizedExpression() (logicalExpression, error) {
+	p.pos++
+	p.skipSpace()
+	expression, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() != ')' {
+		return nil, p.errorf("expected )")
+	}
+	p.pos++
+	return expression, nil
+}
+
+// parseTestExpression parses a query that tests for the existence of nodes
+// or a call of a function that returns a LogicalType or NodesType result.
+func (p *parser) parseTestExpression() (logicalExpression, error) {
+	if p.atFunction() {
+		e, err := p.parseFunctionExpression()
+		if err != nil {
+			return nil, err
+		}
+		if e.function.result == valueType {
+			return nil, p.errorf("the result of %s() must be compared", e.name)
+		}
+		return e, nil
+	}
+	if p.peek() != '@' && p.peek() != '$' {
+		return nil, p.errorf("expected a query")
+	}
+	path, err := p.parseQuery(true)
+	if err != nil {
+		return nil, err
+	}
+	return existenceExpression{path: path}, nil
+}
+
+var functionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*\(`)
+
+// atFunction returns true if a function expression begins at the current position.
+func (p *parser) atFunction() bool {
+	return functionNamePattern.MatchString(p.text[p.pos:])
+}
+
+// parseFunctionExpression parses a call of a function extension and checks
+// that its arguments are compatible with the types of its parameters.
+func (p *parser) parseFunctionExpression() (functionExpression, error) {
+	name := strings.TrimSuffix(functionNamePattern.FindString(p.text[p.pos:]), "(")
+	e := functionExpression{name: name, function: functions[name]}
+	if e.function == nil {
+		return e, p.errorf("unknown function %s()", name)
+	}
+	p.pos += len(name) + 1
+	for i, parameter := range e.function.parameters {
+		p.skipSpace()
+		if i > 0 {
+			if p.peek() != ',' {
+				return e, p.errorf("%s() requires %d arguments", name, len(e.function.parameters))
+			}
+			p.pos++
+			p.skipSpace()
+		}
+		argument, err := p.parseArgument(parameter)
+		if err != nil {
+			return e, err
+		}
+		e.arguments = append(e.arguments, argument)
+	}
+	p.skipSpace()
+	if p.peek() != ')' {
+		return e, p.errorf("%s() requires %d arguments", name, len(e.function.parameters))
+	}
+	p.pos++
+	return e, nil
+}
+
+// parseArgument parses an argument of a function parameter of the specified type.
+func (p *parser) parseArgument(parameter int) (interface{}, error) {
+	switch parameter {
+	case valueType:
+		return p.parseComparable()
+	case logicalType:
+		return p.parseLogicalOr()
+	}
+	if p.atFunction() {
+		e, err := p.parseFunctionExpression()
+		if err != nil {
+			return nil, err
+		}
+		if e.function.result != nodesType {
+			return nil, p.errorf("%s() doesn't return nodes", e.name)
+		}
+		return e, nil
+	}
+	if p.peek() != '@' && p.peek() != '$' {
+		return nil, p.errorf("expected a query")
+	}
+	path, err := p.parseQuery(true)
+	if err != nil {
+		return nil, err
+	}
+	return queryArgument{path: path}, nil
+}
+
+// parseComparison parses a comparison of two values.
+func (p *parser) parseComparison() (logicalExpression, error) {
+	left, err := p.parseComparable()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	operator := p.parseComparisonOperator()
+	if operator == "" {
+		return nil, p.errorf("expected a comparison operator")
+	}
+	p.pos += len(operator)
+	p.skipSpace()
+	right, err := p.parseComparable()
+	if err != nil {
+		return nil, err
+	}
+	return comparisonExpression{left: left, operator: operator, right: right}, nil
+}
+
+// parseComparisonOperator returns the comparison operator at the current position
+// without consuming it, or the empty string if there isn't one.
+func (p *parser) parseComparisonOperator() string {
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.hasPrefix(operator) {
+			return operator
+		}
+	}
+	return ""
+}
+
+var numberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?`)
+
+// parseComparable parses a literal, a singular query, or a call of a function that returns a value.
+func (p *parser) parseComparable() (comparable, error) {
+	if p.atFunction() {
+		e, err := p.parseFunctionExpression()
+		if err != nil {
+			return nil, err
+		}
+		if e.function.result != valueType {
+			return nil, p.errorf("%s() doesn't return a value", e.name)
+		}
+		return e, nil
+	}
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literal{node: &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}}, nil
+	case c == '@' || c == '$':
+		path, err := p.parseQuery(true)
+		if err != nil {
+			return nil, err
+		}
+		if !path.singular() {
+			return nil, p.errorf("%s can select more than one node and can't be compared", path.text)
+		}
+		return singularQuery{path: path}, nil
+	case c == '-' || '0' <= c && c <= '9':
+		m := numberPattern.FindString(p.text[p.pos:])
+		if m == "" {
+			return nil, p.errorf("invalid number")
+		}
+		p.pos += len(m)
+		return literal{node: &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: m}}, nil
+	}
+	for _, keyword := range []struct{ name, tag string }{
+		{"true", "!!bool"}, {"false", "!!bool"}, {"null", "!!null"},
+	} {
+		if p.hasPrefix(keyword.name) {
+			p.pos += len(keyword.name)
+			return literal{node: &yaml.Node{Kind: yaml.ScalarNode, Tag: keyword.tag, Value: keyword.name}}, nil
+		}
+	}
+	return nil, p.errorf("expected a value")
+}
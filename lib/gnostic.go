@@ -16,27 +16,38 @@ package lib
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"gopkg.in/yaml.v3"
 
+	"github.com/google/gnostic/bundler"
 	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/conversions"
 	discovery_v1 "github.com/google/gnostic/discovery"
+	"github.com/google/gnostic/filter"
 	"github.com/google/gnostic/jsonwriter"
 	openapi_v2 "github.com/google/gnostic/openapiv2"
 	openapi_v3 "github.com/google/gnostic/openapiv3"
+	openapi_v31 "github.com/google/gnostic/openapiv31"
+	"github.com/google/gnostic/overlay"
 	plugins "github.com/google/gnostic/plugins"
+	"github.com/google/gnostic/refgraph"
 	surface "github.com/google/gnostic/surface"
 )
 
@@ -64,6 +75,8 @@ const (
 	SourceFormatOpenAPI3 = 3
 	// SourceFormatDiscovery represents a Google Discovery document
 	SourceFormatDiscovery = 4
+	// SourceFormatOpenAPI31 represents an OpenAPI 3.1 document
+	SourceFormatOpenAPI31 = 5
 )
 
 // Determine the version of an OpenAPI description read from JSON or YAML.
@@ -86,6 +99,9 @@ func getOpenAPIVersionFromInfo(info *yaml.Node) int {
 	if ok && strings.HasPrefix(openapi, "3.0") {
 		return SourceFormatOpenAPI3
 	}
+	if ok && strings.HasPrefix(openapi, "3.1") {
+		return SourceFormatOpenAPI31
+	}
 
 	kind, ok := compiler.StringForScalarNode(compiler.MapValueForKey(m, "kind"))
 	if ok && kind == "discovery#restDescription" {
@@ -105,8 +121,9 @@ type pluginCall struct {
 	Invocation string
 }
 
-// Invokes a plugin.
-func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceName string, timePlugins bool, excludeSurface bool) ([]*plugins.Message, error) {
+// Invokes a plugin. The plugin is killed if ctx is canceled or if it runs for longer than timeout.
+// Its response is returned without being handled, so that responses can be handled in invocation order.
+func (p *pluginCall) perform(ctx context.Context, document proto.Message, sourceFormat int, sourceName string, sourceMap *plugins.SourceMap, session *compiler.Session, timeout time.Duration, excludeSurface bool) pluginResult {
 	if p.Name != "" {
 		request := &plugins.Request{}
 
@@ -126,7 +143,7 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 		//
 		invocationRegex := regexp.MustCompile(`^([\w-_\/\.]+=[\w-_\/\.]+(,[\w-_\/\.]+=[\w-_\/\.]+)*:)?[^,:=]+$`)
 		if !invocationRegex.Match([]byte(p.Invocation)) {
-			return nil, fmt.Errorf("Invalid invocation of %s: %s", executableName, invocation)
+			return pluginResult{err: fmt.Errorf("Invalid invocation of %s: %s", executableName, invocation)}
 		}
 
 		invocationParts := strings.Split(p.Invocation, ":")
@@ -157,12 +174,13 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 		request.OutputPath = outputLocation
 
 		request.SourceName = sourceName
+		request.SourceMap = sourceMap
 		switch sourceFormat {
 		case SourceFormatOpenAPI2:
 			request.AddModel("openapi.v2.Document", document)
 			if !excludeSurface {
 				// include experimental API surface model
-				surfaceModel, err := surface.NewModelFromOpenAPI2(document.(*openapi_v2.Document), sourceName)
+				surfaceModel, err := surface.NewModelFromOpenAPI2WithSession(document.(*openapi_v2.Document), sourceName, session)
 				if err == nil {
 					request.AddModel("surface.v1.Model", surfaceModel)
 				}
@@ -171,11 +189,13 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 			request.AddModel("openapi.v3.Document", document)
 			if !excludeSurface {
 				// include experimental API surface model
-				surfaceModel, err := surface.NewModelFromOpenAPI3(document.(*openapi_v3.Document), sourceName)
+				surfaceModel, err := surface.NewModelFromOpenAPI3WithSession(document.(*openapi_v3.Document), sourceName, session)
 				if err == nil {
 					request.AddModel("surface.v1.Model", surfaceModel)
 				}
 			}
+		case SourceFormatOpenAPI31:
+			request.AddModel("openapi.v31.Document", document)
 		case SourceFormatDiscovery:
 			request.AddModel("discovery.v1.Document", document)
 		default:
@@ -183,17 +203,47 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 
 		requestBytes, _ := proto.Marshal(request)
 
+		pluginContext := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			pluginContext, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
 		cmd := exec.Command(executableName, "-plugin")
 		cmd.Stdin = bytes.NewReader(requestBytes)
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
 		cmd.Stderr = os.Stderr
+		// Plugins run in their own process groups, and when they are canceled or time out,
+		// their groups are killed so that processes they started don't keep stdout open.
+		setProcessGroup(cmd)
 		pluginStartTime := time.Now()
-		output, err := cmd.Output()
-		pluginElapsedTime := time.Since(pluginStartTime)
-		if timePlugins {
-			fmt.Printf("> %s (%s)\n", executableName, pluginElapsedTime)
+		err := cmd.Start()
+		if err == nil {
+			done := make(chan struct{})
+			go func() {
+				select {
+				case <-pluginContext.Done():
+					killProcessGroup(cmd.Process)
+				case <-done:
+				}
+			}()
+			err = cmd.Wait()
+			close(done)
+		}
+		output := stdout.Bytes()
+		var stats *pluginStats
+		if cmd.ProcessState != nil {
+			stats = newPluginStats(time.Since(pluginStartTime), cmd.ProcessState)
 		}
 		if err != nil {
-			return nil, err
+			if ctx.Err() != nil {
+				return pluginResult{stats: stats, err: fmt.Errorf("%s was canceled: %v", executableName, ctx.Err())}
+			}
+			if pluginContext.Err() == context.DeadlineExceeded {
+				return pluginResult{stats: stats, err: fmt.Errorf("%s timed out after %s", executableName, timeout)}
+			}
+			return pluginResult{stats: stats, err: err}
 		}
 		response := &plugins.Response{}
 		err = proto.Unmarshal(output, response)
@@ -201,14 +251,19 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 			// Gnostic expects plugins to only write the
 			// response message to stdout. Be sure that
 			// any logging messages are written to stderr only.
-			return nil, errors.New("invalid plugin response (plugins must write log messages to stderr, not stdout)")
+			return pluginResult{stats: stats, err: errors.New("invalid plugin response (plugins must write log messages to stderr, not stdout)")}
 		}
-
-		err = plugins.HandleResponse(response, outputLocation)
-
-		return response.Messages, err
+		return pluginResult{response: response, outputLocation: outputLocation, stats: stats}
 	}
-	return nil, nil
+	return pluginResult{}
+}
+
+// pluginResult holds the outcome of a plugin call.
+type pluginResult struct {
+	response       *plugins.Response
+	outputLocation string
+	stats          *pluginStats
+	err            error
 }
 
 func isFile(path string) bool {
@@ -295,20 +350,42 @@ type Gnostic struct {
 	textOutputPath    string
 	yamlOutputPath    string
 	jsonOutputPath    string
+	discoOutputPath   string
+	bundleOutputPath  string
+	pruneOutputPath   string
 	errorOutputPath   string
 	messageOutputPath string
+	messagesFormat    string
+	failOn            plugins.Message_Level
+	messagesSummary   *MessagesSummary
 	resolveReferences bool
+	dereference       bool
+	validate          bool
+	convertTo         string
+	filterOptions     filter.Options
+	overlayPaths      []string
+	overlayMessages   []*plugins.Message
 	pluginCalls       []*pluginCall
+	sourceInfo        *yaml.Node
+	sourceMap         *plugins.SourceMap
 	extensionHandlers []compiler.ExtensionHandler
 	sourceFormat      int
 	timePlugins       bool
+	pluginWorkers     int
+	pluginTimeout     time.Duration
 	excludeSurface    bool
+	loader            compiler.Loader
+	httpHeader        http.Header
+	httpTimeout       time.Duration
+	session           *compiler.Session
 }
 
 // NewGnostic initializes a structure to store global application state.
 func NewGnostic(args []string) *Gnostic {
 	g := &Gnostic{args: args}
 	// Option fields initialize to their default values.
+	g.validate = true
+	g.pluginWorkers = runtime.NumCPU()
 	g.usage = `
 Usage: gnostic SOURCE [OPTIONS]
   SOURCE is the filename or URL of an API description.
@@ -317,10 +394,35 @@ Options:
   --text-out=PATH     Write a text proto to the specified location.
   --json-out=PATH     Write a json API description to the specified location.
   --yaml-out=PATH     Write a yaml API description to the specified location.
+  --discovery-out=PATH
+                      Write a Google Discovery Format description of an OpenAPI
+                      source to the specified location.
+  --bundle-out=PATH   Write a single self-contained description of a source
+                      that is split across multiple files. Externally
+                      referenced fragments are copied into its components.
+                      JSON is written for paths ending in ".json" and YAML
+                      is written for all others.
+  --prune-out=PATH    Write a copy of an OpenAPI v2 or v3 source without the
+                      schemas, parameters, responses, request bodies, headers,
+                      and examples that aren't transitively referenced from
+                      its paths. JSON is written for paths ending in ".json"
+                      and YAML is written for all others.
   --errors-out=PATH   Write compilation errors to the specified location.
   --messages-out=PATH Write messages generated by plugins to the specified
                       location. Messages from all plugin invocations are
                       written to a single common file.
+  --messages-format=FORMAT
+                      Write messages in the specified format, which may be
+                      "pb" (the default for --messages-out), "text" (the
+                      default when messages are printed), "json", "sarif",
+                      "junit", or "github" for GitHub Actions annotations.
+                      Without --messages-out, messages are printed in this
+                      format even if there are none.
+  --fail-on=LEVEL     Fail if any messages are reported with levels at or above
+                      LEVEL, which may be "warning", "error", or "fatal". Counts
+                      of messages by level and by plugin are written to stderr,
+                      and gnostic exits with status 1 if the run fails because
+                      of messages.
   --PLUGIN-out=PATH   Run the plugin named gnostic-PLUGIN and write results
                       to the specified location.
   --PLUGIN            Run the plugin named gnostic-PLUGIN but don't write any
@@ -328,18 +430,59 @@ Options:
                       PLUGIN must not match any other gnostic option.
   --x-EXTENSION       Use the extension named gnostic-x-EXTENSION
                       to process OpenAPI specification extensions.
+  --overlay=FILE      Apply an OpenAPI Overlay to the source before compiling
+                      it. May be repeated to apply several overlays in order.
+                      Actions that don't select any nodes are reported as
+                      messages.
   --resolve-refs      Explicitly resolve $ref references.
                       This could have problems with recursive definitions.
-  --time-plugins      Report plugin runtimes.
+  --dereference       Replace every $ref in OpenAPI v2 and v3 sources with
+                      the object that it refers to. Recursive references are
+                      left in place and reported as messages.
+  --convert-to=FORMAT Convert the source to another format before writing
+                      outputs and calling plugins. FORMAT may be "openapi3"
+                      for OpenAPI v2 sources or "openapi2" for OpenAPI v3
+                      sources. Conversion warnings are reported as messages.
+  --include=SELECTOR  Keep only the operations of an OpenAPI v2 or v3 source
+                      that match a selector, along with the components that
+                      they transitively reference, before writing outputs and
+                      calling plugins. SELECTOR is "tag:NAME", "path:PATTERN",
+                      "operation:OPERATIONID", or "x-EXTENSION:VALUE". In path
+                      patterns, "*" matches within a segment and "**" matches
+                      across segments. May be repeated to match more operations.
+  --exclude=SELECTOR  Remove the operations of an OpenAPI v2 or v3 source that
+                      match a selector. May be repeated.
+  --http-header=NAME:VALUE
+                      Send a header with each HTTP request for a remote
+                      source or reference. May be repeated.
+  --http-timeout=DURATION
+                      Limit the time taken by each HTTP request, e.g. "30s".
+  --no-validation     Don't check sources for semantic errors, such as duplicate
+                      operationIds, missing path parameters, undefined security
+                      schemes, and unresolved references.
+  --plugin-workers=N  Run up to N plugins at the same time. The default is the
+                      number of CPUs. Messages and output are always handled
+                      in the order that plugins are specified.
+  --plugin-timeout=DURATION
+                      Kill plugins that run for longer than DURATION, e.g. "1m".
+  --time-plugins      Report the wall time, CPU time, and peak memory use of
+                      each plugin.
   --no-surface        Exclude surface model from calls to plugins.
   --help              Print usage information and exit.
 `
 	// Initialize internal structures.
 	g.pluginCalls = make([]*pluginCall, 0)
 	g.extensionHandlers = make([]compiler.ExtensionHandler, 0)
+	g.httpHeader = make(http.Header)
 	return g
 }
 
+// SetLoader sets the Loader used to read the source and any files that it references.
+// It takes precedence over the HTTP options.
+func (g *Gnostic) SetLoader(loader compiler.Loader) {
+	g.loader = loader
+}
+
 // Usage returns usage information.
 func (g *Gnostic) Usage() string {
 	return g.usage
@@ -370,6 +513,12 @@ func (g *Gnostic) readOptions() error {
 				g.jsonOutputPath = invocation
 			case "yaml":
 				g.yamlOutputPath = invocation
+			case "discovery":
+				g.discoOutputPath = invocation
+			case "bundle":
+				g.bundleOutputPath = invocation
+			case "prune":
+				g.pruneOutputPath = invocation
 			case "errors":
 				g.errorOutputPath = invocation
 			case "messages":
@@ -382,8 +531,62 @@ func (g *Gnostic) readOptions() error {
 			extensionName := string(m[1])
 			extensionHandler := compiler.ExtensionHandler{Name: extensionPrefix + extensionName}
 			g.extensionHandlers = append(g.extensionHandlers, extensionHandler)
+		} else if strings.HasPrefix(arg, "--convert-to=") {
+			g.convertTo = strings.TrimPrefix(arg, "--convert-to=")
+			if g.convertTo != "openapi2" && g.convertTo != "openapi3" {
+				return NewUsageError(fmt.Sprintf("unsupported conversion format: %s", g.convertTo))
+			}
+		} else if strings.HasPrefix(arg, "--messages-format=") {
+			g.messagesFormat = strings.TrimPrefix(arg, "--messages-format=")
+			if !compiler.StringArrayContainsValue(plugins.MessagesFormats, g.messagesFormat) {
+				return NewUsageError(fmt.Sprintf("unsupported messages format: %s", g.messagesFormat))
+			}
+		} else if strings.HasPrefix(arg, "--fail-on=") {
+			level, ok := failOnLevels[strings.ToLower(strings.TrimPrefix(arg, "--fail-on="))]
+			if !ok {
+				return NewUsageError(fmt.Sprintf("unsupported --fail-on level: %s", strings.TrimPrefix(arg, "--fail-on=")))
+			}
+			g.failOn = level
+		} else if strings.HasPrefix(arg, "--overlay=") {
+			g.overlayPaths = append(g.overlayPaths, strings.TrimPrefix(arg, "--overlay="))
+		} else if strings.HasPrefix(arg, "--include=") {
+			if err := g.filterOptions.Include.Add(strings.TrimPrefix(arg, "--include=")); err != nil {
+				return NewUsageError(err.Error())
+			}
+		} else if strings.HasPrefix(arg, "--exclude=") {
+			if err := g.filterOptions.Exclude.Add(strings.TrimPrefix(arg, "--exclude=")); err != nil {
+				return NewUsageError(err.Error())
+			}
+		} else if strings.HasPrefix(arg, "--http-header=") {
+			header := strings.SplitN(strings.TrimPrefix(arg, "--http-header="), ":", 2)
+			if len(header) != 2 || strings.TrimSpace(header[0]) == "" {
+				return NewUsageError(fmt.Sprintf("invalid HTTP header: %s", arg))
+			}
+			g.httpHeader.Add(strings.TrimSpace(header[0]), strings.TrimSpace(header[1]))
+		} else if strings.HasPrefix(arg, "--http-timeout=") {
+			timeout, err := time.ParseDuration(strings.TrimPrefix(arg, "--http-timeout="))
+			if err != nil {
+				return NewUsageError(fmt.Sprintf("invalid HTTP timeout: %s", arg))
+			}
+			g.httpTimeout = timeout
+		} else if strings.HasPrefix(arg, "--plugin-workers=") {
+			workers, err := strconv.Atoi(strings.TrimPrefix(arg, "--plugin-workers="))
+			if err != nil || workers < 1 {
+				return NewUsageError(fmt.Sprintf("invalid plugin worker count: %s", arg))
+			}
+			g.pluginWorkers = workers
+		} else if strings.HasPrefix(arg, "--plugin-timeout=") {
+			timeout, err := time.ParseDuration(strings.TrimPrefix(arg, "--plugin-timeout="))
+			if err != nil || timeout <= 0 {
+				return NewUsageError(fmt.Sprintf("invalid plugin timeout: %s", arg))
+			}
+			g.pluginTimeout = timeout
 		} else if arg == "--resolve-refs" {
 			g.resolveReferences = true
+		} else if arg == "--dereference" {
+			g.dereference = true
+		} else if arg == "--no-validation" {
+			g.validate = false
 		} else if arg == "--time-plugins" {
 			g.timePlugins = true
 		} else if arg == "--no-surface" {
@@ -408,6 +611,9 @@ func (g *Gnostic) validateOptions() error {
 		g.textOutputPath == "" &&
 		g.yamlOutputPath == "" &&
 		g.jsonOutputPath == "" &&
+		g.discoOutputPath == "" &&
+		g.bundleOutputPath == "" &&
+		g.pruneOutputPath == "" &&
 		g.errorOutputPath == "" &&
 		g.messageOutputPath == "" &&
 		len(g.pluginCalls) == 0 {
@@ -423,17 +629,57 @@ func (g *Gnostic) validateOptions() error {
 	return nil
 }
 
+// Return the Loader specified by the caller or by the HTTP options,
+// or nil if the default Loader should be used.
+func (g *Gnostic) documentLoader() compiler.Loader {
+	if g.loader != nil {
+		return g.loader
+	}
+	if len(g.httpHeader) == 0 && g.httpTimeout == 0 {
+		return nil
+	}
+	return &compiler.URLLoader{
+		Remote: &compiler.HTTPLoader{
+			Client: &http.Client{Timeout: g.httpTimeout},
+			Header: g.httpHeader,
+		},
+		Local: compiler.FileLoader{},
+	}
+}
+
 // Generate an error message to be written to stderr or a file.
 func (g *Gnostic) errorBytes(err error) []byte {
 	return []byte("Errors reading " + g.sourceName + "\n" + err.Error())
 }
 
+// Return a context for compiling the source or resolving its references.
+func (g *Gnostic) newContext(node *yaml.Node) *compiler.Context {
+	context := compiler.NewContextWithSession("$root", node, g.session, &g.extensionHandlers)
+	context.BaseURI = g.sourceName
+	return context
+}
+
+// Get the source map of the source, which is built when it is first needed.
+// It returns nil if the source was not read from YAML or JSON.
+func (g *Gnostic) getSourceMap() *plugins.SourceMap {
+	if g.sourceMap == nil && g.sourceInfo != nil {
+		g.sourceMap = plugins.NewSourceMap(g.session, g.sourceInfo, g.sourceName)
+	}
+	return g.sourceMap
+}
+
 // Read an OpenAPI description from YAML or JSON.
 func (g *Gnostic) readOpenAPIText(bytes []byte) (message proto.Message, err error) {
-	info, err := compiler.ReadInfoFromBytes(g.sourceName, bytes)
+	info, err := g.session.ReadInfoFromBytes(g.sourceName, bytes)
 	if err != nil {
 		return nil, err
 	}
+	// Apply any overlays. The cached source is modified so that references
+	// to it are resolved in the modified document.
+	if err = g.applyOverlays(info); err != nil {
+		return nil, err
+	}
+	g.sourceInfo = info
 	// Determine the OpenAPI version.
 	g.sourceFormat = getOpenAPIVersionFromInfo(info)
 	if g.sourceFormat == SourceFormatUnknown {
@@ -442,29 +688,71 @@ func (g *Gnostic) readOpenAPIText(bytes []byte) (message proto.Message, err erro
 	// Compile to the proto model.
 	if g.sourceFormat == SourceFormatOpenAPI2 {
 		root := info.Content[0]
-		document, err := openapi_v2.NewDocument(root, compiler.NewContextWithExtensions("$root", root, nil, &g.extensionHandlers))
+		document, err := openapi_v2.NewDocument(root, g.newContext(root))
 		if err != nil {
 			return nil, err
 		}
+		if g.validate {
+			if err = openapi_v2.ValidateDocument(root, g.newContext(root)); err != nil {
+				return nil, err
+			}
+		}
 		message = document
 	} else if g.sourceFormat == SourceFormatOpenAPI3 {
 		root := info.Content[0]
-		document, err := openapi_v3.NewDocument(root, compiler.NewContextWithExtensions("$root", root, nil, &g.extensionHandlers))
+		document, err := openapi_v3.NewDocument(root, g.newContext(root))
+		if err != nil {
+			return nil, err
+		}
+		if g.validate {
+			if err = openapi_v3.ValidateDocument(root, g.newContext(root)); err != nil {
+				return nil, err
+			}
+		}
+		message = document
+	} else if g.sourceFormat == SourceFormatOpenAPI31 {
+		root := info.Content[0]
+		document, err := openapi_v31.NewDocument(root, g.newContext(root))
 		if err != nil {
 			return nil, err
 		}
 		message = document
 	} else {
 		root := info.Content[0]
-		document, err := discovery_v1.NewDocument(root, compiler.NewContextWithExtensions("$root", root, nil, &g.extensionHandlers))
+		document, err := discovery_v1.NewDocument(root, g.newContext(root))
 		if err != nil {
 			return nil, err
 		}
+		if g.validate {
+			if err = discovery_v1.ValidateDocument(root, g.newContext(root)); err != nil {
+				return nil, err
+			}
+		}
 		message = document
 	}
 	return message, err
 }
 
+// Apply the overlays specified with --overlay to the source.
+func (g *Gnostic) applyOverlays(info *yaml.Node) error {
+	for _, filename := range g.overlayPaths {
+		bytes, err := g.session.ReadBytesForFile(filename)
+		if err != nil {
+			return err
+		}
+		o, err := overlay.ParseOverlay(bytes)
+		if err != nil {
+			return fmt.Errorf("%s: %s", filename, err)
+		}
+		messages, err := o.Apply(info)
+		if err != nil {
+			return fmt.Errorf("%s: %s", filename, err)
+		}
+		g.overlayMessages = append(g.overlayMessages, messages...)
+	}
+	return nil
+}
+
 func (g *Gnostic) ReadOpenAPIText(bytes []byte) (message proto.Message, err error) {
 	return g.readOpenAPIText(bytes)
 }
@@ -478,6 +766,13 @@ func (g *Gnostic) readOpenAPIBinary(data []byte) (message proto.Message, err err
 		g.sourceFormat = SourceFormatOpenAPI3
 		return documentV3, nil
 	}
+	// if that failed, try to read an OpenAPI 3.1 document
+	documentV31 := &openapi_v31.Document{}
+	err = proto.Unmarshal(data, documentV31)
+	if err == nil && strings.HasPrefix(documentV31.Openapi, "3.1") {
+		g.sourceFormat = SourceFormatOpenAPI31
+		return documentV31, nil
+	}
 	// if that failed, try to read an OpenAPI v2 document
 	documentV2 := &openapi_v2.Document{}
 	err = proto.Unmarshal(data, documentV2)
@@ -522,6 +817,9 @@ func (g *Gnostic) writeJSONYAMLOutput(message proto.Message) {
 	} else if g.sourceFormat == SourceFormatOpenAPI3 {
 		document := message.(*openapi_v3.Document)
 		rawInfo = document.ToRawInfo()
+	} else if g.sourceFormat == SourceFormatOpenAPI31 {
+		document := message.(*openapi_v31.Document)
+		rawInfo = document.ToRawInfo()
 	} else if g.sourceFormat == SourceFormatDiscovery {
 		document := message.(*discovery_v1.Document)
 		rawInfo = document.ToRawInfo()
@@ -563,31 +861,256 @@ func (g *Gnostic) writeJSONYAMLOutput(message proto.Message) {
 	}
 }
 
+// Write a Discovery Format representation.
+// Any returned messages describe parts of the source that could not be converted.
+func (g *Gnostic) writeDiscoveryOutput(message proto.Message) ([]*plugins.Message, error) {
+	var document *openapi_v3.Document
+	var messages []*plugins.Message
+	var err error
+	switch g.sourceFormat {
+	case SourceFormatOpenAPI2:
+		document, messages, err = conversions.OpenAPIv2ToOpenAPIv3WithMessages(message.(*openapi_v2.Document))
+	case SourceFormatOpenAPI3:
+		document = message.(*openapi_v3.Document)
+	default:
+		err = errors.New("discovery output requires an OpenAPI v2 or v3 source")
+	}
+	if err != nil {
+		return nil, err
+	}
+	discoveryDocument, discoveryMessages, err := conversions.OpenAPIv3ToDiscovery(document)
+	if err != nil {
+		return nil, err
+	}
+	if g.sourceFormat == SourceFormatOpenAPI2 {
+		// These messages are about the intermediate OpenAPI v3 document,
+		// so their keys don't locate anything in the source.
+		for _, m := range discoveryMessages {
+			m.Keys = nil
+		}
+	}
+	messages = append(messages, discoveryMessages...)
+	rawInfo := &yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Content: []*yaml.Node{discoveryDocument.ToRawInfo()},
+	}
+	bytes, err := jsonwriter.Marshal(rawInfo)
+	if err != nil {
+		return nil, err
+	}
+	writeFile(g.discoOutputPath, bytes, g.sourceName, "discovery.json")
+	return messages, nil
+}
+
+// Write a bundled description that includes the contents of all referenced files.
+func (g *Gnostic) writeBundleOutput() error {
+	if g.sourceFormat != SourceFormatOpenAPI2 && g.sourceFormat != SourceFormatOpenAPI3 {
+		return errors.New("bundle output requires an OpenAPI v2 or v3 source")
+	}
+	info, err := bundler.BundleFileWithSession(g.session, g.sourceName)
+	if err != nil {
+		return err
+	}
+	var bytes []byte
+	if strings.HasSuffix(g.bundleOutputPath, ".json") {
+		bytes, err = jsonwriter.Marshal(info)
+	} else {
+		bytes, err = yaml.Marshal(info)
+	}
+	if err != nil {
+		return err
+	}
+	writeFile(g.bundleOutputPath, bytes, g.sourceName, "bundled.yaml")
+	return nil
+}
+
+// Write a copy of the document without its unused components.
+func (g *Gnostic) writePruneOutput(message proto.Message) error {
+	var info *yaml.Node
+	switch g.sourceFormat {
+	case SourceFormatOpenAPI2:
+		info = message.(*openapi_v2.Document).ToRawInfo()
+	case SourceFormatOpenAPI3:
+		info = message.(*openapi_v3.Document).ToRawInfo()
+	default:
+		return errors.New("prune output requires an OpenAPI v2 or v3 source")
+	}
+	info, _, err := refgraph.Prune(info)
+	if err != nil {
+		return err
+	}
+	var bytes []byte
+	if strings.HasSuffix(g.pruneOutputPath, ".json") {
+		bytes, err = jsonwriter.Marshal(info)
+	} else {
+		bytes, err = yaml.Marshal(info)
+	}
+	if err != nil {
+		return err
+	}
+	writeFile(g.pruneOutputPath, bytes, g.sourceName, "pruned.yaml")
+	return nil
+}
+
 // Write messages.
-func (g *Gnostic) writeMessagesOutput(message proto.Message) error {
-	protoBytes, err := proto.Marshal(message)
+func (g *Gnostic) writeMessagesOutput(messages []*plugins.Message) error {
+	format := g.messagesFormat
+	if format == "" {
+		format = plugins.MessagesFormatPB
+	}
+	bytes, err := plugins.FormatMessages(messages, format, g.sourceName)
 	if err != nil {
 		writeFile(g.messageOutputPath, g.errorBytes(err), g.sourceName, "errors")
 	} else {
-		writeFile(g.messageOutputPath, protoBytes, g.sourceName, "messages.pb")
+		writeFile(g.messageOutputPath, bytes, g.sourceName, plugins.MessagesFormatExtension(format))
 	}
 	return err
 }
 
+// Convert a document to the format specified with --convert-to.
+// Any returned messages describe parts of the document that could not be converted.
+func (g *Gnostic) convertDocument(message proto.Message) (proto.Message, []*plugins.Message, error) {
+	switch g.convertTo {
+	case "openapi3":
+		if g.sourceFormat != SourceFormatOpenAPI2 {
+			return nil, nil, errors.New("conversion to openapi3 requires an OpenAPI v2 source")
+		}
+		document, messages, err := conversions.OpenAPIv2ToOpenAPIv3WithMessages(message.(*openapi_v2.Document))
+		if err != nil {
+			return nil, nil, err
+		}
+		g.sourceFormat = SourceFormatOpenAPI3
+		return document, messages, nil
+	case "openapi2":
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return nil, nil, errors.New("conversion to openapi2 requires an OpenAPI v3 source")
+		}
+		document, messages, err := conversions.OpenAPIv3ToOpenAPIv2(message.(*openapi_v3.Document))
+		if err != nil {
+			return nil, nil, err
+		}
+		g.sourceFormat = SourceFormatOpenAPI2
+		return document, messages, nil
+	}
+	return message, nil, nil
+}
+
+// Remove the operations that aren't selected with --include and --exclude
+// and the components that only they reference.
+// Any returned messages describe path items that couldn't be filtered.
+func (g *Gnostic) filterDocument(message proto.Message) (proto.Message, []*plugins.Message, error) {
+	switch g.sourceFormat {
+	case SourceFormatOpenAPI2:
+		return filter.FilterOpenAPIv2(message.(*openapi_v2.Document), &g.filterOptions)
+	case SourceFormatOpenAPI3:
+		return filter.FilterOpenAPIv3(message.(*openapi_v3.Document), &g.filterOptions)
+	default:
+		return nil, nil, errors.New("filtering requires an OpenAPI v2 or v3 source")
+	}
+}
+
+// Replace the references in a document with the objects that they refer to.
+// Any returned messages describe recursive references that were left in place.
+func (g *Gnostic) dereferenceDocument(message proto.Message) (proto.Message, []*plugins.Message, error) {
+	switch g.sourceFormat {
+	case SourceFormatOpenAPI2:
+		return bundler.DereferenceOpenAPIv2WithSession(g.session, message.(*openapi_v2.Document), g.sourceName)
+	case SourceFormatOpenAPI3:
+		return bundler.DereferenceOpenAPIv3WithSession(g.session, message.(*openapi_v3.Document), g.sourceName)
+	default:
+		return nil, nil, errors.New("dereferencing requires an OpenAPI v2 or v3 source")
+	}
+}
+
+// performPlugins calls all specified plugins using up to g.pluginWorkers
+// concurrent processes and returns their results in invocation order.
+func (g *Gnostic) performPlugins(ctx context.Context, message proto.Message) []pluginResult {
+	results := make([]pluginResult, len(g.pluginCalls))
+	// Build the source map before starting workers that share it.
+	sourceMap := g.getSourceMap()
+	calls := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < g.pluginWorkers && w < len(g.pluginCalls); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range calls {
+				if err := ctx.Err(); err != nil {
+					results[i].err = fmt.Errorf("%s was canceled: %v", pluginPrefix+g.pluginCalls[i].Name, err)
+					continue
+				}
+				results[i] = g.pluginCalls[i].perform(ctx, message, g.sourceFormat, g.sourceName, sourceMap, g.session, g.pluginTimeout, g.excludeSurface)
+			}
+		}()
+	}
+	for i := range g.pluginCalls {
+		calls <- i
+	}
+	close(calls)
+	wg.Wait()
+	return results
+}
+
+// checkCanceled returns an error if ctx is canceled.
+func checkCanceled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("gnostic was canceled: %v", err)
+	}
+	return nil
+}
+
 // Perform all actions specified in the command-line options.
-func (g *Gnostic) performActions(message proto.Message) (err error) {
+func (g *Gnostic) performActions(ctx context.Context, message proto.Message) (err error) {
 	// Optionally resolve internal references.
 	if g.resolveReferences {
 		if g.sourceFormat == SourceFormatOpenAPI2 {
 			document := message.(*openapi_v2.Document)
-			_, err = document.ResolveReferences(g.sourceName)
+			_, err = document.ResolveReferencesInContext(g.newContext(nil))
 		} else if g.sourceFormat == SourceFormatOpenAPI3 {
 			document := message.(*openapi_v3.Document)
-			_, err = document.ResolveReferences(g.sourceName)
+			_, err = document.ResolveReferencesInContext(g.newContext(nil))
+		} else if g.sourceFormat == SourceFormatOpenAPI31 {
+			document := message.(*openapi_v31.Document)
+			_, err = document.ResolveReferencesInContext(g.newContext(nil))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = checkCanceled(ctx); err != nil {
+		return err
+	}
+	messages := make([]*plugins.Message, 0)
+	messages = append(messages, g.overlayMessages...)
+	// Optionally replace references with the objects that they refer to.
+	if g.dereference {
+		var dereferenceMessages []*plugins.Message
+		message, dereferenceMessages, err = g.dereferenceDocument(message)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, dereferenceMessages...)
+	}
+	// Optionally convert the document to another format.
+	if g.convertTo != "" {
+		var conversionMessages []*plugins.Message
+		message, conversionMessages, err = g.convertDocument(message)
+		if err != nil {
+			return err
 		}
+		messages = append(messages, conversionMessages...)
+	}
+	// Optionally select a subset of the document's operations.
+	if !g.filterOptions.Include.Empty() || !g.filterOptions.Exclude.Empty() {
+		var filterMessages []*plugins.Message
+		message, filterMessages, err = g.filterDocument(message)
 		if err != nil {
 			return err
 		}
+		messages = append(messages, filterMessages...)
+	}
+	if err = checkCanceled(ctx); err != nil {
+		return err
 	}
 	// Optionally write proto in binary format.
 	if g.binaryOutputPath != "" {
@@ -604,35 +1127,105 @@ func (g *Gnostic) performActions(message proto.Message) (err error) {
 	if g.yamlOutputPath != "" || g.jsonOutputPath != "" {
 		g.writeJSONYAMLOutput(message)
 	}
+	// Optionally write a Discovery Format description.
+	if g.discoOutputPath != "" {
+		var discoveryMessages []*plugins.Message
+		discoveryMessages, err = g.writeDiscoveryOutput(message)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, discoveryMessages...)
+	}
+	// Optionally write a bundled description.
+	if g.bundleOutputPath != "" {
+		err = g.writeBundleOutput()
+		if err != nil {
+			return err
+		}
+	}
+	// Optionally write a description without unused components.
+	if g.pruneOutputPath != "" {
+		err = g.writePruneOutput(message)
+		if err != nil {
+			return err
+		}
+	}
+	if err = checkCanceled(ctx); err != nil {
+		return err
+	}
 	// Call all specified plugins.
-	messages := make([]*plugins.Message, 0)
+	g.messagesSummary = newMessagesSummary()
+	g.messagesSummary.add(sourceGnostic, messages)
 	errors := make([]error, 0)
-	for _, p := range g.pluginCalls {
-		pluginMessages, err := p.perform(message, g.sourceFormat, g.sourceName, g.timePlugins, g.excludeSurface)
-		if err != nil {
+	for i, result := range g.performPlugins(ctx, message) {
+		p := g.pluginCalls[i]
+		if g.timePlugins && result.stats != nil {
+			fmt.Printf("> %s (%s)\n", pluginPrefix+p.Name, result.stats)
+		}
+		if result.err != nil {
 			// we don't exit or fail here so that we run all plugins even when some have errors
-			errors = append(errors, err)
+			errors = append(errors, result.err)
+		}
+		var pluginMessages []*plugins.Message
+		if result.response != nil {
+			// Responses are handled here rather than in the workers so that
+			// plugin outputs are written in the order that plugins are specified.
+			if err := plugins.HandleResponse(result.response, result.outputLocation); err != nil {
+				errors = append(errors, err)
+			}
+			pluginMessages = result.response.Messages
 		}
+		g.messagesSummary.add(pluginPrefix+p.Name, pluginMessages)
 		messages = append(messages, pluginMessages...)
 	}
+	// Locate messages that have keys but no locations.
+	if len(messages) > 0 {
+		if sourceMap := g.getSourceMap(); sourceMap != nil {
+			sourceMap.AddLocations(messages)
+		}
+	}
 	if g.messageOutputPath != "" {
-		err = g.writeMessagesOutput(&plugins.Messages{Messages: messages})
+		err = g.writeMessagesOutput(messages)
 		if err != nil {
 			return err
 		}
-	} else {
+	} else if len(messages) > 0 || g.messagesFormat != "" {
 		// Print any messages from the plugins
-		if len(messages) > 0 {
-			for _, message := range messages {
-				fmt.Printf("%+v\n", message)
-			}
+		format := g.messagesFormat
+		if format == "" {
+			format = plugins.MessagesFormatText
+		}
+		bytes, err := plugins.FormatMessages(messages, format, g.sourceName)
+		if err != nil {
+			return err
 		}
+		os.Stdout.Write(bytes)
+	}
+	// Optionally fail if messages are at or above a specified level.
+	// The summary is printed even when plugins fail, but plugin errors take precedence.
+	if g.failOn != plugins.Message_UNKNOWN {
+		fmt.Fprint(os.Stderr, g.messagesSummary.String())
+	}
+	if len(errors) > 0 {
+		return compiler.NewErrorGroupOrNil(errors)
 	}
-	return compiler.NewErrorGroupOrNil(errors)
+	if g.failOn != plugins.Message_UNKNOWN && g.messagesSummary.AtOrAbove(g.failOn) > 0 {
+		return &MessagesError{Level: g.failOn, Summary: g.messagesSummary}
+	}
+	return nil
 }
 
 // Main is the main program for Gnostic.
+// Plugins run in their own process groups, so they don't receive the
+// interrupts that a terminal sends to gnostic. Programs that handle
+// interrupts should call MainContext and cancel its context to stop plugins.
 func (g *Gnostic) Main() error {
+	return g.MainContext(context.Background())
+}
+
+// MainContext is like Main but stops between steps, kills any running plugins,
+// and skips any remaining ones when ctx is canceled.
+func (g *Gnostic) MainContext(ctx context.Context) error {
 	// if help is requested, print usage and immediately exit
 	for _, arg := range g.args {
 		if arg == "--help" {
@@ -641,7 +1234,8 @@ func (g *Gnostic) Main() error {
 		}
 	}
 
-	compiler.ClearCaches()
+	// Each run reads files with its own session so that concurrent runs don't share caches.
+	g.session = compiler.NewSession()
 
 	var err error
 	err = g.readOptions()
@@ -652,12 +1246,18 @@ func (g *Gnostic) Main() error {
 	if err != nil {
 		return err
 	}
+	if loader := g.documentLoader(); loader != nil {
+		g.session.SetLoader(loader)
+	}
 	// Read the OpenAPI source.
-	bytes, err := compiler.ReadBytesForFile(g.sourceName)
+	bytes, err := g.session.ReadBytesForFile(g.sourceName)
 	if err != nil {
 		writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
 		return err
 	}
+	if err = checkCanceled(ctx); err != nil {
+		return err
+	}
 	extension := strings.ToLower(filepath.Ext(g.sourceName))
 	var message proto.Message
 	if extension == ".json" || extension == ".yaml" {
@@ -679,11 +1279,23 @@ func (g *Gnostic) Main() error {
 		writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
 		return err
 	}
+	if err = checkCanceled(ctx); err != nil {
+		return err
+	}
 	// Perform actions specified by command options.
-	err = g.performActions(message)
+	err = g.performActions(ctx, message)
 	if err != nil {
-		writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+		// Messages that cause failures have already been reported.
+		if _, ok := err.(*MessagesError); !ok {
+			writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+		}
 		return err
 	}
 	return nil
 }
+
+// MessagesSummary returns the counts of the messages reported by the last
+// call of Main, or nil if Main failed before calling plugins.
+func (g *Gnostic) MessagesSummary() *MessagesSummary {
+	return g.messagesSummary
+}
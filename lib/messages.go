@@ -0,0 +1,128 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// sourceGnostic identifies messages that are reported by gnostic itself
+// rather than by plugins, such as messages about overlays and conversions.
+const sourceGnostic = "gnostic"
+
+// failOnLevels are the message levels that can be specified with --fail-on.
+var failOnLevels = map[string]plugins.Message_Level{
+	"warning": plugins.Message_WARNING,
+	"error":   plugins.Message_ERROR,
+	"fatal":   plugins.Message_FATAL,
+}
+
+// MessagesSummary counts the messages reported by a run of gnostic
+// by level and by the plugin that reported them.
+type MessagesSummary struct {
+	// Levels counts messages by level.
+	Levels map[plugins.Message_Level]int
+	// Sources counts messages by level for each source of messages, which is
+	// the executable name of a plugin, such as "gnostic-linter", or "gnostic"
+	// for messages that gnostic reports itself.
+	Sources map[string]map[plugins.Message_Level]int
+	// sources lists the sources of messages in the order that they were added.
+	sources []string
+}
+
+func newMessagesSummary() *MessagesSummary {
+	return &MessagesSummary{
+		Levels:  make(map[plugins.Message_Level]int),
+		Sources: make(map[string]map[plugins.Message_Level]int),
+	}
+}
+
+// add counts messages reported by a source.
+func (s *MessagesSummary) add(source string, messages []*plugins.Message) {
+	if s.Sources[source] == nil {
+		s.Sources[source] = make(map[plugins.Message_Level]int)
+		s.sources = append(s.sources, source)
+	}
+	for _, message := range messages {
+		s.Levels[message.GetLevel()]++
+		s.Sources[source][message.GetLevel()]++
+	}
+}
+
+// AtOrAbove returns the number of messages with levels at or above the specified level.
+func (s *MessagesSummary) AtOrAbove(level plugins.Message_Level) int {
+	count := 0
+	for l, n := range s.Levels {
+		if l >= level {
+			count += n
+		}
+	}
+	return count
+}
+
+// String describes the counts of messages, listing the total for each level
+// followed by the counts for each source that reported messages.
+func (s *MessagesSummary) String() string {
+	var b strings.Builder
+	b.WriteString("Messages: " + describeCounts(s.Levels) + "\n")
+	for _, source := range s.sources {
+		if counts := s.Sources[source]; len(counts) > 0 {
+			b.WriteString("  " + source + ": " + describeCounts(counts) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// describeCounts lists counts of messages from the most to the least severe level.
+func describeCounts(counts map[plugins.Message_Level]int) string {
+	levels := make([]plugins.Message_Level, 0, len(counts))
+	for level, n := range counts {
+		if n > 0 {
+			levels = append(levels, level)
+		}
+	}
+	if len(levels) == 0 {
+		return "none"
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] > levels[j] })
+	parts := make([]string, 0, len(levels))
+	for _, level := range levels {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[level], level))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MessagesError is returned by Main when messages are reported with levels at or
+// above the level specified with --fail-on. The gnostic command exits with status 1
+// when it gets a MessagesError and with status -1 for all other errors.
+type MessagesError struct {
+	// Level is the level specified with --fail-on.
+	Level plugins.Message_Level
+	// Summary counts all of the messages that were reported.
+	Summary *MessagesSummary
+}
+
+func (e *MessagesError) Error() string {
+	count := e.Summary.AtOrAbove(e.Level)
+	noun := "messages"
+	if count == 1 {
+		noun = "message"
+	}
+	return fmt.Sprintf("%d %s at or above level %s", count, noun, e.Level)
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package lib
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing because process groups aren't available on this platform.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills a process. Processes that it started aren't killed.
+func killProcessGroup(process *os.Process) error {
+	return process.Kill()
+}
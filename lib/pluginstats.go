@@ -0,0 +1,44 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// pluginStats describes the resources used by a plugin process that has exited.
+type pluginStats struct {
+	wall   time.Duration
+	cpu    time.Duration
+	maxRSS int64 // peak resident set size in bytes, or zero if unknown
+}
+
+func newPluginStats(wall time.Duration, state *os.ProcessState) *pluginStats {
+	return &pluginStats{
+		wall:   wall,
+		cpu:    state.UserTime() + state.SystemTime(),
+		maxRSS: maxRSS(state),
+	}
+}
+
+func (s *pluginStats) String() string {
+	text := fmt.Sprintf("wall %s, cpu %s", s.wall, s.cpu)
+	if s.maxRSS > 0 {
+		text += fmt.Sprintf(", peak rss %.1f MB", float64(s.maxRSS)/(1<<20))
+	}
+	return text
+}
@@ -0,0 +1,477 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package merge combines several OpenAPI v3 descriptions into one, such as the
+// description of a gateway that fronts several services. Conflicts that can't
+// be resolved automatically are reported as ERROR messages.
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// Message codes used to report conflicts between sources.
+const (
+	DuplicateOperationID = "DUPLICATEOPERATIONID"
+	DuplicatePath        = "DUPLICATEPATH"
+	RenamedComponent     = "RENAMEDCOMPONENT"
+)
+
+// Source is a document to be merged.
+type Source struct {
+	// Name identifies the source in messages and is prepended to the names
+	// of its components that conflict with components of other sources.
+	Name string
+	// PathPrefix is prepended to each of the source's paths, e.g. "/pets".
+	PathPrefix string
+	// Document is the source's OpenAPI v3 description.
+	Document *openapi3.Document
+}
+
+// merger holds the state of a merging operation.
+type merger struct {
+	sources []*Source
+	// roots holds copies of the root nodes of the sources' documents.
+	roots []*yaml.Node
+	// names maps the section and name of each component of each source
+	// (e.g. "schemas/Pet") to the component's name in the result.
+	names []map[string]string
+	// messages reports conflicts between sources.
+	messages []*plugins.Message
+}
+
+// MergeOpenAPIv3 combines OpenAPI v3 documents into a single document. The info,
+// servers, and other top-level fields of the result are taken from the first source.
+// Structurally equal components with the same name are merged, and components
+// whose names conflict with different components of earlier sources are renamed.
+// Tags and security schemes are merged by name. Paths that are the same after
+// their sources' prefixes are added and operationIds that are used by more than
+// one operation are reported as ERROR messages.
+func MergeOpenAPIv3(sources []*Source) (*openapi3.Document, []*plugins.Message, error) {
+	if len(sources) == 0 {
+		return nil, nil, errors.New("no documents to merge")
+	}
+	m := &merger{sources: sources}
+	for _, source := range sources {
+		root := source.Document.ToRawInfo()
+		if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+			root = root.Content[0]
+		}
+		if root.Kind != yaml.MappingNode {
+			return nil, nil, fmt.Errorf("invalid document %s", source.Name)
+		}
+		m.roots = append(m.roots, root)
+	}
+	m.nameComponents()
+	info := m.merge()
+	document, err := openapi3.NewDocument(info, compiler.NewContext("$root", info, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, m.messages, nil
+}
+
+// nameComponents chooses the name of each component in the result. A component
+// is renamed when an earlier source has a different component with the same name.
+// Components are compared after the references that they contain are renamed,
+// so renaming one component can cause others to be renamed.
+func (m *merger) nameComponents() {
+	renamed := make([]map[string]bool, len(m.roots))
+	for i := range renamed {
+		renamed[i] = make(map[string]bool)
+	}
+	for {
+		owners := m.assignNames(renamed)
+		// Verify that components that share a name are still equal when their references are renamed.
+		changed := false
+		contents := make(map[string]*yaml.Node)
+		for i, root := range m.roots {
+			forEachComponent(root, func(id string, value *yaml.Node) {
+				target := sectionForID(id) + "/" + m.names[i][id]
+				content := compiler.CopyNode(value)
+				m.rewriteRefs(i, content)
+				if existing, ok := contents[target]; !ok {
+					contents[target] = content
+				} else if !equalNodes(existing, content) && owners[target] != i && !renamed[i][id] {
+					renamed[i][id] = true
+					changed = true
+				}
+			})
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// assignNames assigns names to components, renaming the specified components and any
+// components that differ from earlier components with the same name. It returns a map
+// from the section and name of each component in the result to the source that first used it.
+func (m *merger) assignNames(renamed []map[string]bool) map[string]int {
+	m.names = make([]map[string]string, len(m.roots))
+	m.messages = nil
+	owners := make(map[string]int)
+	used := make(map[string]*yaml.Node)
+	for i, root := range m.roots {
+		m.names[i] = make(map[string]string)
+		forEachComponent(root, func(id string, value *yaml.Node) {
+			section, name := sectionForID(id), nameForID(id)
+			if existing, ok := used[id]; !renamed[i][id] && (!ok || equalNodes(existing, value)) {
+				if !ok {
+					used[id] = value
+					owners[id] = i
+				}
+				m.names[i][id] = name
+				return
+			}
+			prefix := unsafeNameCharacters.ReplaceAllString(m.sources[i].Name, "_")
+			newName := prefix + "_" + name
+			for n := 2; used[section+"/"+newName] != nil; n++ {
+				newName = fmt.Sprintf("%s_%s_%d", prefix, name, n)
+			}
+			used[section+"/"+newName] = value
+			owners[section+"/"+newName] = i
+			m.names[i][id] = newName
+			m.messages = append(m.messages, &plugins.Message{
+				Level: plugins.Message_WARNING,
+				Code:  RenamedComponent,
+				Text: fmt.Sprintf("%s %s of %s conflicts with a component of %s and was renamed %s.",
+					section, name, m.sources[i].Name, m.sources[owners[id]].Name, newName),
+				Keys: []string{"components", section, newName},
+			})
+		})
+	}
+	return owners
+}
+
+var unsafeNameCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// merge builds the merged document.
+func (m *merger) merge() *yaml.Node {
+	root := compiler.CopyNode(m.roots[0])
+	security := m.securityForSource(0)
+	setValue(root, "security", security)
+	setValue(root, "paths", m.mergePaths(security))
+	setValue(root, "components", m.mergeComponents())
+	setValue(root, "tags", m.mergeTags())
+	return root
+}
+
+// securityForSource returns a copy of the top-level security requirements of a
+// source with its security schemes renamed, or nil if it has none.
+func (m *merger) securityForSource(i int) *yaml.Node {
+	security := compiler.MapValueForKey(m.roots[i], "security")
+	if security == nil {
+		return nil
+	}
+	security = compiler.CopyNode(security)
+	m.renameSecuritySchemes(i, security)
+	return security
+}
+
+// mergePaths combines the paths of all sources. Operations of sources whose top-level
+// security requirements differ from those of the result are given explicit requirements.
+func (m *merger) mergePaths(security *yaml.Node) *yaml.Node {
+	paths := compiler.NewMappingNode()
+	templates := make(map[string]string)
+	operationIDs := make(map[string]string)
+	for i, root := range m.roots {
+		source := m.sources[i]
+		sourceSecurity := m.securityForSource(i)
+		if equalNodes(sourceSecurity, security) {
+			sourceSecurity = nil
+		} else if sourceSecurity == nil {
+			sourceSecurity = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		}
+		sourcePaths := compiler.MapValueForKey(root, "paths")
+		if sourcePaths == nil || sourcePaths.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(sourcePaths.Content)-1; j += 2 {
+			path, value := sourcePaths.Content[j].Value, compiler.CopyNode(sourcePaths.Content[j+1])
+			if strings.HasPrefix(path, "x-") {
+				if !compiler.MapHasKey(paths, path) {
+					paths.Content = append(paths.Content, compiler.NewScalarNodeForString(path), value)
+				}
+				continue
+			}
+			path = joinPath(source.PathPrefix, path)
+			template := pathTemplate.ReplaceAllString(path, "{}")
+			if other, ok := templates[template]; ok {
+				m.messages = append(m.messages, &plugins.Message{
+					Level: plugins.Message_ERROR,
+					Code:  DuplicatePath,
+					Text:  fmt.Sprintf("Path %s of %s conflicts with a path of %s and was not merged.", path, source.Name, other),
+					Keys:  []string{"paths", path},
+				})
+				continue
+			}
+			templates[template] = source.Name
+			m.rewriteRefs(i, value)
+			compiler.ForEachEntry(value, func(method string, operation *yaml.Node) {
+				if !compiler.StringArrayContainsValue(methods, method) || operation.Kind != yaml.MappingNode {
+					return
+				}
+				if operationSecurity := compiler.MapValueForKey(operation, "security"); operationSecurity != nil {
+					m.renameSecuritySchemes(i, operationSecurity)
+				} else if sourceSecurity != nil {
+					setValue(operation, "security", compiler.CopyNode(sourceSecurity))
+				}
+				operationID, ok := compiler.StringForScalarNode(compiler.MapValueForKey(operation, "operationId"))
+				if !ok {
+					return
+				}
+				if other, ok := operationIDs[operationID]; ok {
+					m.messages = append(m.messages, &plugins.Message{
+						Level: plugins.Message_ERROR,
+						Code:  DuplicateOperationID,
+						Text:  fmt.Sprintf("operationId %s of %s is also used by %s.", operationID, source.Name, other),
+						Keys:  []string{"paths", path, method, "operationId"},
+					})
+					return
+				}
+				operationIDs[operationID] = source.Name
+			})
+			paths.Content = append(paths.Content, compiler.NewScalarNodeForString(path), value)
+		}
+	}
+	return paths
+}
+
+// mergeComponents combines the components of all sources using the names chosen by nameComponents.
+func (m *merger) mergeComponents() *yaml.Node {
+	var components *yaml.Node
+	for i, root := range m.roots {
+		sourceComponents := compiler.MapValueForKey(root, "components")
+		forEachComponent(root, func(id string, value *yaml.Node) {
+			if components == nil {
+				components = compiler.NewMappingNode()
+			}
+			section := compiler.MapValueForKey(components, sectionForID(id))
+			if section == nil {
+				section = compiler.NewMappingNode()
+				setValue(components, sectionForID(id), section)
+			}
+			name := m.names[i][id]
+			if compiler.MapHasKey(section, name) {
+				return
+			}
+			value = compiler.CopyNode(value)
+			m.rewriteRefs(i, value)
+			section.Content = append(section.Content, compiler.NewScalarNodeForString(name), value)
+		})
+		// Specification extensions of the components object are taken from the first source that has them.
+		if sourceComponents != nil && sourceComponents.Kind == yaml.MappingNode {
+			for j := 0; j < len(sourceComponents.Content)-1; j += 2 {
+				key := sourceComponents.Content[j].Value
+				if strings.HasPrefix(key, "x-") {
+					if components == nil {
+						components = compiler.NewMappingNode()
+					}
+					if !compiler.MapHasKey(components, key) {
+						setValue(components, key, compiler.CopyNode(sourceComponents.Content[j+1]))
+					}
+				}
+			}
+		}
+	}
+	return components
+}
+
+// mergeTags combines the tags of all sources. When several sources
+// describe the same tag, the description of the first is used.
+func (m *merger) mergeTags() *yaml.Node {
+	var tags *yaml.Node
+	names := make(map[string]bool)
+	for _, root := range m.roots {
+		sourceTags := compiler.MapValueForKey(root, "tags")
+		if sourceTags == nil || sourceTags.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, tag := range sourceTags.Content {
+			name, _ := compiler.StringForScalarNode(compiler.MapValueForKey(tag, "name"))
+			if names[name] {
+				continue
+			}
+			names[name] = true
+			if tags == nil {
+				tags = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			}
+			tags.Content = append(tags.Content, compiler.CopyNode(tag))
+		}
+	}
+	return tags
+}
+
+// rewriteRefs renames the components referenced by a node from the specified source.
+func (m *merger) rewriteRefs(i int, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			m.rewriteRefs(i, child)
+		}
+	case yaml.MappingNode:
+		for j := 0; j < len(node.Content)-1; j += 2 {
+			key, value := node.Content[j].Value, node.Content[j+1]
+			switch {
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				value.Value = m.renameRef(i, value.Value)
+			case key == "discriminator" && value.Kind == yaml.MappingNode:
+				compiler.ForEachEntry(compiler.MapValueForKey(value, "mapping"), func(_ string, target *yaml.Node) {
+					if strings.HasPrefix(target.Value, "#") {
+						target.Value = m.renameRef(i, target.Value)
+					} else if name, ok := m.names[i]["schemas/"+target.Value]; ok {
+						target.Value = name
+					}
+				})
+			default:
+				m.rewriteRefs(i, value)
+			}
+		}
+	}
+}
+
+// renameRef returns a local reference to a component with the component renamed.
+// Other references are returned unchanged.
+func (m *merger) renameRef(i int, ref string) string {
+	if !strings.HasPrefix(ref, "#/components/") {
+		return ref
+	}
+	tokens := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 3)
+	if len(tokens) < 2 {
+		return ref
+	}
+	name, ok := m.names[i][tokens[0]+"/"+compiler.UnescapePointerToken(tokens[1])]
+	if !ok {
+		return ref
+	}
+	tokens[1] = compiler.EscapePointerToken(name)
+	return "#/components/" + strings.Join(tokens, "/")
+}
+
+// renameSecuritySchemes renames the security schemes named in a list of security requirements.
+func (m *merger) renameSecuritySchemes(i int, security *yaml.Node) {
+	if security.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, requirement := range security.Content {
+		if requirement.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(requirement.Content)-1; j += 2 {
+			if name, ok := m.names[i]["securitySchemes/"+requirement.Content[j].Value]; ok {
+				requirement.Content[j].Value = name
+			}
+		}
+	}
+}
+
+// methods lists the fields of path items that hold operations.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var pathTemplate = regexp.MustCompile(`\{[^}]*\}`)
+
+// joinPath prepends a prefix to a path.
+func joinPath(prefix, path string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if prefix != "" && path == "/" {
+		return prefix
+	}
+	return prefix + path
+}
+
+// forEachComponent calls a function for each component of a document with an ID
+// of the form "SECTION/NAME".
+func forEachComponent(root *yaml.Node, f func(id string, value *yaml.Node)) {
+	compiler.ForEachEntry(compiler.MapValueForKey(root, "components"), func(section string, entries *yaml.Node) {
+		compiler.ForEachEntry(entries, func(name string, value *yaml.Node) {
+			f(section+"/"+name, value)
+		})
+	})
+}
+
+func sectionForID(id string) string {
+	return id[:strings.Index(id, "/")]
+}
+
+func nameForID(id string) string {
+	return id[strings.Index(id, "/")+1:]
+}
+
+// setValue sets the value of a key in a mapping node,
+// removing the key if the value is nil.
+func setValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			if value == nil {
+				node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			} else {
+				node.Content[i+1] = value
+			}
+			return
+		}
+	}
+	if value != nil {
+		node.Content = append(node.Content, compiler.NewScalarNodeForString(key), value)
+	}
+}
+
+// equalNodes returns true if two nodes have the same structure and values.
+// The order of the entries of mapping nodes is ignored.
+func equalNodes(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.Value == b.Value && a.ShortTag() == b.ShortTag()
+	case yaml.MappingNode:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := 0; i < len(a.Content)-1; i += 2 {
+			if !equalNodes(a.Content[i+1], compiler.MapValueForKey(b, a.Content[i].Value)) {
+				return false
+			}
+		}
+		return true
+	case yaml.AliasNode:
+		return equalNodes(a.Alias, b.Alias)
+	default:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := range a.Content {
+			if !equalNodes(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	plugins "github.com/google/gnostic/plugins"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+const pets = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+security:
+  - apiKey: []
+tags:
+  - name: pets
+    description: Pets for sale.
+paths:
+  /:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/PetList'
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  responses:
+    Error:
+      description: error
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Error'
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+    PetList:
+      type: array
+      items:
+        $ref: '#/components/schemas/Pet'
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`
+
+const stores = `
+openapi: 3.0.0
+info:
+  title: Stores
+  version: 2.0.0
+security:
+  - apiKey: []
+tags:
+  - name: pets
+    description: Pets in stock.
+  - name: stores
+paths:
+  /{storeId}/pets:
+    get:
+      operationId: listPets
+      tags: [stores, pets]
+      parameters:
+        - name: storeId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/PetList'
+        default:
+          $ref: '#/components/responses/Error'
+  /health:
+    get:
+      operationId: health
+      security: []
+      responses:
+        "200":
+          description: ok
+components:
+  responses:
+    Error:
+      description: error
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Error'
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+    PetList:
+      type: array
+      items:
+        $ref: '#/components/schemas/Pet'
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-Store-Key
+      in: header
+`
+
+func parse(t *testing.T, text string) *openapi3.Document {
+	document, err := openapi3.ParseDocument([]byte(text))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return document
+}
+
+func valueAt(t *testing.T, node *yaml.Node, keys ...string) string {
+	for _, key := range keys {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i < len(node.Content)-1; i += 2 {
+				if node.Content[i].Value == key {
+					next = node.Content[i+1]
+				}
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				if key == string(rune('0'+i)) {
+					next = child
+				}
+			}
+		}
+		if next == nil {
+			return ""
+		}
+		node = next
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	bytes, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return string(bytes)
+}
+
+func TestMergeOpenAPIv3(t *testing.T) {
+	document, messages, err := MergeOpenAPIv3([]*Source{
+		{Name: "pets", PathPrefix: "/pets", Document: parse(t, pets)},
+		{Name: "stores", PathPrefix: "/stores/", Document: parse(t, stores)},
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	root := document.ToRawInfo()
+	expected := []struct {
+		keys  []string
+		value string
+	}{
+		{[]string{"info", "title"}, "Pets"},
+		{[]string{"paths", "/pets", "get", "responses", "200", "content", "application/json", "schema", "$ref"}, "#/components/schemas/PetList"},
+		{[]string{"paths", "/pets", "get", "security"}, ""},
+		{[]string{"paths", "/stores/{storeId}/pets", "get", "responses", "200", "content", "application/json", "schema", "$ref"}, "#/components/schemas/stores_PetList"},
+		{[]string{"paths", "/stores/{storeId}/pets", "get", "responses", "default", "$ref"}, "#/components/responses/Error"},
+		{[]string{"paths", "/stores/{storeId}/pets", "get", "security"}, "- stores_apiKey: []\n"},
+		{[]string{"paths", "/stores/health", "get", "responses", "200", "description"}, "ok"},
+		{[]string{"components", "schemas", "stores_Pet", "properties", "id", "type"}, "integer"},
+		{[]string{"components", "schemas", "stores_PetList", "items", "$ref"}, "#/components/schemas/stores_Pet"},
+		{[]string{"components", "schemas", "stores_Error"}, ""},
+		{[]string{"components", "responses", "stores_Error"}, ""},
+		{[]string{"components", "securitySchemes", "stores_apiKey", "name"}, "X-Store-Key"},
+		{[]string{"security"}, "- apiKey: []\n"},
+		{[]string{"tags", "0", "description"}, "Pets for sale."},
+		{[]string{"tags", "1", "name"}, "stores"},
+		{[]string{"tags", "2"}, ""},
+	}
+	for _, e := range expected {
+		if value := valueAt(t, root, e.keys...); value != e.value {
+			t.Errorf("unexpected value at %v: %q (expected %q)", e.keys, value, e.value)
+		}
+	}
+	codes := make(map[string]int)
+	for _, message := range messages {
+		codes[message.Code]++
+		if message.Code == DuplicateOperationID {
+			if message.Level != plugins.Message_ERROR {
+				t.Errorf("unexpected level for %s: %s", message.Code, message.Level)
+			}
+			if valueAt(t, root, message.Keys...) != "listPets" {
+				t.Errorf("unexpected keys for %s: %v", message.Code, message.Keys)
+			}
+		}
+	}
+	if codes[DuplicateOperationID] != 1 || codes[RenamedComponent] != 3 || len(messages) != 4 {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
+
+func TestMergeDuplicatePaths(t *testing.T) {
+	_, messages, err := MergeOpenAPIv3([]*Source{
+		{Name: "a", PathPrefix: "/stores", Document: parse(t, stores)},
+		{Name: "b", PathPrefix: "/stores", Document: parse(t, stores)},
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	paths := 0
+	for _, message := range messages {
+		if message.Code == DuplicatePath {
+			paths++
+		} else {
+			t.Errorf("unexpected message: %+v", message)
+		}
+	}
+	if paths != 2 {
+		t.Errorf("expected 2 duplicate paths, found %d", paths)
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct{ prefix, path, expected string }{
+		{"", "/pets", "/pets"},
+		{"/api", "/pets", "/api/pets"},
+		{"/api/", "/pets", "/api/pets"},
+		{"api", "/pets", "/api/pets"},
+		{"/api", "/", "/api"},
+	}
+	for _, test := range tests {
+		if result := joinPath(test.prefix, test.path); result != test.expected {
+			t.Errorf("joinPath(%q, %q) = %q, expected %q", test.prefix, test.path, result, test.expected)
+		}
+	}
+}
@@ -5461,11 +5461,17 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 
 // ResolveReferences resolves references found inside AdditionalPropertiesItem objects.
 func (m *AdditionalPropertiesItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside AdditionalPropertiesItem objects
+// using the session and base URI of the specified context.
+func (m *AdditionalPropertiesItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*AdditionalPropertiesItem_Schema)
 		if ok {
-			_, err := p.Schema.ResolveReferences(root)
+			_, err := p.Schema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5476,16 +5482,28 @@ func (m *AdditionalPropertiesItem) ResolveReferences(root string) (*yaml.Node, e
 
 // ResolveReferences resolves references found inside Any objects.
 func (m *Any) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Any objects
+// using the session and base URI of the specified context.
+func (m *Any) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside ApiKeySecurity objects.
 func (m *ApiKeySecurity) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ApiKeySecurity objects
+// using the session and base URI of the specified context.
+func (m *ApiKeySecurity) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5496,10 +5514,16 @@ func (m *ApiKeySecurity) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside BasicAuthenticationSecurity objects.
 func (m *BasicAuthenticationSecurity) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside BasicAuthenticationSecurity objects
+// using the session and base URI of the specified context.
+func (m *BasicAuthenticationSecurity) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5510,16 +5534,22 @@ func (m *BasicAuthenticationSecurity) ResolveReferences(root string) (*yaml.Node
 
 // ResolveReferences resolves references found inside BodyParameter objects.
 func (m *BodyParameter) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside BodyParameter objects
+// using the session and base URI of the specified context.
+func (m *BodyParameter) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Schema != nil {
-		_, err := m.Schema.ResolveReferences(root)
+		_, err := m.Schema.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5530,10 +5560,16 @@ func (m *BodyParameter) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Contact objects.
 func (m *Contact) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Contact objects
+// using the session and base URI of the specified context.
+func (m *Contact) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5544,10 +5580,16 @@ func (m *Contact) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Default objects.
 func (m *Default) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Default objects
+// using the session and base URI of the specified context.
+func (m *Default) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5558,10 +5600,16 @@ func (m *Default) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Definitions objects.
 func (m *Definitions) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Definitions objects
+// using the session and base URI of the specified context.
+func (m *Definitions) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5572,68 +5620,74 @@ func (m *Definitions) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Document objects.
 func (m *Document) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Document objects
+// using the session and base URI of the specified context.
+func (m *Document) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Info != nil {
-		_, err := m.Info.ResolveReferences(root)
+		_, err := m.Info.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Paths != nil {
-		_, err := m.Paths.ResolveReferences(root)
+		_, err := m.Paths.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Definitions != nil {
-		_, err := m.Definitions.ResolveReferences(root)
+		_, err := m.Definitions.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Parameters != nil {
-		_, err := m.Parameters.ResolveReferences(root)
+		_, err := m.Parameters.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Responses != nil {
-		_, err := m.Responses.ResolveReferences(root)
+		_, err := m.Responses.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Security {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.SecurityDefinitions != nil {
-		_, err := m.SecurityDefinitions.ResolveReferences(root)
+		_, err := m.SecurityDefinitions.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Tags {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5644,10 +5698,16 @@ func (m *Document) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Examples objects.
 func (m *Examples) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Examples objects
+// using the session and base URI of the specified context.
+func (m *Examples) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5658,10 +5718,16 @@ func (m *Examples) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ExternalDocs objects.
 func (m *ExternalDocs) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ExternalDocs objects
+// using the session and base URI of the specified context.
+func (m *ExternalDocs) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5672,28 +5738,34 @@ func (m *ExternalDocs) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside FileSchema objects.
 func (m *FileSchema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside FileSchema objects
+// using the session and base URI of the specified context.
+func (m *FileSchema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Example != nil {
-		_, err := m.Example.ResolveReferences(root)
+		_, err := m.Example.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5704,22 +5776,28 @@ func (m *FileSchema) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside FormDataParameterSubSchema objects.
 func (m *FormDataParameterSubSchema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside FormDataParameterSubSchema objects
+// using the session and base URI of the specified context.
+func (m *FormDataParameterSubSchema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5727,7 +5805,7 @@ func (m *FormDataParameterSubSchema) ResolveReferences(root string) (*yaml.Node,
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5738,22 +5816,28 @@ func (m *FormDataParameterSubSchema) ResolveReferences(root string) (*yaml.Node,
 
 // ResolveReferences resolves references found inside Header objects.
 func (m *Header) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Header objects
+// using the session and base URI of the specified context.
+func (m *Header) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5761,7 +5845,7 @@ func (m *Header) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5772,22 +5856,28 @@ func (m *Header) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside HeaderParameterSubSchema objects.
 func (m *HeaderParameterSubSchema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside HeaderParameterSubSchema objects
+// using the session and base URI of the specified context.
+func (m *HeaderParameterSubSchema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5795,7 +5885,7 @@ func (m *HeaderParameterSubSchema) ResolveReferences(root string) (*yaml.Node, e
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5806,10 +5896,16 @@ func (m *HeaderParameterSubSchema) ResolveReferences(root string) (*yaml.Node, e
 
 // ResolveReferences resolves references found inside Headers objects.
 func (m *Headers) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Headers objects
+// using the session and base URI of the specified context.
+func (m *Headers) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5820,22 +5916,28 @@ func (m *Headers) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Info objects.
 func (m *Info) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Info objects
+// using the session and base URI of the specified context.
+func (m *Info) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Contact != nil {
-		_, err := m.Contact.ResolveReferences(root)
+		_, err := m.Contact.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.License != nil {
-		_, err := m.License.ResolveReferences(root)
+		_, err := m.License.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5846,10 +5948,16 @@ func (m *Info) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ItemsItem objects.
 func (m *ItemsItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ItemsItem objects
+// using the session and base URI of the specified context.
+func (m *ItemsItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.Schema {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5860,17 +5968,23 @@ func (m *ItemsItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside JsonReference objects.
 func (m *JsonReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside JsonReference objects
+// using the session and base URI of the specified context.
+func (m *JsonReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewJsonReference(info, nil)
+			replacement, err := NewJsonReference(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
@@ -5880,10 +5994,16 @@ func (m *JsonReference) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside License objects.
 func (m *License) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside License objects
+// using the session and base URI of the specified context.
+func (m *License) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5894,9 +6014,15 @@ func (m *License) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedAny objects.
 func (m *NamedAny) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedAny objects
+// using the session and base URI of the specified context.
+func (m *NamedAny) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5906,9 +6032,15 @@ func (m *NamedAny) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedHeader objects.
 func (m *NamedHeader) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedHeader objects
+// using the session and base URI of the specified context.
+func (m *NamedHeader) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5918,9 +6050,15 @@ func (m *NamedHeader) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedParameter objects.
 func (m *NamedParameter) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedParameter objects
+// using the session and base URI of the specified context.
+func (m *NamedParameter) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5930,9 +6068,15 @@ func (m *NamedParameter) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedPathItem objects.
 func (m *NamedPathItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedPathItem objects
+// using the session and base URI of the specified context.
+func (m *NamedPathItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5942,9 +6086,15 @@ func (m *NamedPathItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedResponse objects.
 func (m *NamedResponse) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedResponse objects
+// using the session and base URI of the specified context.
+func (m *NamedResponse) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5954,9 +6104,15 @@ func (m *NamedResponse) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedResponseValue objects.
 func (m *NamedResponseValue) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedResponseValue objects
+// using the session and base URI of the specified context.
+func (m *NamedResponseValue) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5966,9 +6122,15 @@ func (m *NamedResponseValue) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside NamedSchema objects.
 func (m *NamedSchema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedSchema objects
+// using the session and base URI of the specified context.
+func (m *NamedSchema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5978,9 +6140,15 @@ func (m *NamedSchema) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedSecurityDefinitionsItem objects.
 func (m *NamedSecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedSecurityDefinitionsItem objects
+// using the session and base URI of the specified context.
+func (m *NamedSecurityDefinitionsItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5990,15 +6158,27 @@ func (m *NamedSecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Nod
 
 // ResolveReferences resolves references found inside NamedString objects.
 func (m *NamedString) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedString objects
+// using the session and base URI of the specified context.
+func (m *NamedString) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside NamedStringArray objects.
 func (m *NamedStringArray) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedStringArray objects
+// using the session and base URI of the specified context.
+func (m *NamedStringArray) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -6008,11 +6188,17 @@ func (m *NamedStringArray) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NonBodyParameter objects.
 func (m *NonBodyParameter) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NonBodyParameter objects
+// using the session and base URI of the specified context.
+func (m *NonBodyParameter) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*NonBodyParameter_HeaderParameterSubSchema)
 		if ok {
-			_, err := p.HeaderParameterSubSchema.ResolveReferences(root)
+			_, err := p.HeaderParameterSubSchema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6021,7 +6207,7 @@ func (m *NonBodyParameter) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*NonBodyParameter_FormDataParameterSubSchema)
 		if ok {
-			_, err := p.FormDataParameterSubSchema.ResolveReferences(root)
+			_, err := p.FormDataParameterSubSchema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6030,7 +6216,7 @@ func (m *NonBodyParameter) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*NonBodyParameter_QueryParameterSubSchema)
 		if ok {
-			_, err := p.QueryParameterSubSchema.ResolveReferences(root)
+			_, err := p.QueryParameterSubSchema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6039,7 +6225,7 @@ func (m *NonBodyParameter) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*NonBodyParameter_PathParameterSubSchema)
 		if ok {
-			_, err := p.PathParameterSubSchema.ResolveReferences(root)
+			_, err := p.PathParameterSubSchema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6050,16 +6236,22 @@ func (m *NonBodyParameter) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Oauth2AccessCodeSecurity objects.
 func (m *Oauth2AccessCodeSecurity) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Oauth2AccessCodeSecurity objects
+// using the session and base URI of the specified context.
+func (m *Oauth2AccessCodeSecurity) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Scopes != nil {
-		_, err := m.Scopes.ResolveReferences(root)
+		_, err := m.Scopes.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6070,16 +6262,22 @@ func (m *Oauth2AccessCodeSecurity) ResolveReferences(root string) (*yaml.Node, e
 
 // ResolveReferences resolves references found inside Oauth2ApplicationSecurity objects.
 func (m *Oauth2ApplicationSecurity) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Oauth2ApplicationSecurity objects
+// using the session and base URI of the specified context.
+func (m *Oauth2ApplicationSecurity) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Scopes != nil {
-		_, err := m.Scopes.ResolveReferences(root)
+		_, err := m.Scopes.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6090,16 +6288,22 @@ func (m *Oauth2ApplicationSecurity) ResolveReferences(root string) (*yaml.Node,
 
 // ResolveReferences resolves references found inside Oauth2ImplicitSecurity objects.
 func (m *Oauth2ImplicitSecurity) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Oauth2ImplicitSecurity objects
+// using the session and base URI of the specified context.
+func (m *Oauth2ImplicitSecurity) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Scopes != nil {
-		_, err := m.Scopes.ResolveReferences(root)
+		_, err := m.Scopes.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6110,16 +6314,22 @@ func (m *Oauth2ImplicitSecurity) ResolveReferences(root string) (*yaml.Node, err
 
 // ResolveReferences resolves references found inside Oauth2PasswordSecurity objects.
 func (m *Oauth2PasswordSecurity) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Oauth2PasswordSecurity objects
+// using the session and base URI of the specified context.
+func (m *Oauth2PasswordSecurity) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Scopes != nil {
-		_, err := m.Scopes.ResolveReferences(root)
+		_, err := m.Scopes.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6130,10 +6340,16 @@ func (m *Oauth2PasswordSecurity) ResolveReferences(root string) (*yaml.Node, err
 
 // ResolveReferences resolves references found inside Oauth2Scopes objects.
 func (m *Oauth2Scopes) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Oauth2Scopes objects
+// using the session and base URI of the specified context.
+func (m *Oauth2Scopes) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6144,30 +6360,36 @@ func (m *Oauth2Scopes) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Operation objects.
 func (m *Operation) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Operation objects
+// using the session and base URI of the specified context.
+func (m *Operation) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Parameters {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.Responses != nil {
-		_, err := m.Responses.ResolveReferences(root)
+		_, err := m.Responses.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Security {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6175,7 +6397,7 @@ func (m *Operation) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6186,11 +6408,17 @@ func (m *Operation) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Parameter objects.
 func (m *Parameter) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Parameter objects
+// using the session and base URI of the specified context.
+func (m *Parameter) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*Parameter_BodyParameter)
 		if ok {
-			_, err := p.BodyParameter.ResolveReferences(root)
+			_, err := p.BodyParameter.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6199,7 +6427,7 @@ func (m *Parameter) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*Parameter_NonBodyParameter)
 		if ok {
-			_, err := p.NonBodyParameter.ResolveReferences(root)
+			_, err := p.NonBodyParameter.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6210,10 +6438,16 @@ func (m *Parameter) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ParameterDefinitions objects.
 func (m *ParameterDefinitions) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ParameterDefinitions objects
+// using the session and base URI of the specified context.
+func (m *ParameterDefinitions) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6224,11 +6458,17 @@ func (m *ParameterDefinitions) ResolveReferences(root string) (*yaml.Node, error
 
 // ResolveReferences resolves references found inside ParametersItem objects.
 func (m *ParametersItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ParametersItem objects
+// using the session and base URI of the specified context.
+func (m *ParametersItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*ParametersItem_Parameter)
 		if ok {
-			_, err := p.Parameter.ResolveReferences(root)
+			_, err := p.Parameter.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6237,7 +6477,7 @@ func (m *ParametersItem) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*ParametersItem_JsonReference)
 		if ok {
-			info, err := p.JsonReference.ResolveReferences(root)
+			info, err := p.JsonReference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			} else if info != nil {
@@ -6256,66 +6496,72 @@ func (m *ParametersItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside PathItem objects.
 func (m *PathItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside PathItem objects
+// using the session and base URI of the specified context.
+func (m *PathItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewPathItem(info, nil)
+			replacement, err := NewPathItem(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
 	}
 	if m.Get != nil {
-		_, err := m.Get.ResolveReferences(root)
+		_, err := m.Get.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Put != nil {
-		_, err := m.Put.ResolveReferences(root)
+		_, err := m.Put.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Post != nil {
-		_, err := m.Post.ResolveReferences(root)
+		_, err := m.Post.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Delete != nil {
-		_, err := m.Delete.ResolveReferences(root)
+		_, err := m.Delete.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Options != nil {
-		_, err := m.Options.ResolveReferences(root)
+		_, err := m.Options.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Head != nil {
-		_, err := m.Head.ResolveReferences(root)
+		_, err := m.Head.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Patch != nil {
-		_, err := m.Patch.ResolveReferences(root)
+		_, err := m.Patch.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Parameters {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6323,7 +6569,7 @@ func (m *PathItem) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6334,22 +6580,28 @@ func (m *PathItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside PathParameterSubSchema objects.
 func (m *PathParameterSubSchema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside PathParameterSubSchema objects
+// using the session and base URI of the specified context.
+func (m *PathParameterSubSchema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6357,7 +6609,7 @@ func (m *PathParameterSubSchema) ResolveReferences(root string) (*yaml.Node, err
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6368,10 +6620,16 @@ func (m *PathParameterSubSchema) ResolveReferences(root string) (*yaml.Node, err
 
 // ResolveReferences resolves references found inside Paths objects.
 func (m *Paths) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Paths objects
+// using the session and base URI of the specified context.
+func (m *Paths) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6379,7 +6637,7 @@ func (m *Paths) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.Path {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6390,22 +6648,28 @@ func (m *Paths) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside PrimitivesItems objects.
 func (m *PrimitivesItems) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside PrimitivesItems objects
+// using the session and base URI of the specified context.
+func (m *PrimitivesItems) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6413,7 +6677,7 @@ func (m *PrimitivesItems) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6424,10 +6688,16 @@ func (m *PrimitivesItems) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Properties objects.
 func (m *Properties) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Properties objects
+// using the session and base URI of the specified context.
+func (m *Properties) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6438,22 +6708,28 @@ func (m *Properties) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside QueryParameterSubSchema objects.
 func (m *QueryParameterSubSchema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside QueryParameterSubSchema objects
+// using the session and base URI of the specified context.
+func (m *QueryParameterSubSchema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6461,7 +6737,7 @@ func (m *QueryParameterSubSchema) ResolveReferences(root string) (*yaml.Node, er
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6472,28 +6748,34 @@ func (m *QueryParameterSubSchema) ResolveReferences(root string) (*yaml.Node, er
 
 // ResolveReferences resolves references found inside Response objects.
 func (m *Response) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Response objects
+// using the session and base URI of the specified context.
+func (m *Response) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Schema != nil {
-		_, err := m.Schema.ResolveReferences(root)
+		_, err := m.Schema.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Headers != nil {
-		_, err := m.Headers.ResolveReferences(root)
+		_, err := m.Headers.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Examples != nil {
-		_, err := m.Examples.ResolveReferences(root)
+		_, err := m.Examples.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6504,10 +6786,16 @@ func (m *Response) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ResponseDefinitions objects.
 func (m *ResponseDefinitions) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ResponseDefinitions objects
+// using the session and base URI of the specified context.
+func (m *ResponseDefinitions) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6518,11 +6806,17 @@ func (m *ResponseDefinitions) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside ResponseValue objects.
 func (m *ResponseValue) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ResponseValue objects
+// using the session and base URI of the specified context.
+func (m *ResponseValue) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*ResponseValue_Response)
 		if ok {
-			_, err := p.Response.ResolveReferences(root)
+			_, err := p.Response.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6531,7 +6825,7 @@ func (m *ResponseValue) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*ResponseValue_JsonReference)
 		if ok {
-			info, err := p.JsonReference.ResolveReferences(root)
+			info, err := p.JsonReference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			} else if info != nil {
@@ -6550,10 +6844,16 @@ func (m *ResponseValue) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Responses objects.
 func (m *Responses) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Responses objects
+// using the session and base URI of the specified context.
+func (m *Responses) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.ResponseCode {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6561,7 +6861,7 @@ func (m *Responses) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6572,88 +6872,94 @@ func (m *Responses) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Schema objects.
 func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Schema objects
+// using the session and base URI of the specified context.
+func (m *Schema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewSchema(info, nil)
+			replacement, err := NewSchema(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.AdditionalProperties != nil {
-		_, err := m.AdditionalProperties.ResolveReferences(root)
+		_, err := m.AdditionalProperties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Type != nil {
-		_, err := m.Type.ResolveReferences(root)
+		_, err := m.Type.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.AllOf {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.Properties != nil {
-		_, err := m.Properties.ResolveReferences(root)
+		_, err := m.Properties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Xml != nil {
-		_, err := m.Xml.ResolveReferences(root)
+		_, err := m.Xml.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Example != nil {
-		_, err := m.Example.ResolveReferences(root)
+		_, err := m.Example.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6664,11 +6970,17 @@ func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside SchemaItem objects.
 func (m *SchemaItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SchemaItem objects
+// using the session and base URI of the specified context.
+func (m *SchemaItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*SchemaItem_Schema)
 		if ok {
-			_, err := p.Schema.ResolveReferences(root)
+			_, err := p.Schema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6677,7 +6989,7 @@ func (m *SchemaItem) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*SchemaItem_FileSchema)
 		if ok {
-			_, err := p.FileSchema.ResolveReferences(root)
+			_, err := p.FileSchema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6688,10 +7000,16 @@ func (m *SchemaItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside SecurityDefinitions objects.
 func (m *SecurityDefinitions) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SecurityDefinitions objects
+// using the session and base URI of the specified context.
+func (m *SecurityDefinitions) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6702,11 +7020,17 @@ func (m *SecurityDefinitions) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside SecurityDefinitionsItem objects.
 func (m *SecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SecurityDefinitionsItem objects
+// using the session and base URI of the specified context.
+func (m *SecurityDefinitionsItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*SecurityDefinitionsItem_BasicAuthenticationSecurity)
 		if ok {
-			_, err := p.BasicAuthenticationSecurity.ResolveReferences(root)
+			_, err := p.BasicAuthenticationSecurity.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6715,7 +7039,7 @@ func (m *SecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, er
 	{
 		p, ok := m.Oneof.(*SecurityDefinitionsItem_ApiKeySecurity)
 		if ok {
-			_, err := p.ApiKeySecurity.ResolveReferences(root)
+			_, err := p.ApiKeySecurity.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6724,7 +7048,7 @@ func (m *SecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, er
 	{
 		p, ok := m.Oneof.(*SecurityDefinitionsItem_Oauth2ImplicitSecurity)
 		if ok {
-			_, err := p.Oauth2ImplicitSecurity.ResolveReferences(root)
+			_, err := p.Oauth2ImplicitSecurity.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6733,7 +7057,7 @@ func (m *SecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, er
 	{
 		p, ok := m.Oneof.(*SecurityDefinitionsItem_Oauth2PasswordSecurity)
 		if ok {
-			_, err := p.Oauth2PasswordSecurity.ResolveReferences(root)
+			_, err := p.Oauth2PasswordSecurity.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6742,7 +7066,7 @@ func (m *SecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, er
 	{
 		p, ok := m.Oneof.(*SecurityDefinitionsItem_Oauth2ApplicationSecurity)
 		if ok {
-			_, err := p.Oauth2ApplicationSecurity.ResolveReferences(root)
+			_, err := p.Oauth2ApplicationSecurity.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6751,7 +7075,7 @@ func (m *SecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, er
 	{
 		p, ok := m.Oneof.(*SecurityDefinitionsItem_Oauth2AccessCodeSecurity)
 		if ok {
-			_, err := p.Oauth2AccessCodeSecurity.ResolveReferences(root)
+			_, err := p.Oauth2AccessCodeSecurity.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6762,10 +7086,16 @@ func (m *SecurityDefinitionsItem) ResolveReferences(root string) (*yaml.Node, er
 
 // ResolveReferences resolves references found inside SecurityRequirement objects.
 func (m *SecurityRequirement) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SecurityRequirement objects
+// using the session and base URI of the specified context.
+func (m *SecurityRequirement) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6776,22 +7106,34 @@ func (m *SecurityRequirement) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside StringArray objects.
 func (m *StringArray) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside StringArray objects
+// using the session and base URI of the specified context.
+func (m *StringArray) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside Tag objects.
 func (m *Tag) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Tag objects
+// using the session and base URI of the specified context.
+func (m *Tag) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6802,16 +7144,28 @@ func (m *Tag) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside TypeItem objects.
 func (m *TypeItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside TypeItem objects
+// using the session and base URI of the specified context.
+func (m *TypeItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside VendorExtension objects.
 func (m *VendorExtension) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside VendorExtension objects
+// using the session and base URI of the specified context.
+func (m *VendorExtension) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6822,10 +7176,16 @@ func (m *VendorExtension) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Xml objects.
 func (m *Xml) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Xml objects
+// using the session and base URI of the specified context.
+func (m *Xml) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.VendorExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -17,6 +17,10 @@ package openapi_v2
 import (
 	"io/ioutil"
 	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
 )
 
 func TestParseDocument(t *testing.T) {
@@ -36,3 +40,24 @@ func TestParseDocument(t *testing.T) {
 		t.Errorf("unexpected value for Title: %s (expected %s)", d.Info.Title, title)
 	}
 }
+
+func TestValidateDocument(t *testing.T) {
+	filename := "../examples/v2.0/yaml/petstore.yaml"
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unable to read file %s", filename)
+	}
+	var info yaml.Node
+	if err := yaml.Unmarshal(b, &info); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := ValidateDocument(&info, compiler.NewContext("$root", &info, nil)); err != nil {
+		t.Errorf("unexpected errors: %+v", err)
+	}
+	root := info.Content[0]
+	compiler.MapValueForKey(root, "produces").Content[0].Value = "json"
+	err = ValidateDocument(&info, compiler.NewContext("$root", root, nil))
+	if err == nil || err.Error() != "[14,5] $root.produces.0 has invalid media type: json" {
+		t.Errorf("unexpected errors: %+v", err)
+	}
+}
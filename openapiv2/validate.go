@@ -0,0 +1,272 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v2
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// ValidateDocument checks an OpenAPI v2 description for errors that NewDocument
+// doesn't detect because they don't violate the structure of the OpenAPI schema:
+// duplicate operationIds, operations with more than one body parameter or with
+// both body and formData parameters, invalid media types in produces and consumes
+// lists, response examples for media types that an operation doesn't produce,
+// and security requirements that name undefined security definitions.
+// The description should be the node that was compiled by NewDocument, so that
+// errors are reported with their locations in the source.
+func ValidateDocument(in *yaml.Node, context *compiler.Context) error {
+	if in != nil && in.Kind == yaml.DocumentNode && len(in.Content) > 0 {
+		in = in.Content[0]
+	}
+	v := &validator{root: in, definitions: make(map[string]bool)}
+	compiler.ForEachEntry(compiler.MapValueForKey(in, "securityDefinitions"), func(name string, _ *yaml.Node) {
+		v.definitions[name] = true
+	})
+	v.produces = v.mediaTypes(compiler.MapValueForKey(in, "produces"), "produces", context)
+	v.consumes = v.mediaTypes(compiler.MapValueForKey(in, "consumes"), "consumes", context)
+	v.validatePaths(compiler.MapValueForKey(in, "paths"), context)
+	if security := compiler.MapValueForKey(in, "security"); security != nil {
+		v.validateSecurity(security, compiler.NewContext("security", security, context))
+	}
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		a, b := v.errors[i].(*compiler.Error).Context.Node, v.errors[j].(*compiler.Error).Context.Node
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return compiler.NewErrorGroupOrNil(v.errors)
+}
+
+// validator collects the errors found by ValidateDocument.
+type validator struct {
+	root *yaml.Node
+	// definitions holds the names of the document's security definitions.
+	definitions map[string]bool
+	// produces and consumes hold the document's default media types.
+	produces []string
+	consumes []string
+	errors   []error
+}
+
+// parameter describes an operation parameter.
+type parameter struct {
+	name    string
+	in      string
+	context *compiler.Context
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// formMediaTypes are the media types that can be consumed by operations with formData parameters.
+var formMediaTypes = []string{"application/x-www-form-urlencoded", "multipart/form-data"}
+
+func (v *validator) addError(context *compiler.Context, message string) {
+	v.errors = append(v.errors, compiler.NewError(context, message))
+}
+
+// validatePaths checks the operationIds, parameters, media types, and security requirements of each operation.
+func (v *validator) validatePaths(paths *yaml.Node, context *compiler.Context) {
+	pathsContext := compiler.NewContext("paths", paths, context)
+	operationIDs := make(map[string]string)
+	compiler.ForEachEntry(paths, func(path string, item *yaml.Node) {
+		itemContext := compiler.NewContext(path, item, pathsContext)
+		pathParameters := v.parameters(compiler.MapValueForKey(item, "parameters"), itemContext)
+		for _, method := range methods {
+			operation := compiler.MapValueForKey(item, method)
+			if operation == nil {
+				continue
+			}
+			operationContext := compiler.NewContext(method, operation, itemContext)
+			if id := compiler.MapValueForKey(operation, "operationId"); id != nil {
+				if other, ok := operationIDs[id.Value]; ok {
+					v.addError(compiler.NewContext("operationId", id, operationContext),
+						"has duplicate operationId: "+id.Value+" (also used by "+other+")")
+				} else {
+					operationIDs[id.Value] = operationContext.Description()
+				}
+			}
+			// Operation parameters override path parameters with the same name and location.
+			parameters := make([]*parameter, 0)
+			overridden := make(map[string]bool)
+			for _, p := range v.parameters(compiler.MapValueForKey(operation, "parameters"), operationContext) {
+				parameters = append(parameters, p)
+				overridden[p.in+":"+p.name] = true
+			}
+			for _, p := range pathParameters {
+				if !overridden[p.in+":"+p.name] {
+					parameters = append(parameters, p)
+				}
+			}
+			produces := v.produces
+			if node := compiler.MapValueForKey(operation, "produces"); node != nil {
+				produces = v.mediaTypes(node, "produces", operationContext)
+			}
+			consumes := v.consumes
+			if node := compiler.MapValueForKey(operation, "consumes"); node != nil {
+				consumes = v.mediaTypes(node, "consumes", operationContext)
+			}
+			v.validateParameters(parameters, consumes, operationContext)
+			v.validateResponses(compiler.MapValueForKey(operation, "responses"), produces, operationContext)
+			if security := compiler.MapValueForKey(operation, "security"); security != nil {
+				v.validateSecurity(security, compiler.NewContext("security", security, operationContext))
+			}
+		}
+	})
+}
+
+// parameters returns the parameters in a list, resolving references to parameter definitions.
+func (v *validator) parameters(list *yaml.Node, context *compiler.Context) []*parameter {
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil
+	}
+	listContext := compiler.NewContext("parameters", list, context)
+	parameters := make([]*parameter, 0)
+	for i, item := range list.Content {
+		itemContext := compiler.NewContext(strconv.Itoa(i), item, listContext)
+		if compiler.MapHasKey(item, "$ref") {
+			if item = v.resolve(item); item == nil {
+				continue
+			}
+		}
+		name, _ := compiler.StringForScalarNode(compiler.MapValueForKey(item, "name"))
+		in, _ := compiler.StringForScalarNode(compiler.MapValueForKey(item, "in"))
+		parameters = append(parameters, &parameter{name: name, in: in, context: itemContext})
+	}
+	return parameters
+}
+
+// validateParameters checks that an operation has at most one body parameter,
+// that it doesn't have both body and formData parameters, and that operations
+// with formData parameters consume form media types.
+func (v *validator) validateParameters(parameters []*parameter, consumes []string, context *compiler.Context) {
+	sort.SliceStable(parameters, func(i, j int) bool {
+		a, b := parameters[i].context.Node, parameters[j].context.Node
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	var body, formData *parameter
+	for _, p := range parameters {
+		switch p.in {
+		case "body":
+			if body != nil {
+				v.addError(p.context, "has more than one body parameter: "+p.name+" (also has "+body.name+")")
+				continue
+			}
+			body = p
+		case "formData":
+			if formData == nil {
+				formData = p
+			}
+		}
+	}
+	if body != nil && formData != nil {
+		v.addError(context, "has both body and formData parameters: "+body.name+", "+formData.name)
+	}
+	if formData != nil && len(consumes) > 0 && !containsAny(consumes, formMediaTypes) {
+		v.addError(context, "has formData parameter but doesn't consume "+strings.Join(formMediaTypes, " or ")+": "+formData.name)
+	}
+}
+
+// validateResponses checks that response examples are for media types that an operation
+// produces, if it declares the media types that it produces.
+func (v *validator) validateResponses(responses *yaml.Node, produces []string, context *compiler.Context) {
+	responsesContext := compiler.NewContext("responses", responses, context)
+	compiler.ForEachEntry(responses, func(code string, response *yaml.Node) {
+		responseContext := compiler.NewContext(code, response, responsesContext)
+		if compiler.MapHasKey(response, "$ref") {
+			if response = v.resolve(response); response == nil {
+				return
+			}
+		}
+		examples := compiler.MapValueForKey(response, "examples")
+		examplesContext := compiler.NewContext("examples", examples, responseContext)
+		compiler.ForEachEntry(examples, func(mediaType string, example *yaml.Node) {
+			if len(produces) > 0 && !containsAny(produces, []string{mediaType}) {
+				v.addError(compiler.NewContext(mediaType, example, examplesContext),
+					"has example for media type that isn't produced: "+mediaType)
+			}
+		})
+	})
+}
+
+// mediaTypes returns the media types in a produces or consumes list, reporting any that are invalid.
+func (v *validator) mediaTypes(list *yaml.Node, name string, context *compiler.Context) []string {
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil
+	}
+	listContext := compiler.NewContext(name, list, context)
+	mediaTypes := make([]string, 0)
+	for i, item := range list.Content {
+		if mediaType, _, err := mime.ParseMediaType(item.Value); err != nil || !strings.Contains(mediaType, "/") {
+			v.addError(compiler.NewContext(strconv.Itoa(i), item, listContext), "has invalid media type: "+item.Value)
+			continue
+		}
+		mediaTypes = append(mediaTypes, item.Value)
+	}
+	return mediaTypes
+}
+
+// validateSecurity checks that security requirements name defined security definitions.
+func (v *validator) validateSecurity(security *yaml.Node, context *compiler.Context) {
+	if security.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, requirement := range security.Content {
+		if requirement.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(requirement.Content)-1; j += 2 {
+			name := requirement.Content[j]
+			if !v.definitions[name.Value] {
+				v.addError(compiler.NewContext(strconv.Itoa(i), name, context),
+					"refers to undefined security definition: "+name.Value)
+			}
+		}
+	}
+}
+
+// resolve returns the target of a local reference or nil if it can't be resolved.
+// References to other files are not resolved.
+func (v *validator) resolve(node *yaml.Node) *yaml.Node {
+	ref, _ := compiler.StringForScalarNode(compiler.MapValueForKey(node, "$ref"))
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	target, err := compiler.NodeForPointer(v.root, compiler.PointerForFragment(ref[1:]))
+	if err != nil {
+		return nil
+	}
+	return target
+}
+
+// containsAny returns true if any of the candidates match a list of media types.
+// Media types are compared without their parameters and can have wildcards,
+// such as "*/*" and "text/*".
+func containsAny(mediaTypes []string, candidates []string) bool {
+	for _, m := range mediaTypes {
+		m, _, _ := mime.ParseMediaType(m)
+		for _, c := range candidates {
+			c, _, _ := mime.ParseMediaType(c)
+			if m == c || m == "*/*" || strings.HasSuffix(m, "/*") && strings.HasPrefix(c, strings.TrimSuffix(m, "*")) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -5030,11 +5030,17 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 
 // ResolveReferences resolves references found inside AdditionalPropertiesItem objects.
 func (m *AdditionalPropertiesItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside AdditionalPropertiesItem objects
+// using the session and base URI of the specified context.
+func (m *AdditionalPropertiesItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*AdditionalPropertiesItem_SchemaOrReference)
 		if ok {
-			_, err := p.SchemaOrReference.ResolveReferences(root)
+			_, err := p.SchemaOrReference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5045,17 +5051,29 @@ func (m *AdditionalPropertiesItem) ResolveReferences(root string) (*yaml.Node, e
 
 // ResolveReferences resolves references found inside Any objects.
 func (m *Any) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Any objects
+// using the session and base URI of the specified context.
+func (m *Any) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside AnyOrExpression objects.
 func (m *AnyOrExpression) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside AnyOrExpression objects
+// using the session and base URI of the specified context.
+func (m *AnyOrExpression) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*AnyOrExpression_Any)
 		if ok {
-			_, err := p.Any.ResolveReferences(root)
+			_, err := p.Any.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5064,7 +5082,7 @@ func (m *AnyOrExpression) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*AnyOrExpression_Expression)
 		if ok {
-			_, err := p.Expression.ResolveReferences(root)
+			_, err := p.Expression.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5075,10 +5093,16 @@ func (m *AnyOrExpression) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Callback objects.
 func (m *Callback) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Callback objects
+// using the session and base URI of the specified context.
+func (m *Callback) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.Path {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5086,7 +5110,7 @@ func (m *Callback) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5097,11 +5121,17 @@ func (m *Callback) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside CallbackOrReference objects.
 func (m *CallbackOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside CallbackOrReference objects
+// using the session and base URI of the specified context.
+func (m *CallbackOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*CallbackOrReference_Callback)
 		if ok {
-			_, err := p.Callback.ResolveReferences(root)
+			_, err := p.Callback.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5110,7 +5140,7 @@ func (m *CallbackOrReference) ResolveReferences(root string) (*yaml.Node, error)
 	{
 		p, ok := m.Oneof.(*CallbackOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5121,10 +5151,16 @@ func (m *CallbackOrReference) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside CallbacksOrReferences objects.
 func (m *CallbacksOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside CallbacksOrReferences objects
+// using the session and base URI of the specified context.
+func (m *CallbacksOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5135,64 +5171,70 @@ func (m *CallbacksOrReferences) ResolveReferences(root string) (*yaml.Node, erro
 
 // ResolveReferences resolves references found inside Components objects.
 func (m *Components) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Components objects
+// using the session and base URI of the specified context.
+func (m *Components) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Schemas != nil {
-		_, err := m.Schemas.ResolveReferences(root)
+		_, err := m.Schemas.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Responses != nil {
-		_, err := m.Responses.ResolveReferences(root)
+		_, err := m.Responses.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Parameters != nil {
-		_, err := m.Parameters.ResolveReferences(root)
+		_, err := m.Parameters.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Examples != nil {
-		_, err := m.Examples.ResolveReferences(root)
+		_, err := m.Examples.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.RequestBodies != nil {
-		_, err := m.RequestBodies.ResolveReferences(root)
+		_, err := m.RequestBodies.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Headers != nil {
-		_, err := m.Headers.ResolveReferences(root)
+		_, err := m.Headers.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.SecuritySchemes != nil {
-		_, err := m.SecuritySchemes.ResolveReferences(root)
+		_, err := m.SecuritySchemes.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Links != nil {
-		_, err := m.Links.ResolveReferences(root)
+		_, err := m.Links.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Callbacks != nil {
-		_, err := m.Callbacks.ResolveReferences(root)
+		_, err := m.Callbacks.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5203,10 +5245,16 @@ func (m *Components) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Contact objects.
 func (m *Contact) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Contact objects
+// using the session and base URI of the specified context.
+func (m *Contact) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5217,22 +5265,34 @@ func (m *Contact) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside DefaultType objects.
 func (m *DefaultType) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside DefaultType objects
+// using the session and base URI of the specified context.
+func (m *DefaultType) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside Discriminator objects.
 func (m *Discriminator) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Discriminator objects
+// using the session and base URI of the specified context.
+func (m *Discriminator) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Mapping != nil {
-		_, err := m.Mapping.ResolveReferences(root)
+		_, err := m.Mapping.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5243,36 +5303,42 @@ func (m *Discriminator) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Document objects.
 func (m *Document) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Document objects
+// using the session and base URI of the specified context.
+func (m *Document) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Info != nil {
-		_, err := m.Info.ResolveReferences(root)
+		_, err := m.Info.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Servers {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.Paths != nil {
-		_, err := m.Paths.ResolveReferences(root)
+		_, err := m.Paths.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Components != nil {
-		_, err := m.Components.ResolveReferences(root)
+		_, err := m.Components.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Security {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5280,21 +5346,21 @@ func (m *Document) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.Tags {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5305,16 +5371,22 @@ func (m *Document) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Encoding objects.
 func (m *Encoding) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Encoding objects
+// using the session and base URI of the specified context.
+func (m *Encoding) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Headers != nil {
-		_, err := m.Headers.ResolveReferences(root)
+		_, err := m.Headers.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5325,10 +5397,16 @@ func (m *Encoding) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Encodings objects.
 func (m *Encodings) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Encodings objects
+// using the session and base URI of the specified context.
+func (m *Encodings) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5339,16 +5417,22 @@ func (m *Encodings) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Example objects.
 func (m *Example) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Example objects
+// using the session and base URI of the specified context.
+func (m *Example) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5359,11 +5443,17 @@ func (m *Example) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ExampleOrReference objects.
 func (m *ExampleOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ExampleOrReference objects
+// using the session and base URI of the specified context.
+func (m *ExampleOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*ExampleOrReference_Example)
 		if ok {
-			_, err := p.Example.ResolveReferences(root)
+			_, err := p.Example.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5372,7 +5462,7 @@ func (m *ExampleOrReference) ResolveReferences(root string) (*yaml.Node, error)
 	{
 		p, ok := m.Oneof.(*ExampleOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5383,10 +5473,16 @@ func (m *ExampleOrReference) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside ExamplesOrReferences objects.
 func (m *ExamplesOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ExamplesOrReferences objects
+// using the session and base URI of the specified context.
+func (m *ExamplesOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5397,10 +5493,16 @@ func (m *ExamplesOrReferences) ResolveReferences(root string) (*yaml.Node, error
 
 // ResolveReferences resolves references found inside Expression objects.
 func (m *Expression) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Expression objects
+// using the session and base URI of the specified context.
+func (m *Expression) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5411,10 +5513,16 @@ func (m *Expression) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ExternalDocs objects.
 func (m *ExternalDocs) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ExternalDocs objects
+// using the session and base URI of the specified context.
+func (m *ExternalDocs) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5425,34 +5533,40 @@ func (m *ExternalDocs) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Header objects.
 func (m *Header) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Header objects
+// using the session and base URI of the specified context.
+func (m *Header) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Schema != nil {
-		_, err := m.Schema.ResolveReferences(root)
+		_, err := m.Schema.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Example != nil {
-		_, err := m.Example.ResolveReferences(root)
+		_, err := m.Example.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Examples != nil {
-		_, err := m.Examples.ResolveReferences(root)
+		_, err := m.Examples.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Content != nil {
-		_, err := m.Content.ResolveReferences(root)
+		_, err := m.Content.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5463,11 +5577,17 @@ func (m *Header) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside HeaderOrReference objects.
 func (m *HeaderOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside HeaderOrReference objects
+// using the session and base URI of the specified context.
+func (m *HeaderOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*HeaderOrReference_Header)
 		if ok {
-			_, err := p.Header.ResolveReferences(root)
+			_, err := p.Header.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5476,7 +5596,7 @@ func (m *HeaderOrReference) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*HeaderOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5487,10 +5607,16 @@ func (m *HeaderOrReference) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside HeadersOrReferences objects.
 func (m *HeadersOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside HeadersOrReferences objects
+// using the session and base URI of the specified context.
+func (m *HeadersOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5501,22 +5627,28 @@ func (m *HeadersOrReferences) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside Info objects.
 func (m *Info) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Info objects
+// using the session and base URI of the specified context.
+func (m *Info) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Contact != nil {
-		_, err := m.Contact.ResolveReferences(root)
+		_, err := m.Contact.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.License != nil {
-		_, err := m.License.ResolveReferences(root)
+		_, err := m.License.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5527,10 +5659,16 @@ func (m *Info) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ItemsItem objects.
 func (m *ItemsItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ItemsItem objects
+// using the session and base URI of the specified context.
+func (m *ItemsItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.SchemaOrReference {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5541,10 +5679,16 @@ func (m *ItemsItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside License objects.
 func (m *License) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside License objects
+// using the session and base URI of the specified context.
+func (m *License) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5555,28 +5699,34 @@ func (m *License) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Link objects.
 func (m *Link) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Link objects
+// using the session and base URI of the specified context.
+func (m *Link) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Parameters != nil {
-		_, err := m.Parameters.ResolveReferences(root)
+		_, err := m.Parameters.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.RequestBody != nil {
-		_, err := m.RequestBody.ResolveReferences(root)
+		_, err := m.RequestBody.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Server != nil {
-		_, err := m.Server.ResolveReferences(root)
+		_, err := m.Server.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5587,11 +5737,17 @@ func (m *Link) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside LinkOrReference objects.
 func (m *LinkOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside LinkOrReference objects
+// using the session and base URI of the specified context.
+func (m *LinkOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*LinkOrReference_Link)
 		if ok {
-			_, err := p.Link.ResolveReferences(root)
+			_, err := p.Link.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5600,7 +5756,7 @@ func (m *LinkOrReference) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*LinkOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -5611,10 +5767,16 @@ func (m *LinkOrReference) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside LinksOrReferences objects.
 func (m *LinksOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside LinksOrReferences objects
+// using the session and base URI of the specified context.
+func (m *LinksOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5625,34 +5787,40 @@ func (m *LinksOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside MediaType objects.
 func (m *MediaType) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside MediaType objects
+// using the session and base URI of the specified context.
+func (m *MediaType) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Schema != nil {
-		_, err := m.Schema.ResolveReferences(root)
+		_, err := m.Schema.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Example != nil {
-		_, err := m.Example.ResolveReferences(root)
+		_, err := m.Example.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Examples != nil {
-		_, err := m.Examples.ResolveReferences(root)
+		_, err := m.Examples.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Encoding != nil {
-		_, err := m.Encoding.ResolveReferences(root)
+		_, err := m.Encoding.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5663,10 +5831,16 @@ func (m *MediaType) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside MediaTypes objects.
 func (m *MediaTypes) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside MediaTypes objects
+// using the session and base URI of the specified context.
+func (m *MediaTypes) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5677,9 +5851,15 @@ func (m *MediaTypes) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedAny objects.
 func (m *NamedAny) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedAny objects
+// using the session and base URI of the specified context.
+func (m *NamedAny) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5689,9 +5869,15 @@ func (m *NamedAny) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedCallbackOrReference objects.
 func (m *NamedCallbackOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedCallbackOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedCallbackOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5701,9 +5887,15 @@ func (m *NamedCallbackOrReference) ResolveReferences(root string) (*yaml.Node, e
 
 // ResolveReferences resolves references found inside NamedEncoding objects.
 func (m *NamedEncoding) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedEncoding objects
+// using the session and base URI of the specified context.
+func (m *NamedEncoding) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5713,9 +5905,15 @@ func (m *NamedEncoding) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedExampleOrReference objects.
 func (m *NamedExampleOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedExampleOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedExampleOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5725,9 +5923,15 @@ func (m *NamedExampleOrReference) ResolveReferences(root string) (*yaml.Node, er
 
 // ResolveReferences resolves references found inside NamedHeaderOrReference objects.
 func (m *NamedHeaderOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedHeaderOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedHeaderOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5737,9 +5941,15 @@ func (m *NamedHeaderOrReference) ResolveReferences(root string) (*yaml.Node, err
 
 // ResolveReferences resolves references found inside NamedLinkOrReference objects.
 func (m *NamedLinkOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedLinkOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedLinkOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5749,9 +5959,15 @@ func (m *NamedLinkOrReference) ResolveReferences(root string) (*yaml.Node, error
 
 // ResolveReferences resolves references found inside NamedMediaType objects.
 func (m *NamedMediaType) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedMediaType objects
+// using the session and base URI of the specified context.
+func (m *NamedMediaType) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5761,9 +5977,15 @@ func (m *NamedMediaType) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedParameterOrReference objects.
 func (m *NamedParameterOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedParameterOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedParameterOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5773,9 +5995,15 @@ func (m *NamedParameterOrReference) ResolveReferences(root string) (*yaml.Node,
 
 // ResolveReferences resolves references found inside NamedPathItem objects.
 func (m *NamedPathItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedPathItem objects
+// using the session and base URI of the specified context.
+func (m *NamedPathItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5785,9 +6013,15 @@ func (m *NamedPathItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside NamedRequestBodyOrReference objects.
 func (m *NamedRequestBodyOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedRequestBodyOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedRequestBodyOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5797,9 +6031,15 @@ func (m *NamedRequestBodyOrReference) ResolveReferences(root string) (*yaml.Node
 
 // ResolveReferences resolves references found inside NamedResponseOrReference objects.
 func (m *NamedResponseOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedResponseOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedResponseOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5809,9 +6049,15 @@ func (m *NamedResponseOrReference) ResolveReferences(root string) (*yaml.Node, e
 
 // ResolveReferences resolves references found inside NamedSchemaOrReference objects.
 func (m *NamedSchemaOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedSchemaOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedSchemaOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5821,9 +6067,15 @@ func (m *NamedSchemaOrReference) ResolveReferences(root string) (*yaml.Node, err
 
 // ResolveReferences resolves references found inside NamedSecuritySchemeOrReference objects.
 func (m *NamedSecuritySchemeOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedSecuritySchemeOrReference objects
+// using the session and base URI of the specified context.
+func (m *NamedSecuritySchemeOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5833,9 +6085,15 @@ func (m *NamedSecuritySchemeOrReference) ResolveReferences(root string) (*yaml.N
 
 // ResolveReferences resolves references found inside NamedServerVariable objects.
 func (m *NamedServerVariable) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedServerVariable objects
+// using the session and base URI of the specified context.
+func (m *NamedServerVariable) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5845,15 +6103,27 @@ func (m *NamedServerVariable) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside NamedString objects.
 func (m *NamedString) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedString objects
+// using the session and base URI of the specified context.
+func (m *NamedString) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside NamedStringArray objects.
 func (m *NamedStringArray) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside NamedStringArray objects
+// using the session and base URI of the specified context.
+func (m *NamedStringArray) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Value != nil {
-		_, err := m.Value.ResolveReferences(root)
+		_, err := m.Value.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -5863,16 +6133,22 @@ func (m *NamedStringArray) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside OauthFlow objects.
 func (m *OauthFlow) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside OauthFlow objects
+// using the session and base URI of the specified context.
+func (m *OauthFlow) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Scopes != nil {
-		_, err := m.Scopes.ResolveReferences(root)
+		_, err := m.Scopes.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5883,34 +6159,40 @@ func (m *OauthFlow) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside OauthFlows objects.
 func (m *OauthFlows) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside OauthFlows objects
+// using the session and base URI of the specified context.
+func (m *OauthFlows) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Implicit != nil {
-		_, err := m.Implicit.ResolveReferences(root)
+		_, err := m.Implicit.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Password != nil {
-		_, err := m.Password.ResolveReferences(root)
+		_, err := m.Password.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.ClientCredentials != nil {
-		_, err := m.ClientCredentials.ResolveReferences(root)
+		_, err := m.ClientCredentials.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.AuthorizationCode != nil {
-		_, err := m.AuthorizationCode.ResolveReferences(root)
+		_, err := m.AuthorizationCode.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5921,10 +6203,16 @@ func (m *OauthFlows) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Object objects.
 func (m *Object) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Object objects
+// using the session and base URI of the specified context.
+func (m *Object) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5935,42 +6223,48 @@ func (m *Object) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Operation objects.
 func (m *Operation) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Operation objects
+// using the session and base URI of the specified context.
+func (m *Operation) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Parameters {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.RequestBody != nil {
-		_, err := m.RequestBody.ResolveReferences(root)
+		_, err := m.RequestBody.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Responses != nil {
-		_, err := m.Responses.ResolveReferences(root)
+		_, err := m.Responses.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Callbacks != nil {
-		_, err := m.Callbacks.ResolveReferences(root)
+		_, err := m.Callbacks.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Security {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5978,7 +6272,7 @@ func (m *Operation) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.Servers {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5986,7 +6280,7 @@ func (m *Operation) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -5997,34 +6291,40 @@ func (m *Operation) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Parameter objects.
 func (m *Parameter) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Parameter objects
+// using the session and base URI of the specified context.
+func (m *Parameter) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Schema != nil {
-		_, err := m.Schema.ResolveReferences(root)
+		_, err := m.Schema.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Example != nil {
-		_, err := m.Example.ResolveReferences(root)
+		_, err := m.Example.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Examples != nil {
-		_, err := m.Examples.ResolveReferences(root)
+		_, err := m.Examples.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Content != nil {
-		_, err := m.Content.ResolveReferences(root)
+		_, err := m.Content.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6035,11 +6335,17 @@ func (m *Parameter) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ParameterOrReference objects.
 func (m *ParameterOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ParameterOrReference objects
+// using the session and base URI of the specified context.
+func (m *ParameterOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*ParameterOrReference_Parameter)
 		if ok {
-			_, err := p.Parameter.ResolveReferences(root)
+			_, err := p.Parameter.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6048,7 +6354,7 @@ func (m *ParameterOrReference) ResolveReferences(root string) (*yaml.Node, error
 	{
 		p, ok := m.Oneof.(*ParameterOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6059,10 +6365,16 @@ func (m *ParameterOrReference) ResolveReferences(root string) (*yaml.Node, error
 
 // ResolveReferences resolves references found inside ParametersOrReferences objects.
 func (m *ParametersOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ParametersOrReferences objects
+// using the session and base URI of the specified context.
+func (m *ParametersOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6073,72 +6385,78 @@ func (m *ParametersOrReferences) ResolveReferences(root string) (*yaml.Node, err
 
 // ResolveReferences resolves references found inside PathItem objects.
 func (m *PathItem) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside PathItem objects
+// using the session and base URI of the specified context.
+func (m *PathItem) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewPathItem(info, nil)
+			replacement, err := NewPathItem(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
 	}
 	if m.Get != nil {
-		_, err := m.Get.ResolveReferences(root)
+		_, err := m.Get.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Put != nil {
-		_, err := m.Put.ResolveReferences(root)
+		_, err := m.Put.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Post != nil {
-		_, err := m.Post.ResolveReferences(root)
+		_, err := m.Post.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Delete != nil {
-		_, err := m.Delete.ResolveReferences(root)
+		_, err := m.Delete.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Options != nil {
-		_, err := m.Options.ResolveReferences(root)
+		_, err := m.Options.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Head != nil {
-		_, err := m.Head.ResolveReferences(root)
+		_, err := m.Head.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Patch != nil {
-		_, err := m.Patch.ResolveReferences(root)
+		_, err := m.Patch.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Trace != nil {
-		_, err := m.Trace.ResolveReferences(root)
+		_, err := m.Trace.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Servers {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6146,7 +6464,7 @@ func (m *PathItem) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.Parameters {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6154,7 +6472,7 @@ func (m *PathItem) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6165,10 +6483,16 @@ func (m *PathItem) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Paths objects.
 func (m *Paths) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Paths objects
+// using the session and base URI of the specified context.
+func (m *Paths) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.Path {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6176,7 +6500,7 @@ func (m *Paths) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6187,10 +6511,16 @@ func (m *Paths) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Properties objects.
 func (m *Properties) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Properties objects
+// using the session and base URI of the specified context.
+func (m *Properties) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6201,17 +6531,23 @@ func (m *Properties) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Reference objects.
 func (m *Reference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Reference objects
+// using the session and base URI of the specified context.
+func (m *Reference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.XRef != "" {
-		info, err := compiler.ReadInfoForRef(root, m.XRef)
+		info, refContext, err := context.ReadInfoForRef(m.XRef)
 		if err != nil {
 			return nil, err
 		}
 		if info != nil {
-			replacement, err := NewReference(info, nil)
+			replacement, err := NewReference(info, refContext)
 			if err == nil {
 				*m = *replacement
-				return m.ResolveReferences(root)
+				return m.ResolveReferencesInContext(refContext)
 			}
 		}
 		return info, nil
@@ -6221,10 +6557,16 @@ func (m *Reference) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside RequestBodiesOrReferences objects.
 func (m *RequestBodiesOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside RequestBodiesOrReferences objects
+// using the session and base URI of the specified context.
+func (m *RequestBodiesOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6235,16 +6577,22 @@ func (m *RequestBodiesOrReferences) ResolveReferences(root string) (*yaml.Node,
 
 // ResolveReferences resolves references found inside RequestBody objects.
 func (m *RequestBody) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside RequestBody objects
+// using the session and base URI of the specified context.
+func (m *RequestBody) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Content != nil {
-		_, err := m.Content.ResolveReferences(root)
+		_, err := m.Content.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6255,11 +6603,17 @@ func (m *RequestBody) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside RequestBodyOrReference objects.
 func (m *RequestBodyOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside RequestBodyOrReference objects
+// using the session and base URI of the specified context.
+func (m *RequestBodyOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*RequestBodyOrReference_RequestBody)
 		if ok {
-			_, err := p.RequestBody.ResolveReferences(root)
+			_, err := p.RequestBody.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6268,7 +6622,7 @@ func (m *RequestBodyOrReference) ResolveReferences(root string) (*yaml.Node, err
 	{
 		p, ok := m.Oneof.(*RequestBodyOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6279,28 +6633,34 @@ func (m *RequestBodyOrReference) ResolveReferences(root string) (*yaml.Node, err
 
 // ResolveReferences resolves references found inside Response objects.
 func (m *Response) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Response objects
+// using the session and base URI of the specified context.
+func (m *Response) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Headers != nil {
-		_, err := m.Headers.ResolveReferences(root)
+		_, err := m.Headers.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Content != nil {
-		_, err := m.Content.ResolveReferences(root)
+		_, err := m.Content.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Links != nil {
-		_, err := m.Links.ResolveReferences(root)
+		_, err := m.Links.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6311,11 +6671,17 @@ func (m *Response) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ResponseOrReference objects.
 func (m *ResponseOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ResponseOrReference objects
+// using the session and base URI of the specified context.
+func (m *ResponseOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*ResponseOrReference_Response)
 		if ok {
-			_, err := p.Response.ResolveReferences(root)
+			_, err := p.Response.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6324,7 +6690,7 @@ func (m *ResponseOrReference) ResolveReferences(root string) (*yaml.Node, error)
 	{
 		p, ok := m.Oneof.(*ResponseOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6335,16 +6701,22 @@ func (m *ResponseOrReference) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside Responses objects.
 func (m *Responses) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Responses objects
+// using the session and base URI of the specified context.
+func (m *Responses) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.ResponseOrReference {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6352,7 +6724,7 @@ func (m *Responses) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6363,10 +6735,16 @@ func (m *Responses) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ResponsesOrReferences objects.
 func (m *ResponsesOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ResponsesOrReferences objects
+// using the session and base URI of the specified context.
+func (m *ResponsesOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6377,34 +6755,40 @@ func (m *ResponsesOrReferences) ResolveReferences(root string) (*yaml.Node, erro
 
 // ResolveReferences resolves references found inside Schema objects.
 func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Schema objects
+// using the session and base URI of the specified context.
+func (m *Schema) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Discriminator != nil {
-		_, err := m.Discriminator.ResolveReferences(root)
+		_, err := m.Discriminator.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Xml != nil {
-		_, err := m.Xml.ResolveReferences(root)
+		_, err := m.Xml.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Example != nil {
-		_, err := m.Example.ResolveReferences(root)
+		_, err := m.Example.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.Enum {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6412,7 +6796,7 @@ func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.AllOf {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6420,7 +6804,7 @@ func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.OneOf {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6428,45 +6812,45 @@ func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
 	}
 	for _, item := range m.AnyOf {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
 	}
 	if m.Not != nil {
-		_, err := m.Not.ResolveReferences(root)
+		_, err := m.Not.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Items != nil {
-		_, err := m.Items.ResolveReferences(root)
+		_, err := m.Items.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Properties != nil {
-		_, err := m.Properties.ResolveReferences(root)
+		_, err := m.Properties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.AdditionalProperties != nil {
-		_, err := m.AdditionalProperties.ResolveReferences(root)
+		_, err := m.AdditionalProperties.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if m.Default != nil {
-		_, err := m.Default.ResolveReferences(root)
+		_, err := m.Default.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6477,11 +6861,17 @@ func (m *Schema) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside SchemaOrReference objects.
 func (m *SchemaOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SchemaOrReference objects
+// using the session and base URI of the specified context.
+func (m *SchemaOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*SchemaOrReference_Schema)
 		if ok {
-			_, err := p.Schema.ResolveReferences(root)
+			_, err := p.Schema.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6490,7 +6880,7 @@ func (m *SchemaOrReference) ResolveReferences(root string) (*yaml.Node, error) {
 	{
 		p, ok := m.Oneof.(*SchemaOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6501,10 +6891,16 @@ func (m *SchemaOrReference) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside SchemasOrReferences objects.
 func (m *SchemasOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SchemasOrReferences objects
+// using the session and base URI of the specified context.
+func (m *SchemasOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6515,10 +6911,16 @@ func (m *SchemasOrReferences) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside SecurityRequirement objects.
 func (m *SecurityRequirement) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SecurityRequirement objects
+// using the session and base URI of the specified context.
+func (m *SecurityRequirement) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6529,16 +6931,22 @@ func (m *SecurityRequirement) ResolveReferences(root string) (*yaml.Node, error)
 
 // ResolveReferences resolves references found inside SecurityScheme objects.
 func (m *SecurityScheme) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SecurityScheme objects
+// using the session and base URI of the specified context.
+func (m *SecurityScheme) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Flows != nil {
-		_, err := m.Flows.ResolveReferences(root)
+		_, err := m.Flows.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6549,11 +6957,17 @@ func (m *SecurityScheme) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside SecuritySchemeOrReference objects.
 func (m *SecuritySchemeOrReference) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SecuritySchemeOrReference objects
+// using the session and base URI of the specified context.
+func (m *SecuritySchemeOrReference) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	{
 		p, ok := m.Oneof.(*SecuritySchemeOrReference_SecurityScheme)
 		if ok {
-			_, err := p.SecurityScheme.ResolveReferences(root)
+			_, err := p.SecurityScheme.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6562,7 +6976,7 @@ func (m *SecuritySchemeOrReference) ResolveReferences(root string) (*yaml.Node,
 	{
 		p, ok := m.Oneof.(*SecuritySchemeOrReference_Reference)
 		if ok {
-			_, err := p.Reference.ResolveReferences(root)
+			_, err := p.Reference.ResolveReferencesInContext(context)
 			if err != nil {
 				return nil, err
 			}
@@ -6573,10 +6987,16 @@ func (m *SecuritySchemeOrReference) ResolveReferences(root string) (*yaml.Node,
 
 // ResolveReferences resolves references found inside SecuritySchemesOrReferences objects.
 func (m *SecuritySchemesOrReferences) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SecuritySchemesOrReferences objects
+// using the session and base URI of the specified context.
+func (m *SecuritySchemesOrReferences) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6587,16 +7007,22 @@ func (m *SecuritySchemesOrReferences) ResolveReferences(root string) (*yaml.Node
 
 // ResolveReferences resolves references found inside Server objects.
 func (m *Server) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Server objects
+// using the session and base URI of the specified context.
+func (m *Server) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.Variables != nil {
-		_, err := m.Variables.ResolveReferences(root)
+		_, err := m.Variables.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6607,10 +7033,16 @@ func (m *Server) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ServerVariable objects.
 func (m *ServerVariable) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ServerVariable objects
+// using the session and base URI of the specified context.
+func (m *ServerVariable) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6621,10 +7053,16 @@ func (m *ServerVariable) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside ServerVariables objects.
 func (m *ServerVariables) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside ServerVariables objects
+// using the session and base URI of the specified context.
+func (m *ServerVariables) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6635,22 +7073,40 @@ func (m *ServerVariables) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside SpecificationExtension objects.
 func (m *SpecificationExtension) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside SpecificationExtension objects
+// using the session and base URI of the specified context.
+func (m *SpecificationExtension) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside StringArray objects.
 func (m *StringArray) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside StringArray objects
+// using the session and base URI of the specified context.
+func (m *StringArray) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	return nil, compiler.NewErrorGroupOrNil(errors)
 }
 
 // ResolveReferences resolves references found inside Strings objects.
 func (m *Strings) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Strings objects
+// using the session and base URI of the specified context.
+func (m *Strings) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.AdditionalProperties {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6661,16 +7117,22 @@ func (m *Strings) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Tag objects.
 func (m *Tag) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Tag objects
+// using the session and base URI of the specified context.
+func (m *Tag) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	if m.ExternalDocs != nil {
-		_, err := m.ExternalDocs.ResolveReferences(root)
+		_, err := m.ExternalDocs.ResolveReferencesInContext(context)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -6681,10 +7143,16 @@ func (m *Tag) ResolveReferences(root string) (*yaml.Node, error) {
 
 // ResolveReferences resolves references found inside Xml objects.
 func (m *Xml) ResolveReferences(root string) (*yaml.Node, error) {
+	return m.ResolveReferencesInContext(compiler.NewContextWithBaseURI("$root", nil, nil, root))
+}
+
+// ResolveReferencesInContext resolves references found inside Xml objects
+// using the session and base URI of the specified context.
+func (m *Xml) ResolveReferencesInContext(context *compiler.Context) (*yaml.Node, error) {
 	errors := make([]error, 0)
 	for _, item := range m.SpecificationExtension {
 		if item != nil {
-			_, err := item.ResolveReferences(root)
+			_, err := item.ResolveReferencesInContext(context)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -16,7 +16,10 @@ package openapi_v3
 
 import (
 	"io/ioutil"
+	"strings"
 	"testing"
+
+	"github.com/google/gnostic/compiler"
 )
 
 func TestParseDocument(t *testing.T) {
@@ -36,3 +39,88 @@ func TestParseDocument(t *testing.T) {
 		t.Errorf("unexpected value for Title: %s (expected %s)", d.Info.Title, title)
 	}
 }
+
+func TestValidateDocument(t *testing.T) {
+	d, err := ParseDocument([]byte(`
+openapi: 3.0.0
+info:
+  title: Valid
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/petId'
+    get:
+      operationId: getPet
+      security:
+        - apiKey: []
+      responses:
+        "200":
+          description: pet
+components:
+  parameters:
+    petId:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: string
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// Compiled documents can also be validated, although errors will have no line numbers.
+	info := d.ToRawInfo()
+	if err := ValidateDocument(info, compiler.NewContext("$root", info, nil)); err != nil {
+		t.Errorf("unexpected errors: %+v", err)
+	}
+	d.Paths.Path[0].Value.Get.OperationId = "getPet"
+	d.Paths.Path[0].Value.Delete = d.Paths.Path[0].Value.Get
+	info = d.ToRawInfo()
+	err = ValidateDocument(info, compiler.NewContext("$root", info, nil))
+	if err == nil || !strings.Contains(err.Error(), "$root.paths./pets/{petId}.delete.operationId has duplicate operationId: getPet") {
+		t.Errorf("unexpected errors: %+v", err)
+	}
+}
+
+func TestValidateDocumentExamples(t *testing.T) {
+	info, err := compiler.ReadInfoFromBytes("examples.yaml", []byte(`
+openapi: 3.0.0
+info:
+  title: Examples
+  version: 1.0.0
+x-links:
+  $ref: '#/missing/extension'
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: object
+                example:
+                  $ref: '#/missing/example'
+              examples:
+                payload:
+                  value:
+                    $ref: '#/missing/value'
+                shared:
+                  $ref: '#/components/examples/missing'
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	err = ValidateDocument(info, compiler.NewContext("$root", info, nil))
+	if err == nil || strings.Count(err.Error(), "unresolved reference") != 1 ||
+		!strings.Contains(err.Error(), "has unresolved reference: #/components/examples/missing") {
+		t.Errorf("unexpected errors: %+v", err)
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// ValidateDocument checks an OpenAPI v3 description for errors that NewDocument
+// doesn't detect because they don't violate the structure of the OpenAPI schema:
+// duplicate operationIds, path template parameters without matching path
+// parameters, path parameters that aren't required, security requirements that
+// name undefined security schemes, and local references that can't be resolved.
+// The description should be the node that was compiled by NewDocument, so that
+// errors are reported with their locations in the source.
+func ValidateDocument(in *yaml.Node, context *compiler.Context) error {
+	if in != nil && in.Kind == yaml.DocumentNode && len(in.Content) > 0 {
+		in = in.Content[0]
+	}
+	v := &validator{root: in, schemes: make(map[string]bool)}
+	components := compiler.MapValueForKey(in, "components")
+	componentsContext := compiler.NewContext("components", components, context)
+	compiler.ForEachEntry(compiler.MapValueForKey(components, "securitySchemes"), func(name string, _ *yaml.Node) {
+		v.schemes[name] = true
+	})
+	parameters := compiler.MapValueForKey(components, "parameters")
+	parametersContext := compiler.NewContext("parameters", parameters, componentsContext)
+	compiler.ForEachEntry(parameters, func(name string, parameter *yaml.Node) {
+		if !isReference(parameter) {
+			v.validateParameter(parameter, compiler.NewContext(name, parameter, parametersContext))
+		}
+	})
+	v.validatePaths(compiler.MapValueForKey(in, "paths"), context)
+	if security := compiler.MapValueForKey(in, "security"); security != nil {
+		v.validateSecurity(security, compiler.NewContext("security", security, context))
+	}
+	v.validateReferences(in, context)
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		a, b := v.errors[i].(*compiler.Error).Context.Node, v.errors[j].(*compiler.Error).Context.Node
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return compiler.NewErrorGroupOrNil(v.errors)
+}
+
+// validator collects the errors found by ValidateDocument.
+type validator struct {
+	root *yaml.Node
+	// schemes holds the names of the document's security schemes.
+	schemes map[string]bool
+	errors  []error
+}
+
+// parameter describes an operation parameter.
+type parameter struct {
+	name    string
+	in      string
+	context *compiler.Context
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var pathTemplateParameter = regexp.MustCompile(`\{([^}]*)\}`)
+
+func (v *validator) addError(context *compiler.Context, message string) {
+	v.errors = append(v.errors, compiler.NewError(context, message))
+}
+
+// validatePaths checks the operationIds, parameters, and security requirements of each operation.
+func (v *validator) validatePaths(paths *yaml.Node, context *compiler.Context) {
+	pathsContext := compiler.NewContext("paths", paths, context)
+	operationIDs := make(map[string]string)
+	compiler.ForEachEntry(paths, func(path string, item *yaml.Node) {
+		itemContext := compiler.NewContext(path, item, pathsContext)
+		if isReference(item) {
+			if item = v.resolve(item); item == nil {
+				return
+			}
+		}
+		templateNames := make([]string, 0)
+		templateParameters := make(map[string]bool)
+		for _, m := range pathTemplateParameter.FindAllStringSubmatch(path, -1) {
+			templateNames = append(templateNames, m[1])
+			templateParameters[m[1]] = true
+		}
+		pathParameters := v.parameters(compiler.MapValueForKey(item, "parameters"), itemContext)
+		for _, method := range methods {
+			operation := compiler.MapValueForKey(item, method)
+			if operation == nil {
+				continue
+			}
+			operationContext := compiler.NewContext(method, operation, itemContext)
+			if id := compiler.MapValueForKey(operation, "operationId"); id != nil {
+				if other, ok := operationIDs[id.Value]; ok {
+					v.addError(compiler.NewContext("operationId", id, operationContext),
+						"has duplicate operationId: "+id.Value+" (also used by "+other+")")
+				} else {
+					operationIDs[id.Value] = operationContext.Description()
+				}
+			}
+			// Operation parameters override path parameters with the same name and location.
+			parameters := make(map[string]*parameter)
+			for _, p := range pathParameters {
+				parameters[p.in+":"+p.name] = p
+			}
+			for _, p := range v.parameters(compiler.MapValueForKey(operation, "parameters"), operationContext) {
+				parameters[p.in+":"+p.name] = p
+				v.validatePathParameter(p, templateParameters)
+			}
+			for _, name := range templateNames {
+				if parameters["path:"+name] == nil {
+					v.addError(operationContext, "is missing path parameter: "+name)
+				}
+			}
+			if security := compiler.MapValueForKey(operation, "security"); security != nil {
+				v.validateSecurity(security, compiler.NewContext("security", security, operationContext))
+			}
+		}
+		for _, p := range pathParameters {
+			v.validatePathParameter(p, templateParameters)
+		}
+	})
+}
+
+// validatePathParameter checks that a path parameter appears in its path template.
+func (v *validator) validatePathParameter(p *parameter, templateParameters map[string]bool) {
+	if p.in == "path" && !templateParameters[p.name] {
+		v.addError(p.context, "has path parameter that isn't in the path template: "+p.name)
+	}
+}
+
+// parameters returns the parameters in a list, resolving references to components.
+// Parameters that are defined in the list are validated.
+func (v *validator) parameters(list *yaml.Node, context *compiler.Context) []*parameter {
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil
+	}
+	listContext := compiler.NewContext("parameters", list, context)
+	parameters := make([]*parameter, 0)
+	for i, item := range list.Content {
+		itemContext := compiler.NewContext(strconv.Itoa(i), item, listContext)
+		if isReference(item) {
+			if item = v.resolve(item); item == nil {
+				continue
+			}
+		} else {
+			v.validateParameter(item, itemContext)
+		}
+		name, _ := compiler.StringForScalarNode(compiler.MapValueForKey(item, "name"))
+		in, _ := compiler.StringForScalarNode(compiler.MapValueForKey(item, "in"))
+		parameters = append(parameters, &parameter{name: name, in: in, context: itemContext})
+	}
+	return parameters
+}
+
+// validateParameter checks that a path parameter is required.
+func (v *validator) validateParameter(node *yaml.Node, context *compiler.Context) {
+	in, _ := compiler.StringForScalarNode(compiler.MapValueForKey(node, "in"))
+	if in != "path" {
+		return
+	}
+	if required, _ := compiler.BoolForScalarNode(compiler.MapValueForKey(node, "required")); !required {
+		name, _ := compiler.StringForScalarNode(compiler.MapValueForKey(node, "name"))
+		v.addError(context, "has path parameter that isn't required: "+name)
+	}
+}
+
+// validateSecurity checks that security requirements name defined security schemes.
+func (v *validator) validateSecurity(security *yaml.Node, context *compiler.Context) {
+	if security.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, requirement := range security.Content {
+		if requirement.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(requirement.Content)-1; j += 2 {
+			name := requirement.Content[j]
+			if !v.schemes[name.Value] {
+				v.addError(compiler.NewContext(strconv.Itoa(i), name, context),
+					"refers to undefined security scheme: "+name.Value)
+			}
+		}
+	}
+}
+
+// validateReferences checks that the local references in a node can be resolved.
+func (v *validator) validateReferences(node *yaml.Node, context *compiler.Context) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			v.validateReferences(item, compiler.NewContext(strconv.Itoa(i), item, context))
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				if strings.HasPrefix(value.Value, "#") && v.resolve(node) == nil {
+					v.addError(compiler.NewContext(key, value, context), "has unresolved reference: "+value.Value)
+				}
+			case key == "example" || strings.HasPrefix(key, "x-"):
+				// Examples and specification extensions can contain any values.
+			case key == "examples":
+				v.validateExampleReferences(value, compiler.NewContext(key, value, context))
+			default:
+				v.validateReferences(value, compiler.NewContext(key, value, context))
+			}
+		}
+	}
+}
+
+// validateExampleReferences checks the references in a map of examples.
+// The values of examples aren't checked because they can contain any values.
+func (v *validator) validateExampleReferences(examples *yaml.Node, context *compiler.Context) {
+	compiler.ForEachEntry(examples, func(name string, example *yaml.Node) {
+		if isReference(example) {
+			v.validateReferences(example, compiler.NewContext(name, example, context))
+		}
+	})
+}
+
+// resolve returns the target of a local reference or nil if it can't be resolved.
+// References to other files are not resolved.
+func (v *validator) resolve(node *yaml.Node) *yaml.Node {
+	ref, _ := compiler.StringForScalarNode(compiler.MapValueForKey(node, "$ref"))
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	target, err := compiler.NodeForPointer(v.root, compiler.PointerForFragment(ref[1:]))
+	if err != nil {
+		return nil
+	}
+	return target
+}
+
+// isReference returns true if a node is a reference object.
+func isReference(node *yaml.Node) bool {
+	return compiler.MapHasKey(node, "$ref")
+}
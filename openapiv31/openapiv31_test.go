@@ -0,0 +1,64 @@
+// Copyright 2020 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v31
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestParseDocument(t *testing.T) {
+	filename := "../examples/v3.1/yaml/petstore.yaml"
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Logf("unable to read file %s", filename)
+		t.FailNow()
+	}
+	d, err := ParseDocument(b)
+	if err != nil {
+		t.Logf("%s", err.Error())
+		t.FailNow()
+	}
+	title := "OpenAPI Petstore"
+	if d.Info.Title != title {
+		t.Errorf("unexpected value for Title: %s (expected %s)", d.Info.Title, title)
+	}
+	if d.Webhooks == nil || len(d.Webhooks.AdditionalProperties) != 1 {
+		t.Fatalf("expected one webhook, got %+v", d.Webhooks)
+	}
+	var pet *Schema
+	for _, pair := range d.Components.Schemas.AdditionalProperties {
+		if pair.Name == "Pet" {
+			pet = pair.Value.GetSchema()
+		}
+	}
+	if pet == nil {
+		t.Fatalf("missing Pet schema")
+	}
+	for _, pair := range pet.Properties.AdditionalProperties {
+		if pair.Name == "tag" {
+			types := pair.Value.GetSchema().Type.Value
+			if len(types) != 2 || types[0] != "string" || types[1] != "null" {
+				t.Errorf("unexpected type for tag: %+v", types)
+			}
+		}
+	}
+	if len(pet.XDefs.GetAdditionalProperties()) != 1 {
+		t.Errorf("expected one $defs entry, got %+v", pet.XDefs)
+	}
+	if _, ok := pet.UnevaluatedProperties.GetOneof().(*SchemaOrBoolean_Boolean); !ok || pet.UnevaluatedProperties.GetBoolean() {
+		t.Errorf("expected unevaluatedProperties to be false")
+	}
+}
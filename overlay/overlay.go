@@ -0,0 +1,207 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package overlay reads OpenAPI Overlay 1.0 documents and applies them to the
+// yaml.Node trees of API descriptions. Overlays describe changes that can be
+// reapplied to each new version of a description, such as vendor-specific
+// additions to an upstream API.
+package overlay
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/jsonpath"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// UnmatchedTarget is the code of messages that report actions
+// with targets that don't select any nodes.
+const UnmatchedTarget = "UNMATCHEDTARGET"
+
+// Overlay is an OpenAPI Overlay document.
+type Overlay struct {
+	// Version is the version of the Overlay Specification that the document uses.
+	Version string `yaml:"overlay"`
+	// Info describes the overlay.
+	Info Info `yaml:"info"`
+	// Extends is the URL of the description that the overlay was written for.
+	Extends string `yaml:"extends"`
+	// Actions are applied in order.
+	Actions []*Action `yaml:"actions"`
+}
+
+// Info describes an overlay.
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// Action modifies the nodes selected by a JSONPath query.
+type Action struct {
+	// Target is a JSONPath query (RFC 9535) that selects the nodes to modify.
+	Target string `yaml:"target"`
+	// Description describes the action.
+	Description string `yaml:"description"`
+	// Update is merged into each selected object or appended to each selected array.
+	// Its Kind is zero if the action has no update.
+	Update yaml.Node `yaml:"update"`
+	// Remove removes the selected nodes from their parents. Update is ignored if Remove is true.
+	Remove bool `yaml:"remove"`
+
+	path *jsonpath.Path
+}
+
+// ParseOverlay reads an Overlay document from JSON or YAML.
+func ParseOverlay(bytes []byte) (*Overlay, error) {
+	o := &Overlay{}
+	if err := yaml.Unmarshal(bytes, o); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(o.Version, "1.") {
+		return nil, fmt.Errorf("unsupported overlay version %q", o.Version)
+	}
+	if o.Info.Title == "" || o.Info.Version == "" {
+		return nil, errors.New("overlay info must include a title and a version")
+	}
+	if len(o.Actions) == 0 {
+		return nil, errors.New("overlay must include at least one action")
+	}
+	for i, action := range o.Actions {
+		if action == nil {
+			return nil, fmt.Errorf("action %d is empty", i)
+		}
+		path, err := jsonpath.Parse(action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("action %d: %s", i, err)
+		}
+		action.path = path
+	}
+	return o, nil
+}
+
+// Apply applies an overlay's actions in order to a document, modifying it in place.
+// Actions with targets that don't select any nodes are reported as WARNING messages.
+func (o *Overlay) Apply(info *yaml.Node) ([]*plugins.Message, error) {
+	messages := make([]*plugins.Message, 0)
+	for i, action := range o.Actions {
+		if action.path == nil {
+			path, err := jsonpath.Parse(action.Target)
+			if err != nil {
+				return nil, fmt.Errorf("action %d: %s", i, err)
+			}
+			action.path = path
+		}
+		results := action.path.Query(info)
+		if len(results) == 0 {
+			messages = append(messages, &plugins.Message{
+				Level: plugins.Message_WARNING,
+				Code:  UnmatchedTarget,
+				Text:  fmt.Sprintf("Target %s of action %d of overlay %q doesn't select any nodes.", action.Target, i, o.Info.Title),
+			})
+			continue
+		}
+		for _, r := range results {
+			var err error
+			if action.Remove {
+				err = remove(r)
+			} else if action.Update.Kind != 0 {
+				err = update(r.Node, &action.Update)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("action %d: %s at %s", i, err, "/"+strings.Join(r.Keys, "/"))
+			}
+		}
+	}
+	return messages, nil
+}
+
+// remove removes a selected node from its parent.
+func remove(r *jsonpath.Result) error {
+	if r.Parent == nil {
+		return errors.New("can't remove the root of a document")
+	}
+	parent := r.Parent
+	switch parent.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(parent.Content); i += 2 {
+			if refersTo(parent.Content[i], r.Node) {
+				parent.Content = append(parent.Content[:i-1], parent.Content[i+1:]...)
+				return nil
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range parent.Content {
+			if refersTo(item, r.Node) {
+				parent.Content = append(parent.Content[:i], parent.Content[i+1:]...)
+				return nil
+			}
+		}
+	}
+	// The node was already removed by an earlier selection of the same action.
+	return nil
+}
+
+// refersTo returns true if a node is another node or an alias of it.
+func refersTo(node, target *yaml.Node) bool {
+	return node == target || node.Kind == yaml.AliasNode && node.Alias == target
+}
+
+// update merges a value into a selected object or appends it to a selected array.
+func update(node, value *yaml.Node) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if value.Kind != yaml.MappingNode {
+			return errors.New("objects can only be updated with objects")
+		}
+		merge(node, value)
+	case yaml.SequenceNode:
+		node.Content = append(node.Content, compiler.CopyNode(value))
+	default:
+		return errors.New("only objects and arrays can be updated")
+	}
+	return nil
+}
+
+// merge recursively merges the members of one object into another.
+// Members that are not objects in both replace existing members.
+func merge(node, value *yaml.Node) {
+	for i := 0; i < len(value.Content)-1; i += 2 {
+		key, member := value.Content[i], value.Content[i+1]
+		found := false
+		for j := 0; j < len(node.Content)-1; j += 2 {
+			if node.Content[j].Value != key.Value {
+				continue
+			}
+			found = true
+			existing := node.Content[j+1]
+			if existing.Kind == yaml.AliasNode && existing.Alias != nil {
+				existing = existing.Alias
+			}
+			if existing.Kind == yaml.MappingNode && member.Kind == yaml.MappingNode {
+				merge(existing, member)
+			} else {
+				node.Content[j+1] = compiler.CopyNode(member)
+			}
+			break
+		}
+		if !found {
+			node.Content = append(node.Content, compiler.CopyNode(key), compiler.CopyNode(member))
+		}
+	}
+}
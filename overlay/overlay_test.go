@@ -0,0 +1,143 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package overlay
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const document = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+tags:
+  - name: pets
+  - name: internal
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      x-internal: false
+    post:
+      operationId: createPet
+      tags: [pets, internal]
+      x-internal: true
+`
+
+func apply(t *testing.T, overlayText string) (string, int, error) {
+	o, err := ParseOverlay([]byte(overlayText))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var info yaml.Node
+	if err := yaml.Unmarshal([]byte(document), &info); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	messages, err := o.Apply(&info)
+	if err != nil {
+		return "", 0, err
+	}
+	bytes, err := yaml.Marshal(&info)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return string(bytes), len(messages), nil
+}
+
+func TestApply(t *testing.T) {
+	result, unmatched, err := apply(t, `
+overlay: 1.0.0
+info:
+  title: Public API
+  version: 1.0.0
+actions:
+  - target: $.paths.*[?@['x-internal'] == true]
+    remove: true
+  - target: $.tags[?@.name == 'internal']
+    remove: true
+  - target: $.info
+    update:
+      title: Public Pets
+      contact:
+        name: API Team
+  - target: $.info
+    update:
+      contact:
+        email: api@example.com
+  - target: $.paths['/pets'].get.tags
+    update: public
+  - target: $..['x-internal']
+    remove: true
+  - target: $.paths['/stores']
+    update:
+      description: stores
+`)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := `openapi: 3.0.0
+info:
+    title: Public Pets
+    version: 1.0.0
+    contact:
+        name: API Team
+        email: api@example.com
+tags:
+    - name: pets
+paths:
+    /pets:
+        get:
+            operationId: listPets
+            tags: [pets, public]
+`
+	if result != expected {
+		t.Errorf("unexpected result:\n%s", result)
+	}
+	if unmatched != 1 {
+		t.Errorf("expected 1 unmatched target, found %d", unmatched)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	for _, action := range []string{
+		"{target: $, remove: true}",
+		"{target: $.info.title, update: {a: 1}}",
+		"{target: $.info, update: [1]}",
+	} {
+		_, _, err := apply(t, "{overlay: 1.0.0, info: {title: t, version: 1}, actions: ["+action+"]}")
+		if err == nil {
+			t.Errorf("expected an error for %s", action)
+		}
+	}
+}
+
+func TestParseOverlayErrors(t *testing.T) {
+	for _, text := range []string{
+		"{overlay: 2.0.0, info: {title: t, version: 1}, actions: [{target: $}]}",
+		"{overlay: 1.0.0, info: {version: 1}, actions: [{target: $}]}",
+		"{overlay: 1.0.0, info: {title: t, version: 1}, actions: []}",
+		"{overlay: 1.0.0, info: {title: t, version: 1}, actions: [{target: paths}]}",
+		"[",
+	} {
+		if _, err := ParseOverlay([]byte(text)); err == nil || strings.TrimSpace(err.Error()) == "" {
+			t.Errorf("expected an error for %s", text)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnostic_plugin_v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// Formats of message reports that are supported by FormatMessages.
+const (
+	// MessagesFormatPB is a binary Messages proto.
+	MessagesFormatPB = "pb"
+	// MessagesFormatText lists each message on a line, as described by Message.Report.
+	MessagesFormatText = "text"
+	// MessagesFormatJSON is the JSON encoding of a Messages proto.
+	MessagesFormatJSON = "json"
+	// MessagesFormatSARIF is a SARIF 2.1.0 log, which can be uploaded to code scanning services.
+	MessagesFormatSARIF = "sarif"
+	// MessagesFormatJUnit is a JUnit XML report, which can be read by test reporters.
+	MessagesFormatJUnit = "junit"
+	// MessagesFormatGitHub lists messages as GitHub Actions workflow commands,
+	// which annotate the lines that they refer to.
+	MessagesFormatGitHub = "github"
+)
+
+// MessagesFormats lists the supported message formats.
+var MessagesFormats = []string{
+	MessagesFormatPB,
+	MessagesFormatText,
+	MessagesFormatJSON,
+	MessagesFormatSARIF,
+	MessagesFormatJUnit,
+	MessagesFormatGitHub,
+}
+
+// MessagesFormatExtension returns the file extension used for messages in a format.
+func MessagesFormatExtension(format string) string {
+	switch format {
+	case MessagesFormatText:
+		return "messages.txt"
+	case MessagesFormatJSON:
+		return "messages.json"
+	case MessagesFormatSARIF:
+		return "sarif"
+	case MessagesFormatJUnit:
+		return "junit.xml"
+	case MessagesFormatGitHub:
+		return "messages.github"
+	}
+	return "messages.pb"
+}
+
+// FormatMessages encodes messages in one of the supported formats.
+// The source name identifies the API description that messages without
+// file locations refer to.
+func FormatMessages(messages []*Message, format string, sourceName string) ([]byte, error) {
+	switch format {
+	case MessagesFormatPB:
+		return proto.Marshal(&Messages{Messages: messages})
+	case MessagesFormatText:
+		var b bytes.Buffer
+		for _, m := range messages {
+			b.WriteString(m.Report() + "\n")
+		}
+		return b.Bytes(), nil
+	case MessagesFormatJSON:
+		m := jsonpb.Marshaler{Indent: "  "}
+		s, err := m.MarshalToString(&Messages{Messages: messages})
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s + "\n"), nil
+	case MessagesFormatSARIF:
+		return formatSARIF(messages, sourceName)
+	case MessagesFormatJUnit:
+		return formatJUnit(messages, sourceName)
+	case MessagesFormatGitHub:
+		return formatGitHub(messages, sourceName), nil
+	}
+	return nil, fmt.Errorf("unsupported messages format: %s", format)
+}
+
+// keyPath returns a readable description of the keys of a message.
+func keyPath(m *Message) string {
+	return strings.Join(m.GetKeys(), ".")
+}
+
+// sarifLevel returns the SARIF level of a message.
+func sarifLevel(m *Message) string {
+	switch m.GetLevel() {
+	case Message_FATAL, Message_ERROR:
+		return "error"
+	case Message_WARNING:
+		return "warning"
+	case Message_INFO:
+		return "note"
+	}
+	return "none"
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int32 `json:"startLine"`
+	StartColumn int32 `json:"startColumn,omitempty"`
+	EndLine     int32 `json:"endLine,omitempty"`
+	EndColumn   int32 `json:"endColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// formatSARIF encodes messages as a SARIF log with a rule for each message code.
+func formatSARIF(messages []*Message, sourceName string) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gnostic",
+			InformationURI: "https://github.com/google/gnostic",
+			Rules:          make([]sarifRule, 0),
+		}},
+		Results: make([]sarifResult, 0),
+	}
+	rules := make(map[string]bool)
+	for _, m := range messages {
+		if !rules[m.GetCode()] {
+			rules[m.GetCode()] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: m.GetCode()})
+		}
+		location := sarifLocation{}
+		location.PhysicalLocation.ArtifactLocation.URI = sourceName
+		if m.GetFile() != "" {
+			location.PhysicalLocation.ArtifactLocation.URI = m.GetFile()
+			if m.GetLine() > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   m.GetLine(),
+					StartColumn: m.GetColumn(),
+					EndLine:     m.GetEndLine(),
+					EndColumn:   m.GetEndColumn(),
+				}
+			}
+		}
+		if len(m.GetKeys()) > 0 {
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: keyPath(m)}}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    m.GetCode(),
+			Level:     sarifLevel(m),
+			Message:   sarifMessage{Text: m.GetText()},
+			Locations: []sarifLocation{location},
+		})
+	}
+	b, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// formatJUnit encodes messages as a JUnit report with a test case for each message.
+// Warnings, errors, and fatal errors are failures; other messages are reported
+// as test cases that pass.
+func formatJUnit(messages []*Message, sourceName string) ([]byte, error) {
+	suite := junitTestSuite{Name: sourceName, TestCases: make([]junitTestCase, 0)}
+	for _, m := range messages {
+		name := m.GetCode()
+		if path := keyPath(m); path != "" {
+			name += " " + path
+		}
+		testCase := junitTestCase{Name: name, ClassName: sourceName}
+		if m.GetFile() != "" {
+			testCase.ClassName = m.GetFile()
+		}
+		if m.GetLevel() >= Message_WARNING {
+			testCase.Failure = &junitFailure{Message: m.GetText(), Type: m.GetLevel().String(), Text: m.Report()}
+			suite.Failures++
+		} else {
+			testCase.SystemOut = m.Report()
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+	b, err := xml.MarshalIndent(junitTestSuites{TestSuites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// formatGitHub encodes messages as GitHub Actions workflow commands.
+func formatGitHub(messages []*Message, sourceName string) []byte {
+	var b bytes.Buffer
+	for _, m := range messages {
+		command := "notice"
+		switch m.GetLevel() {
+		case Message_FATAL, Message_ERROR:
+			command = "error"
+		case Message_WARNING:
+			command = "warning"
+		}
+		properties := []string{}
+		if m.GetFile() != "" {
+			properties = append(properties, "file="+escapeGitHubProperty(m.GetFile()))
+			if m.GetLine() > 0 {
+				properties = append(properties, fmt.Sprintf("line=%d", m.GetLine()), fmt.Sprintf("col=%d", m.GetColumn()))
+			}
+			if m.GetEndLine() > 0 {
+				properties = append(properties, fmt.Sprintf("endLine=%d", m.GetEndLine()), fmt.Sprintf("endColumn=%d", m.GetEndColumn()))
+			}
+		} else {
+			properties = append(properties, "file="+escapeGitHubProperty(sourceName))
+		}
+		properties = append(properties, "title="+escapeGitHubProperty(m.GetCode()))
+		text := m.GetText()
+		if m.GetFile() == "" && len(m.GetKeys()) > 0 {
+			text += " (" + keyPath(m) + ")"
+		}
+		fmt.Fprintf(&b, "::%s %s::%s\n", command, strings.Join(properties, ","), escapeGitHubData(text))
+	}
+	return b.Bytes()
+}
+
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
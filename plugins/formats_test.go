@@ -0,0 +1,143 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnostic_plugin_v1
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+var formatTestMessages = []*Message{
+	{
+		Level:     Message_ERROR,
+		Code:      "DANGLINGREFERENCE",
+		Text:      "Reference #/components/responses/NotFound can't be resolved.",
+		Keys:      []string{"paths", "/pets", "get", "responses", "404"},
+		File:      "openapi.yaml",
+		Line:      42,
+		Column:    9,
+		EndLine:   42,
+		EndColumn: 14,
+	},
+	{
+		Level: Message_INFO,
+		Code:  "REFERENCECYCLE",
+		Text:  "Reference cycle: 100%,\nrecursive",
+		Keys:  []string{"components", "schemas", "Tree"},
+	},
+}
+
+func TestFormatMessagesText(t *testing.T) {
+	b, err := FormatMessages(formatTestMessages, MessagesFormatText, "openapi.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := "openapi.yaml:42:9: ERROR DANGLINGREFERENCE Reference #/components/responses/NotFound can't be resolved.\n" +
+		"INFO    REFERENCECYCLE Reference cycle: 100%,\nrecursive [components schemas Tree]\n"
+	if string(b) != expected {
+		t.Errorf("unexpected text:\n%s", string(b))
+	}
+}
+
+func TestFormatMessagesGitHub(t *testing.T) {
+	b, err := FormatMessages(formatTestMessages, MessagesFormatGitHub, "openapi.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := "::error file=openapi.yaml,line=42,col=9,endLine=42,endColumn=14,title=DANGLINGREFERENCE::Reference #/components/responses/NotFound can't be resolved.\n" +
+		"::notice file=openapi.yaml,title=REFERENCECYCLE::Reference cycle: 100%25,%0Arecursive (components.schemas.Tree)\n"
+	if string(b) != expected {
+		t.Errorf("unexpected annotations:\n%s", string(b))
+	}
+}
+
+func TestFormatMessagesSARIF(t *testing.T) {
+	b, err := FormatMessages(formatTestMessages, MessagesFormatSARIF, "openapi.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(b, &log); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", string(b))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("unexpected run: %s", string(b))
+	}
+	result := run.Results[0]
+	region := result.Locations[0].PhysicalLocation.Region
+	if result.Level != "error" || region == nil || region.StartLine != 42 || region.StartColumn != 9 || region.EndColumn != 14 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	result = run.Results[1]
+	if result.Level != "note" || result.Locations[0].PhysicalLocation.Region != nil ||
+		result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "openapi.yaml" ||
+		result.Locations[0].LogicalLocations[0].FullyQualifiedName != "components.schemas.Tree" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestFormatMessagesJUnit(t *testing.T) {
+	b, err := FormatMessages(formatTestMessages, MessagesFormatJUnit, "openapi.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(b, &report); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	suite := report.TestSuites[0]
+	if suite.Tests != 2 || suite.Failures != 1 {
+		t.Fatalf("unexpected report: %s", string(b))
+	}
+	if failure := suite.TestCases[0].Failure; failure == nil || failure.Type != "ERROR" {
+		t.Errorf("unexpected test case: %+v", suite.TestCases[0])
+	}
+	if testCase := suite.TestCases[1]; testCase.Failure != nil || !strings.Contains(testCase.SystemOut, "REFERENCECYCLE") {
+		t.Errorf("unexpected test case: %+v", testCase)
+	}
+}
+
+func TestFormatMessagesJSON(t *testing.T) {
+	b, err := FormatMessages(formatTestMessages, MessagesFormatJSON, "openapi.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var messages struct {
+		Messages []struct {
+			Level string
+			Code  string
+			File  string
+			Line  int
+		}
+	}
+	if err := json.Unmarshal(b, &messages); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(messages.Messages) != 2 || messages.Messages[0].Level != "ERROR" || messages.Messages[0].Line != 42 {
+		t.Errorf("unexpected messages: %s", string(b))
+	}
+}
+
+func TestFormatMessagesUnsupported(t *testing.T) {
+	if _, err := FormatMessages(formatTestMessages, "csv", "openapi.yaml"); err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-examplecheck is a plugin that checks that the examples in an
+// OpenAPI v3 description are valid instances of their schemas.
+package main
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/google/gnostic/examplecheck"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// This is the main function for the plugin.
+func main() {
+	env, err := plugins.NewEnvironment()
+	env.RespondAndExitIfError(err)
+
+	for _, model := range env.Request.Models {
+		switch model.TypeUrl {
+		case "openapi.v3.Document":
+			documentv3 := &openapiv3.Document{}
+			err = proto.Unmarshal(model.Value, documentv3)
+			if err == nil {
+				env.Response.Messages = append(env.Response.Messages, examplecheck.CheckOpenAPIv3(documentv3)...)
+			}
+		}
+	}
+
+	env.RespondAndExit()
+}
@@ -24,6 +24,7 @@ import (
 
 	openapiv2 "github.com/google/gnostic/openapiv2"
 	openapiv3 "github.com/google/gnostic/openapiv3"
+	openapiv31 "github.com/google/gnostic/openapiv31"
 	plugins "github.com/google/gnostic/plugins"
 	surface "github.com/google/gnostic/surface"
 )
@@ -48,6 +49,12 @@ func main() {
 				if err == nil {
 					log.Printf("%+v", document)
 				}
+			case "openapi.v31.Document":
+				document := &openapiv31.Document{}
+				err = proto.Unmarshal(model.Value, document)
+				if err == nil {
+					log.Printf("%+v", document)
+				}
 			case "surface.v1.Model":
 				document := &surface.Model{}
 				err = proto.Unmarshal(model.Value, document)
@@ -0,0 +1,67 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-refgraph is a plugin that analyzes the references in an API description.
+// It reports unused components, dangling references, and reference cycles,
+// and it writes the reference graph in the DOT language of Graphviz.
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+
+	openapiv2 "github.com/google/gnostic/openapiv2"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+	"github.com/google/gnostic/refgraph"
+)
+
+// This is the main function for the plugin.
+func main() {
+	env, err := plugins.NewEnvironment()
+	env.RespondAndExitIfError(err)
+
+	var graph *refgraph.Graph
+
+	for _, model := range env.Request.Models {
+		switch model.TypeUrl {
+		case "openapi.v2.Document":
+			documentv2 := &openapiv2.Document{}
+			err = proto.Unmarshal(model.Value, documentv2)
+			if err == nil {
+				graph, err = refgraph.NewGraphFromOpenAPIv2(documentv2)
+				env.RespondAndExitIfError(err)
+			}
+		case "openapi.v3.Document":
+			documentv3 := &openapiv3.Document{}
+			err = proto.Unmarshal(model.Value, documentv3)
+			if err == nil {
+				graph, err = refgraph.NewGraphFromOpenAPIv3(documentv3)
+				env.RespondAndExitIfError(err)
+			}
+		}
+	}
+
+	if graph != nil {
+		env.Response.Messages = graph.Messages()
+
+		file := &plugins.File{}
+		file.Name = filepath.Join(filepath.Dir(env.Request.SourceName), "refgraph.dot")
+		file.Data = graph.DOT()
+		env.Response.Files = append(env.Response.Files, file)
+	}
+
+	env.RespondAndExit()
+}
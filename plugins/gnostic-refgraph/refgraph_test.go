@@ -0,0 +1,48 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestRefgraphPluginWithOpenAPIv3(t *testing.T) {
+	outputFile := "refgraph-v3.out"
+	// remove any preexisting output files
+	os.Remove(outputFile)
+	// run the compiler, discarding messages, which are tested with the refgraph package
+	output, err := exec.Command(
+		"gnostic",
+		"--refgraph-out=-",
+		"--messages-out=!",
+		"--no-validation",
+		"../../testdata/refgraph/openapi-v3.yaml").Output()
+	if err != nil {
+		t.Logf("Compile failed: %+v", err)
+		t.FailNow()
+	}
+	_ = ioutil.WriteFile(outputFile, output, 0644)
+	err = exec.Command("diff", outputFile, "../../testdata/refgraph/openapi-v3.out").Run()
+	if err != nil {
+		t.Logf("Diff failed: %s vs %s %+v", outputFile, "../../testdata/refgraph/openapi-v3.out", err)
+		t.FailNow()
+	} else {
+		// if the test succeeded, clean up
+		os.Remove(outputFile)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnostic_plugin_v1
+
+import "fmt"
+
+// Position returns the location of a message as "file:line:column",
+// or the empty string if the message has no location.
+func (m *Message) Position() string {
+	if m.GetFile() == "" {
+		return ""
+	}
+	if m.GetLine() == 0 {
+		return m.GetFile()
+	}
+	return fmt.Sprintf("%s:%d:%d", m.GetFile(), m.GetLine(), m.GetColumn())
+}
+
+// Report returns a one-line description of a message. Messages with locations are
+// described as "file:line:column: LEVEL CODE text" so that editors and other tools
+// can find the locations that they refer to. Other messages are described by their
+// level, code, text, and keys.
+func (m *Message) Report() string {
+	if position := m.Position(); position != "" {
+		return fmt.Sprintf("%s: %s %s %s", position, m.GetLevel(), m.GetCode(), m.GetText())
+	}
+	return fmt.Sprintf("%-7s %-14s %s %+v", m.GetLevel(), m.GetCode(), m.GetText(), m.GetKeys())
+}
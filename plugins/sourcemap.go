@@ -0,0 +1,187 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnostic_plugin_v1
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// sourceMapper holds the state of the construction of a SourceMap.
+type sourceMapper struct {
+	session *compiler.Session
+	// root is the name of the file that holds the source document.
+	root    string
+	entries []*SourceMap_Entry
+	// mapped records the key paths that have entries.
+	mapped map[string]bool
+}
+
+// NewSourceMap returns the SourceMap of a source document that was read from the named file.
+// Files that the document refers to are read with the specified session.
+func NewSourceMap(session *compiler.Session, info *yaml.Node, filename string) *SourceMap {
+	if info != nil && info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+		info = info.Content[0]
+	}
+	m := &sourceMapper{
+		session: session,
+		root:    filename,
+		entries: make([]*SourceMap_Entry, 0),
+		mapped:  make(map[string]bool),
+	}
+	if info != nil {
+		m.add([]string{}, info, filename)
+		m.mapNode(info, filename, []string{}, nil)
+	}
+	return &SourceMap{Entries: m.entries}
+}
+
+// add adds an entry for a key path unless the key path already has one.
+func (m *sourceMapper) add(keys []string, node *yaml.Node, filename string) {
+	id := strings.Join(keys, "\x00")
+	if m.mapped[id] {
+		return
+	}
+	m.mapped[id] = true
+	location := &SourceLocation{
+		File:   filename,
+		Line:   int32(node.Line),
+		Column: int32(node.Column),
+	}
+	if width := scalarWidth(node); width > 0 {
+		location.EndLine = location.Line
+		location.EndColumn = location.Column + int32(width)
+	}
+	m.entries = append(m.entries, &SourceMap_Entry{Keys: keys, Location: location})
+}
+
+// scalarWidth returns the number of characters in the source of a scalar
+// that is written on a single line, or zero if it can't be determined.
+func scalarWidth(node *yaml.Node) int {
+	if node.Kind != yaml.ScalarNode || strings.ContainsAny(node.Value, "\n\r") {
+		return 0
+	}
+	width := utf8.RuneCountInString(node.Value)
+	switch node.Style {
+	case 0:
+		return width
+	case yaml.SingleQuotedStyle:
+		return width + strings.Count(node.Value, "'") + 2
+	case yaml.DoubleQuotedStyle:
+		// Values that contain characters that might have been escaped are skipped.
+		for _, r := range node.Value {
+			if r == '"' || r == '\\' || r < ' ' || r > '~' {
+				return 0
+			}
+		}
+		return width + 2
+	}
+	return 0
+}
+
+// mapNode adds entries for the contents of a node that was read from the named file.
+// The stack holds the locations of the external references that are being followed.
+func (m *sourceMapper) mapNode(node *yaml.Node, filename string, keys []string, stack []string) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemKeys := compiler.KeysWith(keys, strconv.Itoa(i))
+			m.add(itemKeys, item, filename)
+			m.mapNode(item, filename, itemKeys, stack)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			valueKeys := compiler.KeysWith(keys, key.Value)
+			m.add(valueKeys, key, filename)
+			m.mapNode(value, filename, valueKeys, stack)
+		}
+		if ref, ok := compiler.StringForScalarNode(compiler.MapValueForKey(node, "$ref")); ok {
+			m.mapReference(ref, filename, keys, stack)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			m.mapNode(node.Alias, filename, keys, stack)
+		}
+	}
+}
+
+// mapReference adds entries for the target of a reference to another file.
+// Local references are not followed because their targets are mapped where they are defined.
+func (m *sourceMapper) mapReference(ref, filename string, keys []string, stack []string) {
+	location := compiler.ResolveURI(filename, ref)
+	target := strings.SplitN(location, "#", 2)[0]
+	if target == m.root {
+		return
+	}
+	for _, s := range stack {
+		if s == location {
+			return
+		}
+	}
+	node, err := m.session.ReadInfoForRef(filename, ref)
+	if err != nil || node == nil {
+		return
+	}
+	m.mapNode(node, target, keys, append(stack, location))
+}
+
+// AddLocations sets the locations of messages that have keys but no locations.
+func (m *SourceMap) AddLocations(messages []*Message) {
+	for _, message := range messages {
+		if message.File != "" || len(message.Keys) == 0 {
+			continue
+		}
+		if location := m.Location(message.Keys); location != nil {
+			message.File = location.File
+			message.Line = location.Line
+			message.Column = location.Column
+			message.EndLine = location.EndLine
+			message.EndColumn = location.EndColumn
+		}
+	}
+}
+
+// Location returns the location of the element of the source document with
+// the specified key path, or the location of its nearest mapped ancestor.
+// It returns nil if the map has no entry for the key path or any of its ancestors.
+func (m *SourceMap) Location(keys []string) *SourceLocation {
+	var location *SourceLocation
+	length := -1
+	for _, entry := range m.GetEntries() {
+		if len(entry.Keys) > length && len(entry.Keys) <= len(keys) && hasPrefix(keys, entry.Keys) {
+			location = entry.Location
+			length = len(entry.Keys)
+			if length == len(keys) {
+				break
+			}
+		}
+	}
+	return location
+}
+
+func hasPrefix(keys, prefix []string) bool {
+	for i := range prefix {
+		if keys[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnostic_plugin_v1
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/gnostic/compiler"
+)
+
+func TestSourceMap(t *testing.T) {
+	filename := "../testdata/bundle/v3.0/openapi.yaml"
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	session := compiler.NewSession()
+	info, err := session.ReadInfoFromBytes(filename, b)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	sourceMap := NewSourceMap(session, info, filename)
+	tests := []struct {
+		keys     string
+		expected string
+	}{
+		{"", "../testdata/bundle/v3.0/openapi.yaml:1:1"},
+		{"paths./pets.get", "../testdata/bundle/v3.0/openapi.yaml:7:5"},
+		{"paths./pets.get.parameters.0", "../testdata/bundle/v3.0/openapi.yaml:10:11"},
+		{"paths./pets.get.parameters.0.$ref", "../testdata/bundle/v3.0/openapi.yaml:10:11"},
+		// Key paths through references to other files are mapped to those files.
+		{"paths./pets.get.parameters.0.in", "../testdata/bundle/v3.0/common/parameters.yaml:3:3"},
+		{"paths./pets.get.responses.200.content.application/json.schema.items.properties.name", "../testdata/bundle/v3.0/schemas/Pet.yaml:9:3"},
+		{"paths./pets.get.responses.200.content.application/json.schema.items.properties.owner.type", "../testdata/bundle/v3.0/schemas/Owner.yaml:1:1"},
+		// Key paths that aren't mapped use the location of their nearest mapped ancestor.
+		{"paths./pets.get.description", "../testdata/bundle/v3.0/openapi.yaml:7:5"},
+	}
+	for _, test := range tests {
+		keys := []string{}
+		if test.keys != "" {
+			keys = strings.Split(test.keys, ".")
+		}
+		location := sourceMap.Location(keys)
+		if location == nil {
+			t.Errorf("no location for %s", test.keys)
+			continue
+		}
+		if actual := fmt.Sprintf("%s:%d:%d", location.File, location.Line, location.Column); actual != test.expected {
+			t.Errorf("unexpected location for %s: %s (expected %s)", test.keys, actual, test.expected)
+		}
+	}
+	if location := (&SourceMap{}).Location([]string{"paths"}); location != nil {
+		t.Errorf("unexpected location in empty map: %+v", location)
+	}
+}
+
+func TestAddLocations(t *testing.T) {
+	filename := "../testdata/refgraph/openapi-v3.yaml"
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	session := compiler.NewSession()
+	info, err := session.ReadInfoFromBytes(filename, b)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	messages := []*Message{
+		{Level: Message_WARNING, Code: "UNUSEDCOMPONENT", Text: "Component is never used.", Keys: []string{"components", "schemas", "Unused"}},
+		{Level: Message_ERROR, Code: "PLUGINLOCATION", Text: "Located by plugin.", Keys: []string{"paths"}, File: "other.yaml", Line: 3, Column: 4},
+		{Level: Message_INFO, Code: "NOKEYS", Text: "Not located."},
+	}
+	NewSourceMap(session, info, filename).AddLocations(messages)
+	expected := []string{
+		"../testdata/refgraph/openapi-v3.yaml:100:5: WARNING UNUSEDCOMPONENT Component is never used.",
+		"other.yaml:3:4: ERROR PLUGINLOCATION Located by plugin.",
+		"INFO    NOKEYS         Not located. []",
+	}
+	for i, message := range messages {
+		if report := message.Report(); report != expected[i] {
+			t.Errorf("unexpected report: %q (expected %q)", report, expected[i])
+		}
+	}
+	if messages[0].EndLine != 100 || messages[0].EndColumn != 11 {
+		t.Errorf("unexpected end: %d:%d", messages[0].EndLine, messages[0].EndColumn)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refgraph
+
+import (
+	"sort"
+	"strings"
+
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// Message codes used to report the results of analyses.
+const (
+	UnusedComponent   = "UNUSEDCOMPONENT"
+	DanglingReference = "DANGLINGREFERENCE"
+	ReferenceCycle    = "REFERENCECYCLE"
+)
+
+// Reachable returns the IDs of the nodes that are transitively referenced
+// from the nodes that aren't components, including those nodes themselves.
+func (g *Graph) Reachable() map[string]bool {
+	roots := make([]*Node, 0)
+	for _, node := range g.nodes {
+		if !node.Component {
+			roots = append(roots, node)
+		}
+	}
+	return g.ReachableFrom(roots...)
+}
+
+// ReachableFrom returns the IDs of the nodes that are transitively
+// referenced from the specified nodes, including those nodes themselves.
+func (g *Graph) ReachableFrom(roots ...*Node) map[string]bool {
+	reached := make(map[string]bool)
+	queue := make([]*Node, 0)
+	for _, node := range roots {
+		if !reached[node.ID] {
+			reached[node.ID] = true
+			queue = append(queue, node)
+		}
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, ref := range node.Refs {
+			if ref.Target != "" && !reached[ref.Target] {
+				reached[ref.Target] = true
+				queue = append(queue, g.index[ref.Target])
+			}
+		}
+	}
+	return reached
+}
+
+// Unused returns the components that are not transitively referenced from
+// any path, operation, or top-level security requirement.
+func (g *Graph) Unused() []*Node {
+	reached := g.Reachable()
+	unused := make([]*Node, 0)
+	for _, node := range g.nodes {
+		if node.Component && !reached[node.ID] {
+			unused = append(unused, node)
+		}
+	}
+	return unused
+}
+
+// Dangling returns the local references that can't be resolved.
+func (g *Graph) Dangling() []*Reference {
+	dangling := make([]*Reference, 0)
+	for _, node := range g.nodes {
+		for _, ref := range node.Refs {
+			if ref.Dangling() {
+				dangling = append(dangling, ref)
+			}
+		}
+	}
+	return dangling
+}
+
+// Cycles returns the reference cycles in the graph. Each cycle is a list of
+// nodes in which each node refers to the next and the last refers to the first.
+// Every node that is part of a cycle appears in exactly one of the returned cycles.
+func (g *Graph) Cycles() [][]*Node {
+	cycles := make([][]*Node, 0)
+	for _, component := range g.stronglyConnectedComponents() {
+		start := component[0]
+		if len(component) == 1 && !g.refersTo(start, start.ID) {
+			continue
+		}
+		members := make(map[string]bool)
+		for _, node := range component {
+			members[node.ID] = true
+		}
+		cycles = append(cycles, g.pathBack(start, members))
+	}
+	return cycles
+}
+
+// refersTo returns true if a node has a reference to the node with the specified ID.
+func (g *Graph) refersTo(node *Node, id string) bool {
+	for _, ref := range node.Refs {
+		if ref.Target == id {
+			return true
+		}
+	}
+	return false
+}
+
+// pathBack returns the shortest path of references from a node back to itself
+// that only passes through the specified members of its strongly connected component.
+func (g *Graph) pathBack(start *Node, members map[string]bool) []*Node {
+	previous := make(map[string]*Node)
+	queue := []*Node{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, ref := range node.Refs {
+			if ref.Target == start.ID {
+				path := []*Node{node}
+				for n := node; n != start; {
+					n = previous[n.ID]
+					path = append([]*Node{n}, path...)
+				}
+				return path
+			}
+			if ref.Target != "" && members[ref.Target] && previous[ref.Target] == nil {
+				previous[ref.Target] = node
+				queue = append(queue, g.index[ref.Target])
+			}
+		}
+	}
+	return []*Node{start}
+}
+
+// stronglyConnectedComponents returns the strongly connected components of
+// the graph using Tarjan's algorithm. Nodes in each component and the
+// components themselves are returned in document order.
+func (g *Graph) stronglyConnectedComponents() [][]*Node {
+	order := make(map[string]int)
+	for i, node := range g.nodes {
+		order[node.ID] = i
+	}
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := make([]*Node, 0)
+	components := make([][]*Node, 0)
+	var visit func(node *Node)
+	visit = func(node *Node) {
+		index[node.ID] = len(index)
+		lowlink[node.ID] = index[node.ID]
+		stack = append(stack, node)
+		onStack[node.ID] = true
+		for _, ref := range node.Refs {
+			if ref.Target == "" {
+				continue
+			}
+			if _, visited := index[ref.Target]; !visited {
+				visit(g.index[ref.Target])
+				if lowlink[ref.Target] < lowlink[node.ID] {
+					lowlink[node.ID] = lowlink[ref.Target]
+				}
+			} else if onStack[ref.Target] && index[ref.Target] < lowlink[node.ID] {
+				lowlink[node.ID] = index[ref.Target]
+			}
+		}
+		if lowlink[node.ID] == index[node.ID] {
+			component := make([]*Node, 0)
+			for {
+				n := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[n.ID] = false
+				component = append(component, n)
+				if n == node {
+					break
+				}
+			}
+			sortNodes(component, order)
+			components = append(components, component)
+		}
+	}
+	for _, node := range g.nodes {
+		if _, visited := index[node.ID]; !visited {
+			visit(node)
+		}
+	}
+	sortComponents(components, order)
+	return components
+}
+
+// Messages returns messages that report unused components as warnings,
+// dangling references as errors, and reference cycles as information.
+func (g *Graph) Messages() []*plugins.Message {
+	messages := make([]*plugins.Message, 0)
+	for _, ref := range g.Dangling() {
+		messages = append(messages, &plugins.Message{
+			Level: plugins.Message_ERROR,
+			Code:  DanglingReference,
+			Text:  "Reference " + ref.Ref + " can't be resolved.",
+			Keys:  ref.Keys,
+		})
+	}
+	for _, node := range g.Unused() {
+		messages = append(messages, &plugins.Message{
+			Level: plugins.Message_WARNING,
+			Code:  UnusedComponent,
+			Text:  "Component " + node.ID + " is never used.",
+			Keys:  node.Keys,
+		})
+	}
+	for _, cycle := range g.Cycles() {
+		ids := make([]string, 0, len(cycle)+1)
+		for _, node := range cycle {
+			ids = append(ids, node.ID)
+		}
+		ids = append(ids, cycle[0].ID)
+		messages = append(messages, &plugins.Message{
+			Level: plugins.Message_INFO,
+			Code:  ReferenceCycle,
+			Text:  "Reference cycle: " + strings.Join(ids, " -> "),
+			Keys:  cycle[0].Keys,
+		})
+	}
+	return messages
+}
+
+// sortNodes sorts nodes in document order.
+func sortNodes(nodes []*Node, order map[string]int) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return order[nodes[i].ID] < order[nodes[j].ID]
+	})
+}
+
+// sortComponents sorts lists of nodes by the document order of their first nodes.
+func sortComponents(components [][]*Node, order map[string]int) {
+	sort.Slice(components, func(i, j int) bool {
+		return order[components[i][0].ID] < order[components[j][0].ID]
+	})
+}
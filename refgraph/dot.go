@@ -0,0 +1,85 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refgraph
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DOT returns a description of the graph in the DOT language of Graphviz.
+// Operations are drawn as boxes, unused components are gray, and dangling
+// references are drawn as dashed red edges to the references that they contain.
+func (g *Graph) DOT() []byte {
+	reached := g.Reachable()
+	var b bytes.Buffer
+	b.WriteString("digraph refs {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range g.nodes {
+		if !node.Component && node.Kind != OperationKind && len(node.Refs) == 0 {
+			// Only show documents and paths that contain references.
+			continue
+		}
+		attributes := []string{"label=" + dotString(labelForNode(node))}
+		switch {
+		case node.Kind == OperationKind:
+			attributes = append(attributes, "shape=box")
+		case node.Kind == PathKind || node.Kind == DocumentKind:
+			attributes = append(attributes, "shape=note")
+		case !reached[node.ID]:
+			attributes = append(attributes, "color=gray", "fontcolor=gray")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotString(node.ID), strings.Join(attributes, ", "))
+	}
+	for _, node := range g.nodes {
+		written := make(map[string]bool)
+		for _, ref := range node.Refs {
+			switch {
+			case ref.Target != "":
+				if !written[ref.Target] {
+					written[ref.Target] = true
+					fmt.Fprintf(&b, "  %s -> %s;\n", dotString(node.ID), dotString(ref.Target))
+				}
+			case ref.Dangling():
+				if !written[ref.Ref] {
+					written[ref.Ref] = true
+					fmt.Fprintf(&b, "  %s [label=%s, shape=plaintext, fontcolor=red];\n", dotString("missing "+ref.Ref), dotString(ref.Ref))
+					fmt.Fprintf(&b, "  %s -> %s [style=dashed, color=red];\n", dotString(node.ID), dotString("missing "+ref.Ref))
+				}
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// labelForNode returns the text displayed for a node.
+func labelForNode(node *Node) string {
+	switch node.Kind {
+	case DocumentKind:
+		return "document"
+	case PathKind, OperationKind:
+		return node.Name
+	default:
+		return node.Kind + "/" + node.Name
+	}
+}
+
+// dotString returns a quoted DOT string.
+func dotString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return `"` + strings.Replace(s, `"`, `\"`, -1) + `"`
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refgraph
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+// prunableKinds lists the kinds of components that are removed when they are unused.
+// Other components, such as security schemes, are kept along with everything that they reference.
+var prunableKinds = map[string]bool{
+	"schemas":       true,
+	"parameters":    true,
+	"responses":     true,
+	"requestBodies": true,
+	"headers":       true,
+	"examples":      true,
+	"definitions":   true,
+}
+
+// Prune returns a copy of an OpenAPI v2 or v3 document without the schemas, parameters,
+// responses, request bodies, headers, and examples that are not transitively referenced
+// from its paths. It also returns the IDs of the components that were removed.
+func Prune(info *yaml.Node) (*yaml.Node, []string, error) {
+	return prune(info, func(node *Node) bool {
+		return !node.Component || !prunableKinds[node.Kind]
+	})
+}
+
+// PruneAllComponents returns a copy of an OpenAPI v2 or v3 document without any
+// components that are not transitively referenced from its paths or top-level
+// security requirements, including security schemes, links, and callbacks.
+// It also returns the IDs of the components that were removed.
+func PruneAllComponents(info *yaml.Node) (*yaml.Node, []string, error) {
+	return prune(info, func(node *Node) bool {
+		return !node.Component
+	})
+}
+
+// prune removes the nodes that are not transitively referenced from the nodes that are kept.
+func prune(info *yaml.Node, keep func(node *Node) bool) (*yaml.Node, []string, error) {
+	info = compiler.CopyNode(info)
+	g, err := NewGraph(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	roots := make([]*Node, 0)
+	for _, node := range g.nodes {
+		if keep(node) {
+			roots = append(roots, node)
+		}
+	}
+	reached := g.ReachableFrom(roots...)
+	removed := make([]string, 0)
+	for _, node := range g.nodes {
+		if !reached[node.ID] {
+			removeEntry(g.root, node.Keys)
+			removed = append(removed, node.ID)
+		}
+	}
+	return info, removed, nil
+}
+
+// PruneOpenAPIv3 returns a copy of an OpenAPI v3 document without unused components.
+func PruneOpenAPIv3(document *openapi3.Document) (*openapi3.Document, []string, error) {
+	info, removed, err := Prune(document.ToRawInfo())
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := openapi3.NewDocument(info, compiler.NewContext("$root", info, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, removed, nil
+}
+
+// PruneOpenAPIv2 returns a copy of an OpenAPI v2 document without unused definitions,
+// parameters, and responses.
+func PruneOpenAPIv2(document *openapi2.Document) (*openapi2.Document, []string, error) {
+	info, removed, err := Prune(document.ToRawInfo())
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := openapi2.NewDocument(info, compiler.NewContext("$root", info, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, removed, nil
+}
+
+// removeEntry removes the entry at a key path from a mapping node along with
+// any of its containing mappings that become empty.
+func removeEntry(node *yaml.Node, keys []string) {
+	if node == nil || node.Kind != yaml.MappingNode || len(keys) == 0 {
+		return
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value != keys[0] {
+			continue
+		}
+		if len(keys) > 1 {
+			value := node.Content[i+1]
+			removeEntry(value, keys[1:])
+			if value.Kind != yaml.MappingNode || len(value.Content) > 0 {
+				return
+			}
+		}
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+		return
+	}
+}
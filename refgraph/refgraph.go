@@ -0,0 +1,309 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package refgraph builds graphs of the references between the operations
+// and components of OpenAPI descriptions. The graphs can be used to find
+// unused components, references that can't be resolved, and reference cycles,
+// and to remove unused components from documents.
+package refgraph
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+// Kinds of nodes that aren't components.
+const (
+	DocumentKind  = "document"
+	PathKind      = "path"
+	OperationKind = "operation"
+)
+
+// methods lists the fields of path items that hold operations.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPI3Sections and openAPI2Sections list the sections that hold components,
+// identified by their key paths.
+var openAPI3Sections = [][]string{
+	{"components", "schemas"},
+	{"components", "responses"},
+	{"components", "parameters"},
+	{"components", "examples"},
+	{"components", "requestBodies"},
+	{"components", "headers"},
+	{"components", "securitySchemes"},
+	{"components", "links"},
+	{"components", "callbacks"},
+}
+
+var openAPI2Sections = [][]string{
+	{"definitions"},
+	{"parameters"},
+	{"responses"},
+	{"securityDefinitions"},
+}
+
+// A Node is a part of an API description that can make or receive references.
+type Node struct {
+	// ID is a local reference to the node, such as "#/components/schemas/Pet".
+	ID string
+	// Kind is the name of the section that holds a component
+	// or one of DocumentKind, PathKind, and OperationKind.
+	Kind string
+	// Name is the name of a component or the path of a path or operation.
+	Name string
+	// Keys is the key path of the node in the document.
+	Keys []string
+	// Component is true for nodes that are only used when they are referenced.
+	Component bool
+	// Refs lists the references made from the node in document order.
+	Refs []*Reference
+}
+
+// A Reference is an edge of the graph.
+type Reference struct {
+	// Ref is the reference as it appears in the document. Security requirements
+	// and discriminator mappings are represented by equivalent local references.
+	Ref string
+	// Keys is the key path of the reference in the document.
+	Keys []string
+	// Target is the ID of the node that contains the referenced fragment. It is
+	// empty for references to other files and for references that can't be resolved.
+	Target string
+	// External is true for references to other files, which are not followed.
+	External bool
+}
+
+// Dangling returns true for local references that can't be resolved.
+func (r *Reference) Dangling() bool {
+	return r.Target == "" && !r.External
+}
+
+// Graph is a graph of the references in an API description.
+type Graph struct {
+	// root is the top-level node of the document.
+	root *yaml.Node
+	// nodes holds the nodes of the graph in document order.
+	nodes []*Node
+	// index maps node IDs to nodes.
+	index map[string]*Node
+	// schemasPrefix is the ID prefix of schemas, which is used to
+	// resolve the schema names in discriminator mappings.
+	schemasPrefix string
+	// securityPrefix is the ID prefix of security schemes, which
+	// are referenced by name in security requirements.
+	securityPrefix string
+}
+
+// NewGraphFromOpenAPIv3 returns the reference graph of an OpenAPI v3 document.
+func NewGraphFromOpenAPIv3(document *openapi3.Document) (*Graph, error) {
+	return NewGraph(document.ToRawInfo())
+}
+
+// NewGraphFromOpenAPIv2 returns the reference graph of an OpenAPI v2 document.
+func NewGraphFromOpenAPIv2(document *openapi2.Document) (*Graph, error) {
+	return NewGraph(document.ToRawInfo())
+}
+
+// NewGraph returns the reference graph of an OpenAPI v2 or v3 document.
+func NewGraph(info *yaml.Node) (*Graph, error) {
+	root := info
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, errors.New("reference graphs require an OpenAPI v2 or v3 document")
+	}
+	g := &Graph{root: root, index: make(map[string]*Node)}
+	var sections [][]string
+	switch {
+	case compiler.MapHasKey(root, "openapi"):
+		sections = openAPI3Sections
+		g.schemasPrefix = "#/components/schemas/"
+		g.securityPrefix = "#/components/securitySchemes/"
+	case compiler.MapHasKey(root, "swagger"):
+		sections = openAPI2Sections
+		g.schemasPrefix = "#/definitions/"
+		g.securityPrefix = "#/securityDefinitions/"
+	default:
+		return nil, errors.New("reference graphs require an OpenAPI v2 or v3 document")
+	}
+	// Add all nodes before adding references so that references can refer to any node.
+	type entry struct {
+		node  *Node
+		value *yaml.Node
+	}
+	entries := make([]entry, 0)
+	add := func(node *Node, value *yaml.Node) {
+		g.nodes = append(g.nodes, node)
+		g.index[node.ID] = node
+		entries = append(entries, entry{node: node, value: value})
+	}
+	add(&Node{ID: "#", Kind: DocumentKind}, nil)
+	compiler.ForEachEntry(compiler.MapValueForKey(root, "paths"), func(path string, pathItem *yaml.Node) {
+		keys := []string{"paths", path}
+		add(&Node{ID: idForKeys(keys), Kind: PathKind, Name: path, Keys: keys}, pathItem)
+		for _, method := range methods {
+			if operation := compiler.MapValueForKey(pathItem, method); operation != nil {
+				keys := []string{"paths", path, method}
+				add(&Node{ID: idForKeys(keys), Kind: OperationKind, Name: strings.ToUpper(method) + " " + path, Keys: keys}, operation)
+			}
+		}
+	})
+	for _, section := range sections {
+		compiler.ForEachEntry(valueForKeys(root, section), func(name string, value *yaml.Node) {
+			keys := append(append([]string{}, section...), name)
+			add(&Node{ID: idForKeys(keys), Kind: section[len(section)-1], Name: name, Keys: keys, Component: true}, value)
+		})
+	}
+	for _, e := range entries {
+		switch e.node.Kind {
+		case DocumentKind:
+			g.addSecurityRefs(e.node, compiler.MapValueForKey(root, "security"), []string{"security"})
+		case PathKind:
+			// Operations are separate nodes.
+			for i := 0; e.value.Kind == yaml.MappingNode && i < len(e.value.Content)-1; i += 2 {
+				key, value := e.value.Content[i].Value, e.value.Content[i+1]
+				if key == "$ref" && value.Kind == yaml.ScalarNode {
+					g.addRef(e.node, value.Value, e.node.Keys)
+				} else if !compiler.StringArrayContainsValue(methods, key) {
+					g.addRefs(e.node, value, compiler.KeysWith(e.node.Keys, key))
+				}
+			}
+		case OperationKind:
+			g.addRefs(e.node, e.value, e.node.Keys)
+			g.addSecurityRefs(e.node, compiler.MapValueForKey(e.value, "security"), compiler.KeysWith(e.node.Keys, "security"))
+		default:
+			g.addRefs(e.node, e.value, e.node.Keys)
+		}
+	}
+	return g, nil
+}
+
+// Nodes returns the nodes of the graph in document order.
+func (g *Graph) Nodes() []*Node {
+	return g.nodes
+}
+
+// Node returns the node with the specified ID or nil if there is none.
+func (g *Graph) Node(id string) *Node {
+	return g.index[id]
+}
+
+// addRefs adds the references found in a value to a node.
+func (g *Graph) addRefs(node *Node, value *yaml.Node, keys []string) {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		for i, child := range value.Content {
+			g.addRefs(node, child, compiler.KeysWith(keys, strconv.Itoa(i)))
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(value.Content)-1; i += 2 {
+			key, child := value.Content[i].Value, value.Content[i+1]
+			switch {
+			case key == "$ref" && child.Kind == yaml.ScalarNode:
+				g.addRef(node, child.Value, keys)
+			case key == "discriminator" && child.Kind == yaml.MappingNode:
+				g.addMappingRefs(node, compiler.MapValueForKey(child, "mapping"), compiler.KeysWith(keys, key, "mapping"))
+			default:
+				g.addRefs(node, child, compiler.KeysWith(keys, key))
+			}
+		}
+	}
+}
+
+// addMappingRefs adds the references in a discriminator mapping, whose
+// values are references or the names of schemas.
+func (g *Graph) addMappingRefs(node *Node, mapping *yaml.Node, keys []string) {
+	compiler.ForEachEntry(mapping, func(name string, value *yaml.Node) {
+		ref := value.Value
+		if !strings.Contains(ref, "#") && !strings.Contains(ref, "/") {
+			ref = g.schemasPrefix + compiler.EscapePointerToken(ref)
+		}
+		g.addRef(node, ref, compiler.KeysWith(keys, name))
+	})
+}
+
+// addSecurityRefs adds references to the security schemes named in a list of security requirements.
+func (g *Graph) addSecurityRefs(node *Node, requirements *yaml.Node, keys []string) {
+	if requirements == nil || requirements.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, requirement := range requirements.Content {
+		compiler.ForEachEntry(requirement, func(name string, value *yaml.Node) {
+			g.addRef(node, g.securityPrefix+compiler.EscapePointerToken(name), compiler.KeysWith(keys, strconv.Itoa(i), name))
+		})
+	}
+}
+
+// addRef adds a reference to a node.
+func (g *Graph) addRef(node *Node, ref string, keys []string) {
+	reference := &Reference{Ref: ref, Keys: keys}
+	if !strings.HasPrefix(ref, "#") {
+		reference.External = true
+	} else {
+		reference.Target = g.targetForRef(ref)
+	}
+	node.Refs = append(node.Refs, reference)
+}
+
+// targetForRef returns the ID of the node that contains the fragment identified
+// by a local reference, or the empty string if the reference can't be resolved.
+func (g *Graph) targetForRef(ref string) string {
+	pointer := compiler.PointerForFragment(ref[1:])
+	if _, err := compiler.NodeForPointer(g.root, pointer); err != nil {
+		return ""
+	}
+	// Find the node with the longest ID that contains the fragment.
+	// Fragments that aren't in any other node are in the document node.
+	target := "#"
+	if pointer == "" {
+		return target
+	}
+	id := "#"
+	for _, token := range strings.Split(pointer[1:], "/") {
+		id += "/" + compiler.EscapePointerToken(compiler.UnescapePointerToken(token))
+		if _, ok := g.index[id]; ok {
+			target = id
+		}
+	}
+	return target
+}
+
+// idForKeys returns the ID of the node at a key path.
+func idForKeys(keys []string) string {
+	id := "#"
+	for _, key := range keys {
+		id += "/" + compiler.EscapePointerToken(key)
+	}
+	return id
+}
+
+// valueForKeys returns the value at a key path in a mapping node.
+func valueForKeys(node *yaml.Node, keys []string) *yaml.Node {
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		node = compiler.MapValueForKey(node, key)
+	}
+	return node
+}
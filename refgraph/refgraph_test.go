@@ -0,0 +1,278 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refgraph
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+func readOpenAPIv3(t *testing.T, filename string) *openapi3.Document {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return document
+}
+
+func readOpenAPIv2(t *testing.T, filename string) *openapi2.Document {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi2.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return document
+}
+
+func ids(nodes []*Node) []string {
+	result := make([]string, 0)
+	for _, node := range nodes {
+		result = append(result, node.ID)
+	}
+	return result
+}
+
+func messageSummaries(messages []*plugins.Message) []string {
+	result := make([]string, 0)
+	for _, message := range messages {
+		result = append(result, message.Level.String()+" "+message.Code+" "+strings.Join(message.Keys, "/"))
+	}
+	return result
+}
+
+func TestOpenAPIv3Graph(t *testing.T) {
+	graph, err := NewGraphFromOpenAPIv3(readOpenAPIv3(t, "../testdata/refgraph/openapi-v3.yaml"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	unused := ids(graph.Unused())
+	expected := []string{
+		"#/components/schemas/Tree",
+		"#/components/schemas/Unused",
+		"#/components/parameters/offset",
+		"#/components/securitySchemes/basic",
+	}
+	if !reflect.DeepEqual(unused, expected) {
+		t.Errorf("Unexpected unused components: %v", unused)
+	}
+	// References to fragments of components refer to the components.
+	owner := graph.Node("#/paths/~1owners~1{ownerId}/get")
+	if owner == nil || owner.Refs[0].Target != "#/components/schemas/Owner" {
+		t.Errorf("Unexpected references from %+v", owner)
+	}
+	// Discriminator mappings are references.
+	if !graph.Node("#/components/schemas/Kind").Component || len(graph.Node("#/components/schemas/Kind").Refs) != 4 {
+		t.Errorf("Unexpected references from Kind: %+v", graph.Node("#/components/schemas/Kind").Refs)
+	}
+	dangling := graph.Dangling()
+	if len(dangling) != 1 || dangling[0].Ref != "#/components/responses/NotFound" {
+		t.Errorf("Unexpected dangling references: %+v", dangling)
+	}
+	cycles := make([]string, 0)
+	for _, cycle := range graph.Cycles() {
+		cycles = append(cycles, strings.Join(ids(cycle), " "))
+	}
+	expected = []string{
+		"#/components/schemas/Pet #/components/schemas/Owner",
+		"#/components/schemas/Tree",
+	}
+	if !reflect.DeepEqual(cycles, expected) {
+		t.Errorf("Unexpected cycles: %v", cycles)
+	}
+	messages := messageSummaries(graph.Messages())
+	expected = []string{
+		"ERROR DANGLINGREFERENCE paths//owners/{ownerId}/get/responses/404",
+		"WARNING UNUSEDCOMPONENT components/schemas/Tree",
+		"WARNING UNUSEDCOMPONENT components/schemas/Unused",
+		"WARNING UNUSEDCOMPONENT components/parameters/offset",
+		"WARNING UNUSEDCOMPONENT components/securitySchemes/basic",
+		"INFO REFERENCECYCLE components/schemas/Pet",
+		"INFO REFERENCECYCLE components/schemas/Tree",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Unexpected messages: %v", messages)
+	}
+}
+
+func TestOpenAPIv2Graph(t *testing.T) {
+	graph, err := NewGraphFromOpenAPIv2(readOpenAPIv2(t, "../testdata/refgraph/swagger-v2.yaml"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	messages := messageSummaries(graph.Messages())
+	expected := []string{
+		"ERROR DANGLINGREFERENCE paths//pets/get/responses/default",
+		"WARNING UNUSEDCOMPONENT definitions/Error",
+		"WARNING UNUSEDCOMPONENT parameters/offset",
+		"WARNING UNUSEDCOMPONENT responses/Error",
+		"WARNING UNUSEDCOMPONENT securityDefinitions/basic",
+		"INFO REFERENCECYCLE definitions/Pet",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Unexpected messages: %v", messages)
+	}
+}
+
+func TestEscapedAndExternalReferences(t *testing.T) {
+	var info yaml.Node
+	err := yaml.Unmarshal([]byte(`
+openapi: 3.0.0
+paths:
+  /a:
+    $ref: 'paths.yaml#/a'
+  /b:
+    get:
+      responses:
+        "200":
+          $ref: '#/components/responses/a~1b'
+        "201":
+          $ref: '#/components/responses/with%20space'
+components:
+  responses:
+    a/b:
+      description: slash
+    with space:
+      description: space
+`), &info)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	graph, err := NewGraph(&info)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(graph.Unused()) != 0 || len(graph.Dangling()) != 0 {
+		t.Errorf("Unexpected results: %v %+v", ids(graph.Unused()), graph.Dangling())
+	}
+	path := graph.Node("#/paths/~1a")
+	if len(path.Refs) != 1 || !path.Refs[0].External {
+		t.Errorf("Expected an external reference: %+v", path.Refs)
+	}
+	if _, err := NewGraph(&yaml.Node{Kind: yaml.ScalarNode, Value: "x"}); err == nil {
+		t.Errorf("Expected an error for a document that is not OpenAPI")
+	}
+}
+
+func TestDOT(t *testing.T) {
+	graph, err := NewGraphFromOpenAPIv2(readOpenAPIv2(t, "../testdata/refgraph/swagger-v2.yaml"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	dot := string(graph.DOT())
+	for _, line := range []string{
+		`  "#/paths/~1pets/get" [label="GET /pets", shape=box];`,
+		`  "#/definitions/Error" [label="definitions/Error", color=gray, fontcolor=gray];`,
+		`  "#/paths/~1pets/get" -> "#/definitions/Pet";`,
+		`  "#/paths/~1pets/get" -> "missing #/responses/Missing" [style=dashed, color=red];`,
+	} {
+		if !strings.Contains(dot, line+"\n") {
+			t.Errorf("Missing %s in\n%s", line, dot)
+		}
+	}
+	if !strings.HasPrefix(dot, "digraph refs {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("Invalid graph:\n%s", dot)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	document := readOpenAPIv3(t, "../testdata/refgraph/openapi-v3.yaml")
+	pruned, removed, err := PruneOpenAPIv3(document)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []string{
+		"#/components/schemas/Tree",
+		"#/components/schemas/Unused",
+		"#/components/parameters/offset",
+	}
+	if !reflect.DeepEqual(removed, expected) {
+		t.Errorf("Unexpected removed components: %v", removed)
+	}
+	// The original document is unchanged.
+	if len(document.Components.Schemas.AdditionalProperties) != 8 {
+		t.Errorf("Original document was modified")
+	}
+	graph, err := NewGraphFromOpenAPIv3(pruned)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// Unused security schemes are kept.
+	if unused := ids(graph.Unused()); !reflect.DeepEqual(unused, []string{"#/components/securitySchemes/basic"}) {
+		t.Errorf("Unexpected unused components after pruning: %v", unused)
+	}
+
+	// Sections that become empty are removed.
+	prunedv2, removed, err := PruneOpenAPIv2(readOpenAPIv2(t, "../testdata/refgraph/swagger-v2.yaml"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(removed) != 3 || prunedv2.Responses != nil || len(prunedv2.Definitions.AdditionalProperties) != 1 {
+		t.Errorf("Unexpected pruning result: %v", removed)
+	}
+}
+
+func TestPruneKeepsReferencesFromKeptComponents(t *testing.T) {
+	var info yaml.Node
+	err := yaml.Unmarshal([]byte(`
+openapi: 3.0.0
+paths: {}
+components:
+  callbacks:
+    onEvent:
+      '{$request.body#/url}':
+        post:
+          requestBody:
+            $ref: '#/components/requestBodies/Event'
+          responses:
+            "200":
+              description: ok
+  requestBodies:
+    Event:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Event'
+  schemas:
+    Event:
+      type: object
+    Other:
+      type: object
+`), &info)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	_, removed, err := Prune(&info)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !reflect.DeepEqual(removed, []string{"#/components/schemas/Other"}) {
+		t.Errorf("Unexpected removed components: %v", removed)
+	}
+}
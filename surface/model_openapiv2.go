@@ -23,16 +23,22 @@ import (
 )
 
 type OpenAPI2Builder struct {
-	model *Model
+	model   *Model
+	session *compiler.Session
 }
 
 // NewModelFromOpenAPI2 builds a model of an API service for use in code generation.
 func NewModelFromOpenAPI2(document *openapiv2.Document, sourceName string) (*Model, error) {
-	return newOpenAPI2Builder().buildModel(document, sourceName)
+	return NewModelFromOpenAPI2WithSession(document, sourceName, compiler.DefaultSession())
 }
 
-func newOpenAPI2Builder() *OpenAPI2Builder {
-	return &OpenAPI2Builder{model: &Model{}}
+// NewModelFromOpenAPI2WithSession builds a model of an API service using a session to read referenced files.
+func NewModelFromOpenAPI2WithSession(document *openapiv2.Document, sourceName string, session *compiler.Session) (*Model, error) {
+	return newOpenAPI2Builder(session).buildModel(document, sourceName)
+}
+
+func newOpenAPI2Builder(session *compiler.Session) *OpenAPI2Builder {
+	return &OpenAPI2Builder{model: &Model{}, session: session}
 }
 
 // Fills the surface model with information from a parsed OpenAPI description. The surface model provides that information
@@ -122,14 +128,16 @@ func (b *OpenAPI2Builder) buildFromResponseDefinitions(responses *openapiv2.Resp
 // Builds all symbolic references. A symbolic reference is an URL to another OpenAPI description. We call "document.ResolveReferences"
 // inside that method. This has the same effect like: "gnostic --resolve-refs"
 func (b *OpenAPI2Builder) buildSymbolicReferences(document *openapiv2.Document, sourceName string) (err error) {
-	cache := compiler.GetInfoCache()
+	cache := b.session.GetInfoCache()
 	if len(cache) == 0 && sourceName != "" {
 		// Fills the compiler cache with all kind of references.
-		_, err = document.ResolveReferences(sourceName)
+		context := compiler.NewContextWithSession("$root", nil, b.session, nil)
+		context.BaseURI = sourceName
+		_, err = document.ResolveReferencesInContext(context)
 		if err != nil {
 			return err
 		}
-		cache = compiler.GetInfoCache()
+		cache = b.session.GetInfoCache()
 	}
 
 	for ref := range cache {
@@ -138,7 +146,7 @@ func (b *OpenAPI2Builder) buildSymbolicReferences(document *openapiv2.Document,
 		}
 	}
 	// Clear compiler cache for recursive calls
-	compiler.ClearInfoCache()
+	b.session.ClearInfoCache()
 	return nil
 }
 
@@ -25,15 +25,21 @@ import (
 type OpenAPI3Builder struct {
 	model    *Model
 	document *openapiv3.Document
+	session  *compiler.Session
 }
 
 // NewModelFromOpenAPIv3 builds a model of an API service for use in code generation.
 func NewModelFromOpenAPI3(document *openapiv3.Document, sourceName string) (*Model, error) {
-	return newOpenAPI3Builder(document).buildModel(document, sourceName)
+	return NewModelFromOpenAPI3WithSession(document, sourceName, compiler.DefaultSession())
 }
 
-func newOpenAPI3Builder(document *openapiv3.Document) *OpenAPI3Builder {
-	return &OpenAPI3Builder{model: &Model{}, document: document}
+// NewModelFromOpenAPI3WithSession builds a model of an API service using a session to read referenced files.
+func NewModelFromOpenAPI3WithSession(document *openapiv3.Document, sourceName string, session *compiler.Session) (*Model, error) {
+	return newOpenAPI3Builder(document, session).buildModel(document, sourceName)
+}
+
+func newOpenAPI3Builder(document *openapiv3.Document, session *compiler.Session) *OpenAPI3Builder {
+	return &OpenAPI3Builder{model: &Model{}, document: document, session: session}
 }
 
 // Fills the surface model with information from a parsed OpenAPI description. The surface model provides that information
@@ -106,14 +112,16 @@ func (b *OpenAPI3Builder) buildFromPaths(paths *openapiv3.Paths) {
 // Builds all symbolic references. A symbolic reference is an URL to another OpenAPI description. We call "document.ResolveReferences"
 // inside that method. This has the same effect like: "gnostic --resolve-refs"
 func (b *OpenAPI3Builder) buildSymbolicReferences(document *openapiv3.Document, sourceName string) (err error) {
-	cache := compiler.GetInfoCache()
+	cache := b.session.GetInfoCache()
 	if len(cache) == 0 && sourceName != "" {
 		// Fills the compiler cache with all kind of references.
-		_, err = document.ResolveReferences(sourceName)
+		context := compiler.NewContextWithSession("$root", nil, b.session, nil)
+		context.BaseURI = sourceName
+		_, err = document.ResolveReferencesInContext(context)
 		if err != nil {
 			return err
 		}
-		cache = compiler.GetInfoCache()
+		cache = b.session.GetInfoCache()
 	}
 
 	for ref := range cache {
@@ -122,7 +130,7 @@ func (b *OpenAPI3Builder) buildSymbolicReferences(document *openapiv3.Document,
 		}
 	}
 	// Clear compiler cache for recursive calls
-	compiler.ClearInfoCache()
+	b.session.ClearInfoCache()
 	return nil
 }
 